@@ -0,0 +1,170 @@
+package auth
+
+import (
+    "crypto/rand"
+    "crypto/sha256"
+    "crypto/subtle"
+    "encoding/hex"
+    "errors"
+    "log"
+    "net/http"
+    "strings"
+
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/storage"
+
+    "github.com/gin-gonic/gin"
+)
+
+const principalKey = "auth.principal"
+
+// Principal - владелец токена, которым подписан запрос, и его роль на момент запроса
+type Principal struct {
+    TokenID   string
+    Name      string
+    Scope     string
+    Role      string
+    UserID    string
+    TeamName  string
+    LeadTeams []string
+}
+
+// ForUser возвращает права токена пользователя: руководитель хотя бы одной команды - team_lead, иначе member
+func ForUser(access *models.UserAccess) Principal {
+    principal := Principal{
+        Scope:     models.ScopeUser,
+        Role:      models.RoleMember,
+        UserID:    access.UserID,
+        TeamName:  access.TeamName,
+        LeadTeams: access.LeadTeams,
+    }
+    if len(access.LeadTeams) > 0 {
+        principal.Role = models.RoleTeamLead
+    }
+    return principal
+}
+
+// Actor возвращает того, кто записывается в историю назначений: пользователя токена или имя токена
+func (p Principal) Actor() string {
+    if p.UserID != "" {
+        return p.UserID
+    }
+    return p.Name
+}
+
+// Authenticate проверяет заголовок Authorization: Bearer <секрет>. Секрет сверяется сначала с ADMIN_TOKEN,
+// затем по хэшу с выданными токенами; пустой adminToken не принимается никогда. Команды пользователя
+// читаются на каждый запрос, поэтому смена руководителя действует сразу.
+func Authenticate(tokens database.TokenRepository, access database.AccessRepository, adminToken string) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        secret, ok := bearerToken(c.GetHeader("Authorization"))
+        if !ok {
+            abort(c, http.StatusUnauthorized, models.CodeUnauthorized, "missing bearer token")
+            return
+        }
+
+        if adminToken != "" && subtle.ConstantTimeCompare([]byte(secret), []byte(adminToken)) == 1 {
+            c.Set(principalKey, Principal{Name: "admin", Scope: models.ScopeAdmin, Role: models.RoleOrgAdmin})
+            c.Next()
+            return
+        }
+
+        token, err := tokens.GetAPITokenByHash(HashSecret(secret))
+        if errors.Is(err, database.ErrNotFound) {
+            abort(c, http.StatusUnauthorized, models.CodeUnauthorized, "invalid or revoked token")
+            return
+        }
+        if err != nil {
+            log.Printf("Token lookup failed: %v", err)
+            abort(c, http.StatusInternalServerError, models.CodeInternalError, "internal server error")
+            return
+        }
+
+        principal := Principal{Scope: token.Scope, UserID: token.UserID}
+        switch token.Scope {
+        case models.ScopeAdmin:
+            principal.Role = models.RoleOrgAdmin
+        case models.ScopeBot:
+            principal.Role = models.RoleBot
+        default:
+            userAccess, err := access.GetUserAccess(token.UserID)
+            if errors.Is(err, database.ErrNotFound) {
+                abort(c, http.StatusUnauthorized, models.CodeUnauthorized, "token user no longer exists")
+                return
+            }
+            if err != nil {
+                log.Printf("User access lookup failed: %v", err)
+                abort(c, http.StatusInternalServerError, models.CodeInternalError, "internal server error")
+                return
+            }
+            principal = ForUser(userAccess)
+        }
+        principal.TokenID = token.TokenID
+        principal.Name = token.Name
+
+        c.Set(principalKey, principal)
+        c.Next()
+    }
+}
+
+// Require пропускает только тех, кому действие разрешено над всеми командами и пользователями;
+// проверки в пределах команды делают обработчики через Principal.Can
+func Require(action string) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        principal, ok := PrincipalFrom(c)
+        if !ok {
+            abort(c, http.StatusUnauthorized, models.CodeUnauthorized, "missing bearer token")
+            return
+        }
+        if !principal.Can(action, "", "") {
+            abort(c, http.StatusForbidden, models.CodeForbidden, "role "+principal.Role+" may not "+action)
+            return
+        }
+        c.Next()
+    }
+}
+
+func PrincipalFrom(c *gin.Context) (Principal, bool) {
+    value, exists := c.Get(principalKey)
+    if !exists {
+        return Principal{}, false
+    }
+    principal, ok := value.(Principal)
+    return principal, ok
+}
+
+func NewTokenID() string {
+    return "tok_" + randomHex(8)
+}
+
+// NewSecret возвращает секрет токена; он показывается один раз при выдаче
+func NewSecret() string {
+    return "prt_" + randomHex(32)
+}
+
+func HashSecret(secret string) string {
+    sum := sha256.Sum256([]byte(secret))
+    return hex.EncodeToString(sum[:])
+}
+
+func bearerToken(header string) (string, bool) {
+    scheme, token, found := strings.Cut(header, " ")
+    if !found || !strings.EqualFold(scheme, "Bearer") {
+        return "", false
+    }
+    token = strings.TrimSpace(token)
+    return token, token != ""
+}
+
+func abort(c *gin.Context, status int, code models.ErrorCodes, message string) {
+    var resp models.ErrorResponse
+    resp.Error.Code = code
+    resp.Error.Message = message
+    c.AbortWithStatusJSON(status, resp)
+}
+
+func randomHex(n int) string {
+    b := make([]byte, n)
+    rand.Read(b)
+    return hex.EncodeToString(b)
+}
@@ -0,0 +1,136 @@
+package auth
+
+import (
+    "slices"
+
+    "pr-reviewer/src/internal/domain/models"
+)
+
+// Действия, права на которые проверяются по роли
+const (
+    ActionTeamRead         = "team.read"
+    ActionTeamManage       = "team.manage"
+    ActionTeamAdmin        = "team.admin"
+    ActionUserRead         = "user.read"
+    ActionUserManage       = "user.manage"
+    ActionUserAvailability = "user.availability"
+    ActionReassign         = "pullRequest.reassign"
+    ActionReview           = "pullRequest.review"
+    ActionServiceRead      = "service.read"
+    ActionServiceAdmin     = "service.admin"
+)
+
+// actions задаёт порядок действий в ответе /auth/permissions
+var actions = []string{
+    ActionTeamRead,
+    ActionTeamManage,
+    ActionTeamAdmin,
+    ActionUserRead,
+    ActionUserManage,
+    ActionUserAvailability,
+    ActionReassign,
+    ActionReview,
+    ActionServiceRead,
+    ActionServiceAdmin,
+}
+
+// reach - над чем разрешено действие; флаги складываются
+type reach uint8
+
+const (
+    reachSelf reach = 1 << iota
+    reachOwnTeam
+    reachLedTeams
+    reachAll
+)
+
+var policy = map[string]map[string]reach{
+    models.RoleOrgAdmin: {
+        ActionTeamRead:         reachAll,
+        ActionTeamManage:       reachAll,
+        ActionTeamAdmin:        reachAll,
+        ActionUserRead:         reachAll,
+        ActionUserManage:       reachAll,
+        ActionUserAvailability: reachAll,
+        ActionReassign:         reachAll,
+        ActionReview:           reachAll,
+        ActionServiceRead:      reachAll,
+        ActionServiceAdmin:     reachAll,
+    },
+    models.RoleTeamLead: {
+        ActionTeamRead:         reachOwnTeam | reachLedTeams,
+        ActionTeamManage:       reachLedTeams,
+        ActionUserRead:         reachSelf | reachLedTeams,
+        ActionUserManage:       reachLedTeams,
+        ActionUserAvailability: reachSelf | reachLedTeams,
+        ActionReassign:         reachLedTeams,
+        ActionReview:           reachSelf,
+    },
+    models.RoleMember: {
+        ActionTeamRead:         reachOwnTeam,
+        ActionUserRead:         reachSelf,
+        ActionUserAvailability: reachSelf,
+        ActionReview:           reachSelf,
+    },
+    models.RoleBot: {
+        ActionTeamRead:    reachAll,
+        ActionUserRead:    reachAll,
+        ActionServiceRead: reachAll,
+    },
+}
+
+// Can сообщает, может ли владелец токена выполнить действие над командой teamName или пользователем userID;
+// пустые значения означают, что цель не задана, и тогда подходит только разрешение над всеми
+func (p Principal) Can(action, teamName, userID string) bool {
+    r := policy[p.Role][action]
+    switch {
+    case r&reachAll != 0:
+        return true
+    case r&reachSelf != 0 && userID != "" && userID == p.UserID:
+        return true
+    case r&reachOwnTeam != 0 && teamName != "" && teamName == p.TeamName:
+        return true
+    case r&reachLedTeams != 0 && teamName != "" && slices.Contains(p.LeadTeams, teamName):
+        return true
+    }
+    return false
+}
+
+// Permissions раскрывает роль в список разрешённых действий с конкретными командами
+func (p Principal) Permissions() models.PermissionsResponse {
+    response := models.PermissionsResponse{
+        Role:        p.Role,
+        TokenID:     p.TokenID,
+        UserID:      p.UserID,
+        TeamName:    p.TeamName,
+        LeadTeams:   p.LeadTeams,
+        Permissions: []models.Permission{},
+    }
+    if response.LeadTeams == nil {
+        response.LeadTeams = []string{}
+    }
+
+    for _, action := range actions {
+        r := policy[p.Role][action]
+        permission := models.Permission{Action: action}
+        if r&reachAll != 0 {
+            permission.All = true
+        } else {
+            permission.Self = r&reachSelf != 0 && p.UserID != ""
+            if r&reachOwnTeam != 0 && p.TeamName != "" {
+                permission.Teams = append(permission.Teams, p.TeamName)
+            }
+            if r&reachLedTeams != 0 {
+                for _, teamName := range p.LeadTeams {
+                    if !slices.Contains(permission.Teams, teamName) {
+                        permission.Teams = append(permission.Teams, teamName)
+                    }
+                }
+            }
+        }
+        if permission.All || permission.Self || len(permission.Teams) > 0 {
+            response.Permissions = append(response.Permissions, permission)
+        }
+    }
+    return response
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+    "net/http"
+    "pr-reviewer/src/internal/api/auth"
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/storage"
+
+    "github.com/gin-gonic/gin"
+)
+
+func requestActor(c *gin.Context) string {
+    if principal, ok := auth.PrincipalFrom(c); ok {
+        return principal.Actor()
+    }
+    return models.ActorAPI
+}
+
+// authorize отвечает 403, если роли вызывающего не хватает для действия над командой teamName или пользователем userID
+func authorize(c *gin.Context, action, teamName, userID string) bool {
+    principal, ok := auth.PrincipalFrom(c)
+    if ok && principal.Can(action, teamName, userID) {
+        return true
+    }
+    c.JSON(http.StatusForbidden, createErrorResponse(models.CodeForbidden, "role "+principal.Role+" may not "+action+" here"))
+    return false
+}
+
+// authorizeUser проверяет действие над пользователем с учётом его команды. Для неизвестного пользователя
+// команда пустая: пройдут только те, кому действие разрешено над всеми, и они получат обычный 404.
+func authorizeUser(c *gin.Context, access database.AccessRepository, action, userID string) bool {
+    var teamName string
+    userAccess, err := access.GetUserAccess(userID)
+    switch err {
+    case nil:
+        teamName = userAccess.TeamName
+    case database.ErrNotFound:
+    default:
+        c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        return false
+    }
+    return authorize(c, action, teamName, userID)
+}
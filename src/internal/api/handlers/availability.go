@@ -0,0 +1,199 @@
+package handlers
+
+import (
+    "net/http"
+    "pr-reviewer/src/internal/api/auth"
+    "strconv"
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/storage"
+
+    "github.com/gin-gonic/gin"
+)
+
+type AvailabilityHandler struct {
+    availability database.AvailabilityRepository
+    access       database.AccessRepository
+}
+
+func NewAvailabilityHandler(availability database.AvailabilityRepository, access database.AccessRepository) *AvailabilityHandler {
+    return &AvailabilityHandler{availability: availability, access: access}
+}
+
+// ListAvailability возвращает текущие и будущие периоды отсутствия пользователя, с include_past=true - все
+func (h *AvailabilityHandler) ListAvailability(c *gin.Context) {
+    userID := c.Query("user_id")
+    if userID == "" {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "user_id is required"))
+        return
+    }
+
+    if !authorizeUser(c, h.access, auth.ActionUserRead, userID) {
+        return
+    }
+
+    periods, err := h.availability.ListAvailability(userID, c.Query("include_past") == "true")
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"user_id": userID, "periods": periods})
+}
+
+func (h *AvailabilityHandler) GetAvailability(c *gin.Context) {
+    availabilityID, err := strconv.ParseInt(c.Query("availability_id"), 10, 64)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "availability_id must be an integer"))
+        return
+    }
+
+    period, err := h.availability.GetAvailability(availabilityID)
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+    if !authorizeUser(c, h.access, auth.ActionUserRead, period.UserID) {
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"period": period})
+}
+
+func (h *AvailabilityHandler) AddAvailability(c *gin.Context) {
+    var req models.CreateAvailabilityRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+
+    period := models.AvailabilityPeriod{
+        UserID:          req.UserID,
+        StartsAt:        req.StartsAt,
+        EndsAt:          req.EndsAt,
+        Reason:          req.Reason,
+        ReassignReviews: req.ReassignReviews,
+    }
+    if period.UserID == "" {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "user_id is required"))
+        return
+    }
+    if msg := validateAvailability(period); msg != "" {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, msg))
+        return
+    }
+    if !authorizeUser(c, h.access, auth.ActionUserAvailability, period.UserID) {
+        return
+    }
+
+    created, err := h.availability.CreateAvailability(period)
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "user not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{"period": created})
+}
+
+func (h *AvailabilityHandler) UpdateAvailability(c *gin.Context) {
+    var req models.UpdateAvailabilityRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+
+    period, err := h.availability.GetAvailability(req.AvailabilityID)
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+    if !authorizeUser(c, h.access, auth.ActionUserAvailability, period.UserID) {
+        return
+    }
+
+    if req.StartsAt != nil {
+        period.StartsAt = *req.StartsAt
+    }
+    if req.EndsAt != nil {
+        period.EndsAt = *req.EndsAt
+    }
+    if req.Reason != nil {
+        period.Reason = *req.Reason
+    }
+    if req.ReassignReviews != nil {
+        period.ReassignReviews = *req.ReassignReviews
+    }
+    if msg := validateAvailability(*period); msg != "" {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, msg))
+        return
+    }
+
+    updated, err := h.availability.UpdateAvailability(*period)
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"period": updated})
+}
+
+func (h *AvailabilityHandler) DeleteAvailability(c *gin.Context) {
+    var req models.DeleteAvailabilityRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+
+    period, err := h.availability.GetAvailability(req.AvailabilityID)
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+    if !authorizeUser(c, h.access, auth.ActionUserAvailability, period.UserID) {
+        return
+    }
+
+    if err := h.availability.DeleteAvailability(req.AvailabilityID); err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.Status(http.StatusNoContent)
+}
+
+func validateAvailability(period models.AvailabilityPeriod) string {
+    if period.StartsAt.IsZero() || period.EndsAt.IsZero() {
+        return "starts_at and ends_at are required"
+    }
+    if !period.EndsAt.After(period.StartsAt) {
+        return "ends_at must be after starts_at"
+    }
+    return ""
+}
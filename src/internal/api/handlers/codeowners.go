@@ -0,0 +1,104 @@
+package handlers
+
+import (
+    "net/http"
+    "pr-reviewer/src/internal/codeowners"
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/storage"
+
+    "github.com/gin-gonic/gin"
+)
+
+type CodeOwnersHandler struct {
+    store  database.CodeOwnersRepository
+    source *codeowners.Source
+}
+
+func NewCodeOwnersHandler(store database.CodeOwnersRepository, source *codeowners.Source) *CodeOwnersHandler {
+    return &CodeOwnersHandler{store: store, source: source}
+}
+
+// SetCodeOwners загружает CODEOWNERS репозитория; файл с ошибочными строками не принимается
+// @Summary Загрузить CODEOWNERS репозитория
+// @Tags CodeOwners
+// @Accept json
+// @Produce json
+// @Router /codeowners/set [post]
+func (h *CodeOwnersHandler) SetCodeOwners(c *gin.Context) {
+    var req models.SetCodeOwnersRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+    if req.Repository == "" {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "repository is required"))
+        return
+    }
+
+    ruleset, errs := codeowners.Parse(req.Content)
+    if len(errs) > 0 {
+        resp := createErrorResponse(models.CodeInvalidRequest, "CODEOWNERS has invalid lines")
+        resp.Error.Details = gin.H{"lines": errs}
+        c.JSON(http.StatusBadRequest, resp)
+        return
+    }
+
+    file, err := h.store.SetCodeOwners(req.Repository, req.Content)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"codeowners": file, "rules": len(ruleset.Rules)})
+}
+
+// GetCodeOwners возвращает действующий CODEOWNERS; с changed_files - ещё и их владельцев
+func (h *CodeOwnersHandler) GetCodeOwners(c *gin.Context) {
+    repository := c.Query("repository")
+    if repository == "" {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "repository is required"))
+        return
+    }
+
+    file, err := h.source.Load(repository)
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    response := gin.H{"codeowners": file}
+    if paths := c.QueryArray("changed_files"); len(paths) > 0 {
+        ruleset, _ := codeowners.Parse(file.Content)
+        owners := make(map[string][]string, len(paths))
+        for _, path := range paths {
+            owners[path] = ruleset.Owners(path)
+        }
+        response["owners"] = owners
+    }
+
+    c.JSON(http.StatusOK, response)
+}
+
+// DeleteCodeOwners удаляет загруженный CODEOWNERS; локальный файл из CODEOWNERS_FILES снова начинает действовать
+func (h *CodeOwnersHandler) DeleteCodeOwners(c *gin.Context) {
+    var req models.DeleteCodeOwnersRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+
+    if err := h.store.DeleteCodeOwners(req.Repository); err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.Status(http.StatusNoContent)
+}
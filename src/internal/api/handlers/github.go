@@ -0,0 +1,191 @@
+package handlers
+
+import (
+    "encoding/json"
+    "errors"
+    "io"
+    "net/http"
+    "pr-reviewer/src/internal/api/auth"
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/integrations/github"
+    "pr-reviewer/src/internal/storage"
+
+    "github.com/gin-gonic/gin"
+)
+
+const maxWebhookBodySize = 5 << 20
+
+type GitHubHandler struct {
+    prs    database.PullRequestRepository
+    github database.GitHubRepository
+    access database.AccessRepository
+    secret []byte
+}
+
+func NewGitHubHandler(prs database.PullRequestRepository, github database.GitHubRepository, access database.AccessRepository, secret string) *GitHubHandler {
+    return &GitHubHandler{prs: prs, github: github, access: access, secret: []byte(secret)}
+}
+
+func (h *GitHubHandler) SetLogin(c *gin.Context) {
+    var req models.SetGitHubLoginRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+
+    login := github.NormalizeLogin(req.GitHubLogin)
+    if req.UserID == "" || login == "" {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "user_id and github_login are required"))
+        return
+    }
+
+    if !authorizeUser(c, h.access, auth.ActionUserManage, req.UserID) {
+        return
+    }
+
+    mapping, err := h.github.SetGitHubLogin(req.UserID, login)
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"github_login": mapping})
+}
+
+func (h *GitHubHandler) RemoveLogin(c *gin.Context) {
+    var req models.RemoveGitHubLoginRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+
+    login := github.NormalizeLogin(req.GitHubLogin)
+    userID, err := h.github.GetUserIDByGitHubLogin(login)
+    if err != nil && err != database.ErrNotFound {
+        c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        return
+    }
+    if !authorizeUser(c, h.access, auth.ActionUserManage, userID) {
+        return
+    }
+
+    if err := h.github.RemoveGitHubLogin(login); err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.Status(http.StatusNoContent)
+}
+
+// Webhook принимает события GitHub и отражает жизненный цикл PR в сервисе
+// @Summary Вебхук GitHub (события pull_request)
+// @Tags Integrations
+// @Accept json
+// @Produce json
+// @Router /webhooks/github [post]
+func (h *GitHubHandler) Webhook(c *gin.Context) {
+    body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodySize))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+
+    if !github.VerifySignature(h.secret, body, c.GetHeader("X-Hub-Signature-256")) {
+        c.JSON(http.StatusUnauthorized, createErrorResponse(models.CodeBadSignature, "invalid webhook signature"))
+        return
+    }
+
+    switch c.GetHeader("X-GitHub-Event") {
+    case github.EventPing:
+        c.JSON(http.StatusOK, gin.H{"status": "pong"})
+        return
+    case github.EventPullRequest:
+    default:
+        c.JSON(http.StatusAccepted, gin.H{"status": "ignored", "reason": "unsupported event"})
+        return
+    }
+
+    var event github.PullRequestEvent
+    if err := json.Unmarshal(body, &event); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+
+    prID := event.PullRequestID()
+
+    var pr *models.PullRequest
+    switch event.Action {
+    case github.ActionOpened, github.ActionReadyForReview:
+        if event.PullRequest.Draft {
+            c.JSON(http.StatusAccepted, gin.H{"status": "ignored", "reason": "draft pull request"})
+            return
+        }
+        pr, err = h.create(event)
+        if err == database.ErrPRExists {
+            c.JSON(http.StatusOK, gin.H{"status": "ignored", "reason": "pull request already exists", "pull_request_id": prID})
+            return
+        }
+    case github.ActionReopened:
+        pr, err = h.prs.ReopenPullRequest(prID, models.ActorGitHub)
+        if err == database.ErrNotFound && !event.PullRequest.Draft {
+            pr, err = h.create(event)
+        }
+    case github.ActionClosed:
+        if event.PullRequest.Merged {
+            // GitHub уже смержил PR: политика мержа не проверяется, закрытый PR не переоткрывается
+            pr, err = h.prs.MergeExternally(prID, models.ActorGitHub)
+        } else {
+            pr, err = h.prs.ClosePullRequest(prID)
+        }
+    default:
+        c.JSON(http.StatusAccepted, gin.H{"status": "ignored", "reason": "unsupported action"})
+        return
+    }
+
+    if err != nil {
+        switch {
+        case err == errUnknownLogin:
+            c.JSON(http.StatusAccepted, gin.H{"status": "ignored", "reason": "author github login is not mapped to a user"})
+        case err == database.ErrNotFound:
+            c.JSON(http.StatusAccepted, gin.H{"status": "ignored", "reason": "pull request or author not found", "pull_request_id": prID})
+        case err == database.ErrPRMerged:
+            c.JSON(http.StatusConflict, createErrorResponse(models.CodePRMerged, "pull request is already merged"))
+        case errors.Is(err, database.ErrNoCandidate):
+            c.JSON(http.StatusConflict, noCandidateResponse(err, "not enough active reviewer candidates in team"))
+        default:
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"status": "processed", "action": event.Action, "pr": pr})
+}
+
+var errUnknownLogin = errors.New("unknown github login")
+
+func (h *GitHubHandler) create(event github.PullRequestEvent) (*models.PullRequest, error) {
+    authorID, err := h.github.GetUserIDByGitHubLogin(github.NormalizeLogin(event.PullRequest.User.Login))
+    if err == database.ErrNotFound {
+        return nil, errUnknownLogin
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    return h.prs.CreatePullRequest(models.CreatePRRequest{
+        PullRequestID:   event.PullRequestID(),
+        PullRequestName: event.PullRequest.Title,
+        AuthorID:        authorID,
+        Repository:      event.Repository.FullName,
+        Labels:          database.NormalizeTags(event.LabelNames()),
+    }, models.ActorGitHub)
+}
+
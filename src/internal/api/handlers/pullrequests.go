@@ -1,19 +1,32 @@
 package handlers
 
 import (
+    "errors"
     "net/http"
+    "strconv"
+    "time"
+    "pr-reviewer/src/internal/api/auth"
+    "pr-reviewer/src/internal/codeowners"
     "pr-reviewer/src/internal/storage"
     "pr-reviewer/src/internal/domain/models"
 
     "github.com/gin-gonic/gin"
 )
 
+const (
+    defaultPRListLimit = 50
+    maxPRListLimit     = 200
+)
+
 type PRHandler struct {
-    db *database.DB
+    prs        database.PullRequestRepository
+    access     database.AccessRepository
+    repos      database.RepoRepository
+    codeOwners *codeowners.Source
 }
 
-func NewPRHandler(db *database.DB) *PRHandler {
-    return &PRHandler{db: db}
+func NewPRHandler(prs database.PullRequestRepository, access database.AccessRepository, repos database.RepoRepository, codeOwners *codeowners.Source) *PRHandler {
+    return &PRHandler{prs: prs, access: access, repos: repos, codeOwners: codeOwners}
 }
 
 func (h *PRHandler) CreatePR(c *gin.Context) {
@@ -23,13 +36,28 @@ func (h *PRHandler) CreatePR(c *gin.Context) {
         return
     }
 
-    pr, err := h.db.CreatePullRequest(req)
+    labels, ok := normalizeTags(c, "labels", req.Labels)
+    if !ok {
+        return
+    }
+    req.Labels = labels
+
+    owners, err := h.codeOwners.Owners(req.Repository, req.ChangedFiles)
     if err != nil {
-        switch err {
-        case database.ErrPRExists:
+        c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        return
+    }
+    req.CodeOwners = owners
+
+    pr, err := h.prs.CreatePullRequest(req, requestActor(c))
+    if err != nil {
+        switch {
+        case err == database.ErrPRExists:
             c.JSON(http.StatusConflict, createErrorResponse(models.CodePRExists, "PR id already exists"))
-        case database.ErrNotFound:
+        case err == database.ErrNotFound:
             c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        case errors.Is(err, database.ErrNoCandidate):
+            c.JSON(http.StatusConflict, noCandidateResponse(err, "not enough active reviewer candidates in team"))
         default:
             c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
         }
@@ -46,11 +74,19 @@ func (h *PRHandler) MergePR(c *gin.Context) {
         return
     }
 
-    pr, err := h.db.MergePullRequest(req.PullRequestID)
+    pr, err := h.prs.MergePullRequest(req.PullRequestID, req.Force, requestActor(c))
     if err != nil {
-        if err == database.ErrNotFound {
+        var blocked *database.MergeBlockedError
+        switch {
+        case err == database.ErrNotFound:
             c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
-        } else {
+        case err == database.ErrPRClosed:
+            c.JSON(http.StatusConflict, createErrorResponse(models.CodePRClosed, "cannot merge closed PR, reopen it first"))
+        case errors.As(err, &blocked):
+            resp := createErrorResponse(models.CodeNotApproved, "PR does not satisfy the team merge policy")
+            resp.Error.Details = blocked
+            c.JSON(http.StatusConflict, resp)
+        default:
             c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
         }
         return
@@ -59,24 +95,141 @@ func (h *PRHandler) MergePR(c *gin.Context) {
     c.JSON(http.StatusOK, gin.H{"pr": pr})
 }
 
-func (h *PRHandler) Reassign(c *gin.Context) {
-    var req models.ReassignRequest
+func (h *PRHandler) ClosePR(c *gin.Context) {
+    var req models.ClosePRRequest
     if err := c.ShouldBindJSON(&req); err != nil {
         c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
         return
     }
 
-    pr, newUserID, err := h.db.ReassignReviewer(req.PullRequestID, req.OldUserID)
+    pr, err := h.prs.ClosePullRequest(req.PullRequestID)
     if err != nil {
         switch err {
         case database.ErrNotFound:
             c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
         case database.ErrPRMerged:
-            c.JSON(http.StatusConflict, createErrorResponse(models.CodePRMerged, "cannot reassign on merged PR"))
+            c.JSON(http.StatusConflict, createErrorResponse(models.CodePRMerged, "cannot close merged PR"))
+        default:
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"pr": pr})
+}
+
+func (h *PRHandler) ReopenPR(c *gin.Context) {
+    var req models.ReopenPRRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+
+    pr, err := h.prs.ReopenPullRequest(req.PullRequestID, requestActor(c))
+    if err != nil {
+        switch {
+        case err == database.ErrNotFound:
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        case err == database.ErrPRMerged:
+            c.JSON(http.StatusConflict, createErrorResponse(models.CodePRMerged, "cannot reopen merged PR"))
+        case errors.Is(err, database.ErrNoCandidate):
+            c.JSON(http.StatusConflict, noCandidateResponse(err, "not enough active reviewer candidates to reopen PR"))
+        default:
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"pr": pr})
+}
+
+func (h *PRHandler) SubmitReview(c *gin.Context) {
+    var req models.SubmitReviewRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+
+    switch req.Decision {
+    case models.ReviewApproved, models.ReviewChangesRequested, models.ReviewCommented:
+    default:
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "decision must be APPROVED, CHANGES_REQUESTED or COMMENTED"))
+        return
+    }
+    if !authorize(c, auth.ActionReview, "", req.ReviewerID) {
+        return
+    }
+
+    pr, err := h.prs.SubmitReview(req.PullRequestID, req.ReviewerID, req.Decision)
+    if err != nil {
+        switch err {
+        case database.ErrNotFound:
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        case database.ErrPRMerged:
+            c.JSON(http.StatusConflict, createErrorResponse(models.CodePRMerged, "cannot review merged PR"))
+        case database.ErrPRClosed:
+            c.JSON(http.StatusConflict, createErrorResponse(models.CodePRClosed, "cannot review closed PR"))
         case database.ErrNotAssigned:
             c.JSON(http.StatusConflict, createErrorResponse(models.CodeNotAssigned, "reviewer is not assigned to this PR"))
-        case database.ErrNoCandidate:
-            c.JSON(http.StatusConflict, createErrorResponse(models.CodeNoCandidate, "no active replacement candidate in team"))
+        default:
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"pr": pr})
+}
+
+func (h *PRHandler) Reassign(c *gin.Context) {
+    var req models.ReassignRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+
+    // переназначать ревьюверов может руководитель команды, которая ревьюит PR:
+    // владельца репозитория, а если репозиторий не заведён - команды автора
+    var authorID, teamName string
+    current, err := h.prs.GetPullRequest(req.PullRequestID)
+    switch err {
+    case nil:
+        authorID = current.AuthorID
+        if current.Repository != "" {
+            repo, err := h.repos.GetRepository(current.Repository)
+            if err != nil && err != database.ErrNotFound {
+                c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+                return
+            }
+            if repo != nil {
+                teamName = repo.TeamName
+            }
+        }
+    case database.ErrNotFound:
+    default:
+        c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        return
+    }
+    if teamName != "" {
+        if !authorize(c, auth.ActionReassign, teamName, "") {
+            return
+        }
+    } else if !authorizeUser(c, h.access, auth.ActionReassign, authorID) {
+        return
+    }
+
+    pr, newUserID, err := h.prs.ReassignReviewer(req.PullRequestID, req.OldUserID, requestActor(c))
+    if err != nil {
+        switch {
+        case err == database.ErrNotFound:
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        case err == database.ErrPRMerged:
+            c.JSON(http.StatusConflict, createErrorResponse(models.CodePRMerged, "cannot reassign on merged PR"))
+        case err == database.ErrPRClosed:
+            c.JSON(http.StatusConflict, createErrorResponse(models.CodePRClosed, "cannot reassign on closed PR"))
+        case err == database.ErrNotAssigned:
+            c.JSON(http.StatusConflict, createErrorResponse(models.CodeNotAssigned, "reviewer is not assigned to this PR"))
+        case errors.Is(err, database.ErrNoCandidate):
+            c.JSON(http.StatusConflict, noCandidateResponse(err, "no active replacement candidate in team"))
         default:
             c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
         }
@@ -87,4 +240,119 @@ func (h *PRHandler) Reassign(c *gin.Context) {
         "pr":          pr,
         "replaced_by": newUserID,
     })
-}
\ No newline at end of file
+}
+
+func (h *PRHandler) GetPR(c *gin.Context) {
+    prID := c.Query("pull_request_id")
+    if prID == "" {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "pull_request_id is required"))
+        return
+    }
+
+    pr, err := h.prs.GetPullRequest(prID)
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"pr": pr})
+}
+
+// ListPRs возвращает PR по фильтрам от новых к старым; следующая страница запрашивается с cursor=next_cursor
+func (h *PRHandler) ListPRs(c *gin.Context) {
+    filter := models.PullRequestFilter{
+        Status:     c.Query("status"),
+        AuthorID:   c.Query("author_id"),
+        ReviewerID: c.Query("reviewer_id"),
+        TeamName:   c.Query("team_name"),
+    }
+    switch filter.Status {
+    case "", "OPEN", "MERGED", "CLOSED":
+    default:
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "status must be OPEN, MERGED or CLOSED"))
+        return
+    }
+
+    ranges := []struct {
+        param string
+        dest  **time.Time
+    }{
+        {"created_from", &filter.CreatedFrom},
+        {"created_to", &filter.CreatedTo},
+        {"merged_from", &filter.MergedFrom},
+        {"merged_to", &filter.MergedTo},
+    }
+    for _, r := range ranges {
+        value := c.Query(r.param)
+        if value == "" {
+            continue
+        }
+        t, err := time.Parse(time.RFC3339, value)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, r.param+" must be an RFC 3339 timestamp"))
+            return
+        }
+        *r.dest = &t
+    }
+
+    limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultPRListLimit)))
+    if err != nil || limit <= 0 {
+        limit = defaultPRListLimit
+    }
+    if limit > maxPRListLimit {
+        limit = maxPRListLimit
+    }
+    filter.Limit = limit
+
+    if cursor := c.Query("cursor"); cursor != "" {
+        after, err := database.DecodePullRequestCursor(cursor)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "cursor is invalid"))
+            return
+        }
+        filter.After = after
+    }
+
+    page, err := h.prs.ListPullRequests(filter)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        return
+    }
+
+    c.JSON(http.StatusOK, page)
+}
+
+// GetHistory возвращает полный журнал назначений ревьюверов PR
+func (h *PRHandler) GetHistory(c *gin.Context) {
+    prID := c.Query("pull_request_id")
+    if prID == "" {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "pull_request_id is required"))
+        return
+    }
+
+    history, err := h.prs.GetPullRequestHistory(prID)
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, history)
+}
+
+// noCandidateResponse добавляет к NO_CANDIDATE причины, по которым участники команды не подошли
+func noCandidateResponse(err error, message string) models.ErrorResponse {
+    resp := createErrorResponse(models.CodeNoCandidate, message)
+    var noCandidate *database.NoCandidateError
+    if errors.As(err, &noCandidate) {
+        resp.Error.Details = noCandidate
+    }
+    return resp
+}
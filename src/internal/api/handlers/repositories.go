@@ -0,0 +1,149 @@
+package handlers
+
+import (
+    "fmt"
+    "net/http"
+    "pr-reviewer/src/internal/api/auth"
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/storage"
+
+    "github.com/gin-gonic/gin"
+)
+
+type RepositoryHandler struct {
+    repos database.RepoRepository
+}
+
+func NewRepositoryHandler(repos database.RepoRepository) *RepositoryHandler {
+    return &RepositoryHandler{repos: repos}
+}
+
+// SetRepository заводит репозиторий за командой или меняет его настройки.
+// Передать репозиторий другой команде может только тот, кто администрирует обе.
+// @Summary Завести репозиторий или изменить его настройки
+// @Tags Repositories
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Repository
+// @Router /repository/set [post]
+func (h *RepositoryHandler) SetRepository(c *gin.Context) {
+    var req models.SetRepositoryRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+    if req.Repository == "" || req.TeamName == "" {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "repository and team_name are required"))
+        return
+    }
+    if req.ReviewersCount != nil && (*req.ReviewersCount < 0 || *req.ReviewersCount > maxReviewersCount) {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, fmt.Sprintf("reviewers_count must be between 0 and %d", maxReviewersCount)))
+        return
+    }
+    if req.RequiredApprovals != nil && (*req.RequiredApprovals < 0 || *req.RequiredApprovals > maxReviewersCount) {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, fmt.Sprintf("required_approvals must be between 0 and %d", maxReviewersCount)))
+        return
+    }
+
+    // руководитель меняет настройки репозиториев своей команды, владельца меняет администратор
+    current, err := h.repos.GetRepository(req.Repository)
+    switch {
+    case err == nil && current.TeamName == req.TeamName:
+        if !authorize(c, auth.ActionTeamManage, req.TeamName, "") {
+            return
+        }
+    case err == nil:
+        if !authorize(c, auth.ActionTeamAdmin, current.TeamName, "") || !authorize(c, auth.ActionTeamAdmin, req.TeamName, "") {
+            return
+        }
+    case err == database.ErrNotFound:
+        if !authorize(c, auth.ActionTeamAdmin, req.TeamName, "") {
+            return
+        }
+    default:
+        c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        return
+    }
+
+    repo, err := h.repos.SetRepository(models.Repository{
+        Repository:        req.Repository,
+        TeamName:          req.TeamName,
+        ReviewersCount:    req.ReviewersCount,
+        RequiredApprovals: req.RequiredApprovals,
+    })
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "team not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"repository": repo})
+}
+
+func (h *RepositoryHandler) GetRepository(c *gin.Context) {
+    name := c.Query("repository")
+    if name == "" {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "repository is required"))
+        return
+    }
+
+    repo, err := h.repos.GetRepository(name)
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"repository": repo})
+}
+
+// ListRepositories возвращает заведённые репозитории, с team_name - только репозитории команды
+func (h *RepositoryHandler) ListRepositories(c *gin.Context) {
+    repos, err := h.repos.ListRepositories(c.Query("team_name"))
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"repositories": repos})
+}
+
+// DeleteRepository удаляет репозиторий; его PR снова ревьюит команда автора
+func (h *RepositoryHandler) DeleteRepository(c *gin.Context) {
+    var req models.DeleteRepositoryRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+
+    var teamName string
+    current, err := h.repos.GetRepository(req.Repository)
+    switch err {
+    case nil:
+        teamName = current.TeamName
+    case database.ErrNotFound:
+    default:
+        c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        return
+    }
+    if !authorize(c, auth.ActionTeamAdmin, teamName, "") {
+        return
+    }
+
+    if err := h.repos.DeleteRepository(req.Repository); err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.Status(http.StatusNoContent)
+}
@@ -10,11 +10,11 @@ import (
 )
 
 type StatsHandler struct {
-    db *database.DB
+    stats database.StatsRepository
 }
 
-func NewStatsHandler(db *database.DB) *StatsHandler {
-    return &StatsHandler{db: db}
+func NewStatsHandler(stats database.StatsRepository) *StatsHandler {
+    return &StatsHandler{stats: stats}
 }
 
 // GetSystemStats возвращает общую статистику системы
@@ -24,13 +24,13 @@ func NewStatsHandler(db *database.DB) *StatsHandler {
 // @Success 200 {object} models.StatsResponse
 // @Router /stats/system [get]
 func (h *StatsHandler) GetSystemStats(c *gin.Context) {
-    systemStats, err := h.db.GetSystemStats()
+    systemStats, err := h.stats.GetSystemStats()
     if err != nil {
         c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
         return
     }
 
-    topReviewers, err := h.db.GetTopReviewers(5)
+    topReviewers, err := h.stats.GetTopReviewers(5)
     if err != nil {
         c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
         return
@@ -51,7 +51,7 @@ func (h *StatsHandler) GetSystemStats(c *gin.Context) {
 // @Success 200 {object} models.StatsResponse
 // @Router /stats/users [get]
 func (h *StatsHandler) GetUserStats(c *gin.Context) {
-    userStats, err := h.db.GetUserStats()
+    userStats, err := h.stats.GetUserStats()
     if err != nil {
         c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
         return
@@ -71,7 +71,7 @@ func (h *StatsHandler) GetUserStats(c *gin.Context) {
 // @Success 200 {object} models.StatsResponse
 // @Router /stats/prs [get]
 func (h *StatsHandler) GetPRStats(c *gin.Context) {
-    prStats, err := h.db.GetPRStats()
+    prStats, err := h.stats.GetPRStats()
     if err != nil {
         c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
         return
@@ -101,7 +101,7 @@ func (h *StatsHandler) GetTopReviewers(c *gin.Context) {
         limit = 50
     }
 
-    topReviewers, err := h.db.GetTopReviewers(limit)
+    topReviewers, err := h.stats.GetTopReviewers(limit)
     if err != nil {
         c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
         return
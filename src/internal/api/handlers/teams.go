@@ -1,7 +1,11 @@
 package handlers
 
 import (
+    "errors"
+    "fmt"
     "net/http"
+    "pr-reviewer/src/internal/api/auth"
+    "pr-reviewer/src/internal/domain/assignment"
     "pr-reviewer/src/internal/storage"
     "pr-reviewer/src/internal/domain/models"
 
@@ -9,11 +13,12 @@ import (
 )
 
 type TeamHandler struct {
-    db *database.DB
+    teams  database.TeamRepository
+    access database.AccessRepository
 }
 
-func NewTeamHandler(db *database.DB) *TeamHandler {
-    return &TeamHandler{db: db}
+func NewTeamHandler(teams database.TeamRepository, access database.AccessRepository) *TeamHandler {
+    return &TeamHandler{teams: teams, access: access}
 }
 
 func (h *TeamHandler) AddTeam(c *gin.Context) {
@@ -23,11 +28,25 @@ func (h *TeamHandler) AddTeam(c *gin.Context) {
         return
     }
 
-    err := h.db.CreateTeam(team)
+    if !authorize(c, auth.ActionTeamAdmin, team.TeamName, "") {
+        return
+    }
+
+    if _, err := assignment.Get(team.AssignmentStrategy); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "unknown assignment_strategy"))
+        return
+    }
+    if !validMemberLevels(c, team.Members) {
+        return
+    }
+
+    err := h.teams.CreateTeam(team)
     if err != nil {
-        switch err {
-        case database.ErrTeamExists:
+        switch {
+        case err == database.ErrTeamExists:
             c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeTeamExists, "team_name already exists"))
+        case errors.Is(err, database.ErrUserInOtherTeam):
+            c.JSON(http.StatusConflict, memberConflictResponse(err))
         default:
             c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
         }
@@ -44,7 +63,11 @@ func (h *TeamHandler) GetTeam(c *gin.Context) {
         return
     }
 
-    team, err := h.db.GetTeam(teamName)
+    if !authorize(c, auth.ActionTeamRead, teamName, "") {
+        return
+    }
+
+    team, err := h.teams.GetTeam(teamName)
     if err != nil {
         if err == database.ErrNotFound {
             c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
@@ -57,9 +80,446 @@ func (h *TeamHandler) GetTeam(c *gin.Context) {
     c.JSON(http.StatusOK, team)
 }
 
+const maxReviewersCount = 10
+
+func (h *TeamHandler) GetSettings(c *gin.Context) {
+    teamName := c.Query("team_name")
+    if teamName == "" {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "team_name is required"))
+        return
+    }
+    if !authorize(c, auth.ActionTeamRead, teamName, "") {
+        return
+    }
+
+    settings, err := h.teams.GetTeamSettings(teamName)
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+func (h *TeamHandler) UpdateSettings(c *gin.Context) {
+    var req models.UpdateTeamSettingsRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+    if req.TeamName == "" {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "team_name is required"))
+        return
+    }
+    if !authorize(c, auth.ActionTeamManage, req.TeamName, "") {
+        return
+    }
+
+    settings, err := h.teams.GetTeamSettings(req.TeamName)
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    if req.ReviewersCount != nil {
+        settings.ReviewersCount = *req.ReviewersCount
+    }
+    if req.MinReviewersCount != nil {
+        settings.MinReviewersCount = *req.MinReviewersCount
+    }
+    if req.AssignmentStrategy != nil {
+        settings.AssignmentStrategy = *req.AssignmentStrategy
+    }
+    if req.RequiredApprovals != nil {
+        settings.RequiredApprovals = *req.RequiredApprovals
+    }
+    if req.MaxOpenReviews != nil {
+        settings.MaxOpenReviews = *req.MaxOpenReviews
+    }
+    if req.ReviewSLASeconds != nil {
+        settings.ReviewSLASeconds = *req.ReviewSLASeconds
+    }
+    if req.SLAAutoReassign != nil {
+        settings.SLAAutoReassign = *req.SLAAutoReassign
+    }
+    if req.FallbackTeams != nil {
+        settings.FallbackTeams = *req.FallbackTeams
+    }
+    if req.MinReviewerLevel != nil {
+        settings.MinReviewerLevel = *req.MinReviewerLevel
+    }
+    if req.ShadowReviewers != nil {
+        settings.ShadowReviewers = *req.ShadowReviewers
+    }
+
+    if settings.ReviewersCount < 0 || settings.ReviewersCount > maxReviewersCount {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, fmt.Sprintf("reviewers_count must be between 0 and %d", maxReviewersCount)))
+        return
+    }
+    if settings.MinReviewersCount < 0 || settings.MinReviewersCount > settings.ReviewersCount {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "min_reviewers_count must be between 0 and reviewers_count"))
+        return
+    }
+    if settings.RequiredApprovals < 0 || settings.RequiredApprovals > maxReviewersCount {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, fmt.Sprintf("required_approvals must be between 0 and %d", maxReviewersCount)))
+        return
+    }
+    if settings.MaxOpenReviews < 0 {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "max_open_reviews must not be negative"))
+        return
+    }
+    if settings.ReviewSLASeconds < 0 {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "review_sla_seconds must not be negative"))
+        return
+    }
+    if _, err := assignment.Get(settings.AssignmentStrategy); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "unknown assignment_strategy"))
+        return
+    }
+    if settings.MinReviewerLevel != "" && !assignment.ValidLevel(settings.MinReviewerLevel) {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "unknown min_reviewer_level"))
+        return
+    }
+    if settings.ShadowReviewers < 0 || settings.ShadowReviewers > maxReviewersCount {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, fmt.Sprintf("shadow_reviewers must be between 0 and %d", maxReviewersCount)))
+        return
+    }
+    seen := make(map[string]bool, len(settings.FallbackTeams))
+    for _, fallback := range settings.FallbackTeams {
+        if fallback == "" || fallback == settings.TeamName || seen[fallback] {
+            c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "fallback_teams must be distinct other teams"))
+            return
+        }
+        seen[fallback] = true
+    }
+
+    settings, err = h.teams.UpdateTeamSettings(*settings)
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+// DeactivateUsers деактивирует участников команды и переназначает их открытые ревью одной транзакцией
+// @Summary Массовая деактивация участников команды
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.DeactivateUsersResponse
+// @Router /team/deactivateUsers [post]
+func (h *TeamHandler) DeactivateUsers(c *gin.Context) {
+    var req models.DeactivateUsersRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+    if req.TeamName == "" || len(req.UserIDs) == 0 {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "team_name and user_ids are required"))
+        return
+    }
+    if !authorize(c, auth.ActionTeamManage, req.TeamName, "") {
+        return
+    }
+
+    report, err := h.teams.DeactivateTeamUsers(req.TeamName, req.UserIDs, requestActor(c))
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "team or some of the users not found in team"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, report)
+}
+
+// AddMembers добавляет пользователей в команду; забрать пользователя из другой команды можно только с allow_move
+// @Summary Добавление участников в команду
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.TeamMembershipResponse
+// @Router /team/addMembers [post]
+func (h *TeamHandler) AddMembers(c *gin.Context) {
+    var req models.AddTeamMembersRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+    if req.TeamName == "" || len(req.Members) == 0 {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "team_name and members are required"))
+        return
+    }
+    for _, member := range req.Members {
+        if member.UserID == "" {
+            c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "user_id is required for every member"))
+            return
+        }
+    }
+    if !validMemberLevels(c, req.Members) {
+        return
+    }
+    if !h.authorizeMembers(c, req) {
+        return
+    }
+
+    report, err := h.teams.AddTeamMembers(req.TeamName, req.Members, req.AllowMove, requestActor(c))
+    if err != nil {
+        switch {
+        case err == database.ErrNotFound:
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        case errors.Is(err, database.ErrUserInOtherTeam):
+            c.JSON(http.StatusConflict, memberConflictResponse(err))
+        default:
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, report)
+}
+
+// RemoveMembers оставляет пользователей без команды и переназначает их открытые ревью
+// @Summary Удаление участников из команды
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.TeamMembershipResponse
+// @Router /team/removeMembers [post]
+func (h *TeamHandler) RemoveMembers(c *gin.Context) {
+    var req models.RemoveTeamMembersRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+    if req.TeamName == "" || len(req.UserIDs) == 0 {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "team_name and user_ids are required"))
+        return
+    }
+    if !authorize(c, auth.ActionTeamManage, req.TeamName, "") {
+        return
+    }
+
+    report, err := h.teams.RemoveTeamMembers(req.TeamName, req.UserIDs, requestActor(c))
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "team or some of the users not found in team"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, report)
+}
+
+// Rename переименовывает команду вместе с её участниками и настройками
+// @Summary Переименование команды
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Router /team/rename [post]
+func (h *TeamHandler) Rename(c *gin.Context) {
+    var req models.RenameTeamRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+    if req.TeamName == "" || req.NewTeamName == "" {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "team_name and new_team_name are required"))
+        return
+    }
+    if !authorize(c, auth.ActionTeamAdmin, req.TeamName, "") {
+        return
+    }
+
+    if err := h.teams.RenameTeam(req.TeamName, req.NewTeamName); err != nil {
+        switch err {
+        case database.ErrNotFound:
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        case database.ErrTeamExists:
+            c.JSON(http.StatusConflict, createErrorResponse(models.CodeTeamExists, "new_team_name already exists"))
+        default:
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"team_name": req.NewTeamName, "previous_team_name": req.TeamName})
+}
+
+// Delete удаляет команду; участников непустой команды нужно убрать явно через remove_members
+// @Summary Удаление команды
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.TeamMembershipResponse
+// @Router /team/delete [post]
+func (h *TeamHandler) Delete(c *gin.Context) {
+    var req models.DeleteTeamRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+    if req.TeamName == "" {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "team_name is required"))
+        return
+    }
+
+    if !authorize(c, auth.ActionTeamAdmin, req.TeamName, "") {
+        return
+    }
+
+    report, err := h.teams.DeleteTeam(req.TeamName, req.RemoveMembers, requestActor(c))
+    if err != nil {
+        switch err {
+        case database.ErrNotFound:
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        case database.ErrTeamNotEmpty:
+            c.JSON(http.StatusConflict, createErrorResponse(models.CodeTeamNotEmpty, "team has members, pass remove_members to delete it anyway"))
+        default:
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, report)
+}
+
+// authorizeMembers требует права на команду и, для переводимых пользователей, на их текущие команды:
+// руководитель не может забрать участника чужой команды
+func (h *TeamHandler) authorizeMembers(c *gin.Context, req models.AddTeamMembersRequest) bool {
+    if !authorize(c, auth.ActionTeamManage, req.TeamName, "") {
+        return false
+    }
+    for _, member := range req.Members {
+        userAccess, err := h.access.GetUserAccess(member.UserID)
+        if err == database.ErrNotFound {
+            continue
+        }
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+            return false
+        }
+        if userAccess.TeamName != "" && userAccess.TeamName != req.TeamName &&
+            !authorize(c, auth.ActionTeamManage, userAccess.TeamName, "") {
+            return false
+        }
+    }
+    return true
+}
+
+// AddLead назначает руководителя команды; он получает права team_lead на её участников
+// @Summary Назначение руководителя команды
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.TeamLeadsResponse
+// @Router /team/addLead [post]
+func (h *TeamHandler) AddLead(c *gin.Context) {
+    h.changeLead(c, h.access.AddTeamLead)
+}
+
+// RemoveLead снимает руководителя команды
+// @Summary Снятие руководителя команды
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.TeamLeadsResponse
+// @Router /team/removeLead [post]
+func (h *TeamHandler) RemoveLead(c *gin.Context) {
+    h.changeLead(c, h.access.RemoveTeamLead)
+}
+
+func (h *TeamHandler) changeLead(c *gin.Context, change func(teamName, userID string) (*models.TeamLeadsResponse, error)) {
+    var req models.TeamLeadRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+    if req.TeamName == "" || req.UserID == "" {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "team_name and user_id are required"))
+        return
+    }
+    if !authorize(c, auth.ActionTeamAdmin, req.TeamName, "") {
+        return
+    }
+
+    leads, err := change(req.TeamName, req.UserID)
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, leads)
+}
+
+func (h *TeamHandler) GetLeads(c *gin.Context) {
+    teamName := c.Query("team_name")
+    if teamName == "" {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "team_name is required"))
+        return
+    }
+    if !authorize(c, auth.ActionTeamRead, teamName, "") {
+        return
+    }
+
+    leads, err := h.access.ListTeamLeads(teamName)
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, leads)
+}
+
+// memberConflictResponse добавляет к USER_IN_OTHER_TEAM список пользователей и их текущих команд
+func memberConflictResponse(err error) models.ErrorResponse {
+    resp := createErrorResponse(models.CodeUserInOtherTeam, "some users already belong to another team, use /team/addMembers with allow_move")
+    var conflict *database.MemberConflictError
+    if errors.As(err, &conflict) {
+        resp.Error.Details = conflict
+    }
+    return resp
+}
+
 func createErrorResponse(code models.ErrorCodes, message string) models.ErrorResponse {
     var resp models.ErrorResponse
     resp.Error.Code = code
     resp.Error.Message = message
     return resp
+}
+
+// validMemberLevels отвечает 400, если у кого-то из участников указан неизвестный уровень
+func validMemberLevels(c *gin.Context, members []models.TeamMember) bool {
+    for _, member := range members {
+        if member.Level != "" && !assignment.ValidLevel(member.Level) {
+            c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, fmt.Sprintf("unknown level of %s", member.UserID)))
+            return false
+        }
+    }
+    return true
 }
\ No newline at end of file
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+    "net/http"
+    "pr-reviewer/src/internal/api/auth"
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/storage"
+
+    "github.com/gin-gonic/gin"
+)
+
+type TokenHandler struct {
+    tokens database.TokenRepository
+    access database.AccessRepository
+}
+
+func NewTokenHandler(tokens database.TokenRepository, access database.AccessRepository) *TokenHandler {
+    return &TokenHandler{tokens: tokens, access: access}
+}
+
+// IssueToken выдаёт токен API; секрет возвращается только в этом ответе, в базе хранится его хэш
+// @Summary Выдать токен API
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Router /auth/tokens/add [post]
+func (h *TokenHandler) IssueToken(c *gin.Context) {
+    var req models.IssueTokenRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+
+    switch {
+    case req.Name == "":
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "name is required"))
+        return
+    case req.Scope != models.ScopeAdmin && req.Scope != models.ScopeUser && req.Scope != models.ScopeBot:
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "scope must be admin, user or bot"))
+        return
+    case req.Scope == models.ScopeUser && req.UserID == "":
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "user_id is required for user scope"))
+        return
+    }
+
+    secret := auth.NewSecret()
+    token, err := h.tokens.CreateAPIToken(models.APIToken{
+        TokenID: auth.NewTokenID(),
+        Name:    req.Name,
+        Scope:   req.Scope,
+        UserID:  req.UserID,
+    }, auth.HashSecret(secret))
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{"token": token, "secret": secret})
+}
+
+func (h *TokenHandler) ListTokens(c *gin.Context) {
+    tokens, err := h.tokens.ListAPITokens()
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"tokens": tokens})
+}
+
+// RevokeToken отзывает токен; повторный отзыв ничего не меняет
+// @Summary Отозвать токен API
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Router /auth/tokens/revoke [post]
+func (h *TokenHandler) RevokeToken(c *gin.Context) {
+    var req models.RevokeTokenRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+
+    token, err := h.tokens.RevokeAPIToken(req.TokenID)
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"token": token})
+}
+
+// Permissions показывает действующие права вызывающего; с user_id - права, которые получил бы токен этого пользователя
+// @Summary Действующие права
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} models.PermissionsResponse
+// @Router /auth/permissions [get]
+func (h *TokenHandler) Permissions(c *gin.Context) {
+    principal, ok := auth.PrincipalFrom(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, createErrorResponse(models.CodeUnauthorized, "missing bearer token"))
+        return
+    }
+
+    userID := c.Query("user_id")
+    if userID == "" {
+        c.JSON(http.StatusOK, principal.Permissions())
+        return
+    }
+    if !authorizeUser(c, h.access, auth.ActionUserRead, userID) {
+        return
+    }
+
+    userAccess, err := h.access.GetUserAccess(userID)
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, auth.ForUser(userAccess).Permissions())
+}
@@ -1,7 +1,10 @@
 package handlers
 
 import (
+    "fmt"
     "net/http"
+    "pr-reviewer/src/internal/api/auth"
+    "pr-reviewer/src/internal/domain/assignment"
     "pr-reviewer/src/internal/storage"
     "pr-reviewer/src/internal/domain/models"
 
@@ -9,11 +12,12 @@ import (
 )
 
 type UserHandler struct {
-    db *database.DB
+    users  database.UserRepository
+    access database.AccessRepository
 }
 
-func NewUserHandler(db *database.DB) *UserHandler {
-    return &UserHandler{db: db}
+func NewUserHandler(users database.UserRepository, access database.AccessRepository) *UserHandler {
+    return &UserHandler{users: users, access: access}
 }
 
 func (h *UserHandler) SetIsActive(c *gin.Context) {
@@ -23,7 +27,69 @@ func (h *UserHandler) SetIsActive(c *gin.Context) {
         return
     }
 
-    user, err := h.db.SetUserActive(req.UserID, req.IsActive)
+    if !authorizeUser(c, h.access, auth.ActionUserManage, req.UserID) {
+        return
+    }
+
+    user, err := h.users.SetUserActive(req.UserID, req.IsActive)
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"user": user})
+}
+
+// SetMaxOpenReviews задаёт личное ограничение открытых ревью; null возвращает ограничение команды
+func (h *UserHandler) SetMaxOpenReviews(c *gin.Context) {
+    var req models.SetMaxOpenReviewsRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+    if req.MaxOpenReviews != nil && *req.MaxOpenReviews < 0 {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "max_open_reviews must not be negative"))
+        return
+    }
+
+    if !authorizeUser(c, h.access, auth.ActionUserManage, req.UserID) {
+        return
+    }
+
+    user, err := h.users.SetUserMaxOpenReviews(req.UserID, req.MaxOpenReviews)
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"user": user})
+}
+
+// SetLevel задаёт уровень пользователя: junior, middle или senior
+func (h *UserHandler) SetLevel(c *gin.Context) {
+    var req models.SetUserLevelRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+    if !assignment.ValidLevel(req.Level) {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "unknown level"))
+        return
+    }
+
+    if !authorizeUser(c, h.access, auth.ActionUserManage, req.UserID) {
+        return
+    }
+
+    user, err := h.users.SetUserLevel(req.UserID, req.Level)
     if err != nil {
         if err == database.ErrNotFound {
             c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
@@ -42,8 +108,11 @@ func (h *UserHandler) GetReview(c *gin.Context) {
         c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "user_id is required"))
         return
     }
+    if !authorizeUser(c, h.access, auth.ActionUserRead, userID) {
+        return
+    }
 
-    response, err := h.db.GetUserPullRequests(userID)
+    response, err := h.users.GetUserPullRequests(userID)
     if err != nil {
         if err == database.ErrNotFound {
             c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
@@ -54,4 +123,70 @@ func (h *UserHandler) GetReview(c *gin.Context) {
     }
 
     c.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+}
+
+// SetSkills заменяет навыки пользователя; они сравниваются с метками PR при назначении ревьюверов
+func (h *UserHandler) SetSkills(c *gin.Context) {
+    var req models.SetUserSkillsRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+    skills, ok := normalizeTags(c, "skills", req.Skills)
+    if !ok {
+        return
+    }
+
+    if !authorizeUser(c, h.access, auth.ActionUserManage, req.UserID) {
+        return
+    }
+
+    result, err := h.users.SetUserSkills(req.UserID, skills)
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, result)
+}
+
+func (h *UserHandler) GetSkills(c *gin.Context) {
+    userID := c.Query("user_id")
+    if userID == "" {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "user_id is required"))
+        return
+    }
+    if !authorizeUser(c, h.access, auth.ActionUserRead, userID) {
+        return
+    }
+
+    result, err := h.users.GetUserSkills(userID)
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, result)
+}
+
+const maxTagLength = 100
+
+// normalizeTags приводит навыки или метки к виду, в котором они хранятся, и отвечает 400 на слишком длинные
+func normalizeTags(c *gin.Context, field string, tags []string) ([]string, bool) {
+    normalized := database.NormalizeTags(tags)
+    for _, tag := range normalized {
+        if len(tag) > maxTagLength {
+            c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, fmt.Sprintf("%s must be at most %d characters long", field, maxTagLength)))
+            return nil, false
+        }
+    }
+    return normalized, true
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+    "net/http"
+    "net/url"
+    "strconv"
+    "pr-reviewer/src/internal/domain/events"
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/storage"
+    "pr-reviewer/src/internal/webhooks"
+
+    "github.com/gin-gonic/gin"
+)
+
+const maxDeliveriesLimit = 500
+
+type WebhookHandler struct {
+    webhooks database.WebhookRepository
+}
+
+func NewWebhookHandler(webhooks database.WebhookRepository) *WebhookHandler {
+    return &WebhookHandler{webhooks: webhooks}
+}
+
+// AddSubscription создаёт подписку на события; секрет для подписи возвращается только в этом ответе
+// @Summary Подписаться на события сервиса
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Router /webhooks/subscriptions/add [post]
+func (h *WebhookHandler) AddSubscription(c *gin.Context) {
+    var req models.CreateWebhookSubscriptionRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+
+    sub := models.WebhookSubscription{
+        SubscriptionID: webhooks.NewSubscriptionID(),
+        URL:            req.URL,
+        Secret:         req.Secret,
+        Events:         req.Events,
+        IsActive:       true,
+    }
+    if sub.Secret == "" {
+        sub.Secret = webhooks.NewSecret()
+    }
+    if sub.Events == nil {
+        sub.Events = []string{}
+    }
+    if msg := validateSubscription(sub); msg != "" {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, msg))
+        return
+    }
+
+    created, err := h.webhooks.CreateWebhookSubscription(sub)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{"subscription": created})
+}
+
+func (h *WebhookHandler) ListSubscriptions(c *gin.Context) {
+    subs, err := h.webhooks.ListWebhookSubscriptions()
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        return
+    }
+
+    for i := range subs {
+        subs[i].Secret = ""
+    }
+
+    c.JSON(http.StatusOK, gin.H{"subscriptions": subs})
+}
+
+func (h *WebhookHandler) GetSubscription(c *gin.Context) {
+    sub, err := h.webhooks.GetWebhookSubscription(c.Query("subscription_id"))
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    sub.Secret = ""
+    c.JSON(http.StatusOK, gin.H{"subscription": sub})
+}
+
+func (h *WebhookHandler) UpdateSubscription(c *gin.Context) {
+    var req models.UpdateWebhookSubscriptionRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+
+    sub, err := h.webhooks.GetWebhookSubscription(req.SubscriptionID)
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    if req.URL != nil {
+        sub.URL = *req.URL
+    }
+    if req.Secret != nil {
+        sub.Secret = *req.Secret
+    }
+    if req.Events != nil {
+        sub.Events = append([]string{}, *req.Events...)
+    }
+    if req.IsActive != nil {
+        sub.IsActive = *req.IsActive
+    }
+    if msg := validateSubscription(*sub); msg != "" {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, msg))
+        return
+    }
+
+    updated, err := h.webhooks.UpdateWebhookSubscription(*sub)
+    if err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    updated.Secret = ""
+    c.JSON(http.StatusOK, gin.H{"subscription": updated})
+}
+
+func (h *WebhookHandler) DeleteSubscription(c *gin.Context) {
+    var req models.DeleteWebhookSubscriptionRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+
+    if err := h.webhooks.DeleteWebhookSubscription(req.SubscriptionID); err != nil {
+        if err == database.ErrNotFound {
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        } else {
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries возвращает журнал доставок, новые первыми
+// @Summary Журнал доставок вебхуков
+// @Tags Webhooks
+// @Produce json
+// @Param subscription_id query string false "Фильтр по подписке"
+// @Param status query string false "PENDING, DELIVERED или DEAD"
+// @Param limit query int false "Количество возвращаемых записей" default(100)
+// @Router /webhooks/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+    h.listDeliveries(c, c.Query("status"))
+}
+
+// ListDeadLetters возвращает доставки, исчерпавшие попытки
+// @Summary Недоставленные события
+// @Tags Webhooks
+// @Produce json
+// @Router /webhooks/deadLetters [get]
+func (h *WebhookHandler) ListDeadLetters(c *gin.Context) {
+    h.listDeliveries(c, models.DeliveryDead)
+}
+
+func (h *WebhookHandler) listDeliveries(c *gin.Context, status string) {
+    switch status {
+    case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryDead:
+    default:
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, "status must be PENDING, DELIVERED or DEAD"))
+        return
+    }
+
+    limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+    if err != nil || limit <= 0 {
+        limit = 100
+    }
+    if limit > maxDeliveriesLimit {
+        limit = maxDeliveriesLimit
+    }
+
+    deliveries, err := h.webhooks.ListWebhookDeliveries(models.WebhookDeliveryFilter{
+        SubscriptionID: c.Query("subscription_id"),
+        Status:         status,
+        Limit:          limit,
+    })
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+// ReplayDelivery возвращает доставку из списка недоставленных в очередь с обнулённым счётчиком попыток
+// @Summary Повторить недоставленное событие
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Router /webhooks/deliveries/replay [post]
+func (h *WebhookHandler) ReplayDelivery(c *gin.Context) {
+    var req models.ReplayWebhookDeliveryRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, createErrorResponse(models.CodeInvalidRequest, err.Error()))
+        return
+    }
+
+    delivery, err := h.webhooks.ReplayWebhookDelivery(req.DeliveryID)
+    if err != nil {
+        switch err {
+        case database.ErrNotFound:
+            c.JSON(http.StatusNotFound, createErrorResponse(models.CodeNotFound, "resource not found"))
+        case database.ErrNotDeadLetter:
+            c.JSON(http.StatusConflict, createErrorResponse(models.CodeNotDeadLetter, "only DEAD deliveries can be replayed"))
+        default:
+            c.JSON(http.StatusInternalServerError, createErrorResponse(models.CodeInternalError, err.Error()))
+        }
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"delivery": delivery})
+}
+
+func validateSubscription(sub models.WebhookSubscription) string {
+    u, err := url.Parse(sub.URL)
+    if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+        return "url must be an absolute http(s) URL"
+    }
+    for _, eventType := range sub.Events {
+        if !events.IsKnown(eventType) {
+            return "unknown event type " + strconv.Quote(eventType)
+        }
+    }
+    return ""
+}
@@ -0,0 +1,15 @@
+package backoff
+
+import "time"
+
+// Delay возвращает паузу перед повтором после attempts неудачных попыток: base * 2^(attempts-1), не больше max
+func Delay(base, max time.Duration, attempts int) time.Duration {
+    delay := base
+    for i := 1; i < attempts && delay < max; i++ {
+        delay *= 2
+    }
+    if delay > max {
+        delay = max
+    }
+    return delay
+}
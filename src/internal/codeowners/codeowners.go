@@ -0,0 +1,129 @@
+package codeowners
+
+import (
+    "fmt"
+    "regexp"
+    "strings"
+)
+
+// Rule - строка CODEOWNERS: шаблон пути и его владельцы (@login, @org/team или email)
+type Rule struct {
+    Pattern string
+    Owners  []string
+    Line    int
+    re      *regexp.Regexp
+}
+
+// Ruleset - разобранный CODEOWNERS; как и в GitHub, для файла действует последнее подходящее правило
+type Ruleset struct {
+    Rules []Rule
+}
+
+// LineError - строка, которую не удалось разобрать; такие строки пропускаются
+type LineError struct {
+    Line    int    `json:"line"`
+    Message string `json:"message"`
+}
+
+func (e LineError) Error() string {
+    return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// Parse разбирает файл в формате CODEOWNERS. Строки с ошибками не попадают в правила и возвращаются отдельно.
+func Parse(content string) (*Ruleset, []LineError) {
+    ruleset := &Ruleset{}
+    var errs []LineError
+
+    for i, line := range strings.Split(content, "\n") {
+        if comment := strings.Index(line, "#"); comment >= 0 {
+            line = line[:comment]
+        }
+        fields := strings.Fields(line)
+        if len(fields) == 0 {
+            continue
+        }
+
+        re, err := compile(fields[0])
+        if err != nil {
+            errs = append(errs, LineError{Line: i + 1, Message: err.Error()})
+            continue
+        }
+        // правило без владельцев снимает владельцев, назначенных выше
+        ruleset.Rules = append(ruleset.Rules, Rule{Pattern: fields[0], Owners: fields[1:], Line: i + 1, re: re})
+    }
+    return ruleset, errs
+}
+
+// Owners возвращает владельцев файла по последнему подходящему правилу
+func (r *Ruleset) Owners(path string) []string {
+    path = strings.TrimPrefix(path, "/")
+    for i := len(r.Rules) - 1; i >= 0; i-- {
+        if r.Rules[i].re.MatchString(path) {
+            return r.Rules[i].Owners
+        }
+    }
+    return nil
+}
+
+// OwnersOf собирает владельцев всех файлов без повторов в порядке первого появления
+func (r *Ruleset) OwnersOf(paths []string) []string {
+    owners := []string{}
+    seen := make(map[string]bool)
+    for _, path := range paths {
+        for _, owner := range r.Owners(path) {
+            if key := strings.ToLower(owner); !seen[key] {
+                seen[key] = true
+                owners = append(owners, owner)
+            }
+        }
+    }
+    return owners
+}
+
+// compile переводит шаблон gitignore-вида в регулярное выражение. Шаблон без "/" в начале или середине
+// ищется на любой глубине, шаблон каталога покрывает всё его содержимое. "*" в последнем сегменте,
+// как в GitHub, покрывает только файлы самого каталога: "docs/*" не относится к "docs/sub/b.md".
+func compile(pattern string) (*regexp.Regexp, error) {
+    if strings.HasPrefix(pattern, "!") {
+        return nil, fmt.Errorf("negated pattern %q is not supported", pattern)
+    }
+    if strings.Contains(pattern, "[") || strings.Contains(pattern, "\\") {
+        return nil, fmt.Errorf("character ranges and escapes are not supported in %q", pattern)
+    }
+
+    anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+    body := strings.TrimSuffix(strings.TrimPrefix(pattern, "/"), "/")
+    if body == "" {
+        return nil, fmt.Errorf("empty pattern %q", pattern)
+    }
+
+    var b strings.Builder
+    b.WriteString("^")
+    if !anchored {
+        b.WriteString("(?:.*/)?")
+    }
+    for i := 0; i < len(body); i++ {
+        switch {
+        case strings.HasPrefix(body[i:], "**/"):
+            b.WriteString("(?:.*/)?")
+            i += 2
+        case strings.HasPrefix(body[i:], "**"):
+            b.WriteString(".*")
+            i++
+        case body[i] == '*':
+            b.WriteString("[^/]*")
+        case body[i] == '?':
+            b.WriteString("[^/]")
+        default:
+            b.WriteString(regexp.QuoteMeta(body[i : i+1]))
+        }
+    }
+    // содержимое покрывает шаблон каталога: с "/" или "**" в конце или без масок в последнем сегменте
+    last := body[strings.LastIndex(body, "/")+1:]
+    if strings.HasSuffix(pattern, "/") || strings.HasSuffix(body, "**") || !strings.ContainsAny(last, "*?") {
+        b.WriteString("(?:/.*)?")
+    }
+    b.WriteString("$")
+
+    return regexp.Compile(b.String())
+}
@@ -0,0 +1,98 @@
+package codeowners
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func TestOwners(t *testing.T) {
+    ruleset, errs := Parse(`
+# владельцы по умолчанию
+*           @org/all
+
+*.go        @gopher   # комментарий после правила
+/build/     @builder
+docs/*      @writer
+apps/**     @apps
+**/logs     @ops
+/src/api    @api
+/src/api/generated
+`)
+    assert.Empty(t, errs)
+    assert.Len(t, ruleset.Rules, 8, "comments and blank lines are skipped")
+
+    cases := []struct {
+        path   string
+        owners []string
+    }{
+        {"README.md", []string{"@org/all"}},
+        {"cmd/main.go", []string{"@gopher"}},
+        {"main.go", []string{"@gopher"}},
+        {"build/out/app", []string{"@builder"}},
+        {"tools/build/app", []string{"@org/all"}},
+        {"docs/a.md", []string{"@writer"}},
+        {"docs/sub/b.md", []string{"@org/all"}},
+        {"apps/web/index.ts", []string{"@apps"}},
+        {"logs/today.txt", []string{"@ops"}},
+        {"srv/logs/today.txt", []string{"@ops"}},
+        {"src/api/handler.go", []string{"@api"}},
+        {"lib/src/api/handler.rb", []string{"@org/all"}},
+        {"/src/api/handler.rb", []string{"@api"}},
+        {"src/api/generated/types.go", []string{}},
+    }
+    for _, c := range cases {
+        owners := ruleset.Owners(c.path)
+        if len(c.owners) == 0 {
+            assert.Empty(t, owners, c.path)
+        } else {
+            assert.Equal(t, c.owners, owners, c.path)
+        }
+    }
+}
+
+func TestOwnersLastMatchWins(t *testing.T) {
+    ruleset, _ := Parse("docs/ @first\n*.md @second\n")
+    assert.Equal(t, []string{"@second"}, ruleset.Owners("docs/guide.md"))
+    assert.Equal(t, []string{"@first"}, ruleset.Owners("docs/image.png"))
+
+    ruleset, _ = Parse("*.md @second\ndocs/ @first\n")
+    assert.Equal(t, []string{"@first"}, ruleset.Owners("docs/guide.md"))
+}
+
+func TestWildcards(t *testing.T) {
+    cases := []struct {
+        pattern string
+        path    string
+        match   bool
+    }{
+        {"docs/*", "docs/a.md", true},
+        {"docs/*", "docs/sub/b.md", false},
+        {"docs/**", "docs/sub/b.md", true},
+        {"docs/*/", "docs/sub/b.md", true},
+        {"docs/?.md", "docs/a.md", true},
+        {"docs/?.md", "docs/ab.md", false},
+        {"a/**/b", "a/b", true},
+        {"a/**/b", "a/x/y/b/c.txt", true},
+        {"*.js", "web/app.js", true},
+        {"docs", "docs/a.md", true},
+        {"docs", "src/docs/a.md", true},
+        {"/docs", "src/docs/a.md", false},
+    }
+    for _, c := range cases {
+        ruleset, errs := Parse(c.pattern + " @owner")
+        if assert.Empty(t, errs, c.pattern) {
+            assert.Equal(t, c.match, ruleset.Owners(c.path) != nil, "%s ~ %s", c.pattern, c.path)
+        }
+    }
+}
+
+func TestParseErrors(t *testing.T) {
+    ruleset, errs := Parse("!docs/ @a\n[ab].go @b\n/ @c\nok/ @d\n")
+    assert.Len(t, ruleset.Rules, 1)
+    var lines []int
+    for _, err := range errs {
+        lines = append(lines, err.Line)
+    }
+    assert.Equal(t, []int{1, 2, 3}, lines)
+}
@@ -0,0 +1,65 @@
+package codeowners
+
+import (
+    "log"
+    "os"
+
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/storage"
+)
+
+// Source находит CODEOWNERS репозитория: сначала загруженный через API, затем локальный файл из настроек.
+// Файлы читаются при каждом обращении, поэтому обновлённый checkout подхватывается без перезапуска.
+type Source struct {
+    store database.CodeOwnersRepository
+    files map[string]string
+}
+
+// NewSource принимает пути к локальным файлам CODEOWNERS по названиям репозиториев
+func NewSource(store database.CodeOwnersRepository, files map[string]string) *Source {
+    return &Source{store: store, files: files}
+}
+
+// Load возвращает CODEOWNERS репозитория или database.ErrNotFound, если он нигде не задан
+func (s *Source) Load(repository string) (*models.CodeOwnersFile, error) {
+    file, err := s.store.GetCodeOwners(repository)
+    if err != database.ErrNotFound {
+        return file, err
+    }
+
+    path, ok := s.files[repository]
+    if !ok {
+        return nil, database.ErrNotFound
+    }
+    content, err := os.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+    file = &models.CodeOwnersFile{Repository: repository, Content: string(content), Source: models.CodeOwnersSourceFile}
+    if info, err := os.Stat(path); err == nil {
+        file.UpdatedAt = info.ModTime()
+    }
+    return file, nil
+}
+
+// Owners возвращает владельцев изменённых файлов; без CODEOWNERS у репозитория - пустой список
+func (s *Source) Owners(repository string, paths []string) ([]string, error) {
+    if repository == "" || len(paths) == 0 {
+        return nil, nil
+    }
+
+    file, err := s.Load(repository)
+    if err == database.ErrNotFound {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    ruleset, errs := Parse(file.Content)
+    for _, lineErr := range errs {
+        log.Printf("CODEOWNERS for %s (%s): %v", repository, file.Source, lineErr)
+    }
+    return ruleset.OwnersOf(paths), nil
+}
+
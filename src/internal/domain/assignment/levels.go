@@ -0,0 +1,41 @@
+package assignment
+
+const (
+    Junior = "junior"
+    Middle = "middle"
+    Senior = "senior"
+
+    DefaultLevel = Middle
+)
+
+// levels перечисляет уровни от младшего к старшему
+var levels = []string{Junior, Middle, Senior}
+
+func ValidLevel(level string) bool {
+    return levelRank(level) >= 0
+}
+
+// AtLeast проверяет, что level не ниже min; пустой min ничего не требует
+func AtLeast(level, min string) bool {
+    return min == "" || levelRank(level) >= levelRank(min)
+}
+
+// LevelsFrom возвращает уровни не ниже min, чтобы сравнивать уровни в запросах
+func LevelsFrom(min string) []string {
+    if min == "" {
+        return append([]string(nil), levels...)
+    }
+    if rank := levelRank(min); rank >= 0 {
+        return append([]string(nil), levels[rank:]...)
+    }
+    return []string{}
+}
+
+func levelRank(level string) int {
+    for i, l := range levels {
+        if l == level {
+            return i
+        }
+    }
+    return -1
+}
@@ -0,0 +1,193 @@
+package assignment
+
+import (
+    "errors"
+    "math/rand"
+    "sort"
+    "time"
+)
+
+const (
+    FirstN      = "first_n"
+    Random      = "random"
+    LeastLoaded = "least_loaded"
+    RoundRobin  = "round_robin"
+
+    Default = LeastLoaded
+)
+
+var ErrUnknownStrategy = errors.New("unknown assignment strategy")
+
+// Candidate описывает участника команды, которого можно назначить ревьювером
+type Candidate struct {
+    UserID         string
+    Level          string
+    OpenReviews    int
+    LastAssignedAt time.Time
+}
+
+// Strategy выбирает до count ревьюверов из кандидатов в порядке приоритета
+type Strategy interface {
+    Name() string
+    Pick(candidates []Candidate, count int) []string
+}
+
+var strategies = map[string]Strategy{
+    FirstN:      firstN{},
+    Random:      random{},
+    LeastLoaded: leastLoaded{},
+    RoundRobin:  roundRobin{},
+}
+
+func Get(name string) (Strategy, error) {
+    if name == "" {
+        name = Default
+    }
+    strategy, ok := strategies[name]
+    if !ok {
+        return nil, ErrUnknownStrategy
+    }
+    return strategy, nil
+}
+
+func Names() []string {
+    names := make([]string, 0, len(strategies))
+    for name := range strategies {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+type firstN struct{}
+
+func (firstN) Name() string { return FirstN }
+
+func (firstN) Pick(candidates []Candidate, count int) []string {
+    return take(candidates, count)
+}
+
+type random struct{}
+
+func (random) Name() string { return Random }
+
+func (random) Pick(candidates []Candidate, count int) []string {
+    return take(shuffled(candidates), count)
+}
+
+type leastLoaded struct{}
+
+func (leastLoaded) Name() string { return LeastLoaded }
+
+func (leastLoaded) Pick(candidates []Candidate, count int) []string {
+    ordered := shuffled(candidates)
+    sort.SliceStable(ordered, func(i, j int) bool {
+        return ordered[i].OpenReviews < ordered[j].OpenReviews
+    })
+    return take(ordered, count)
+}
+
+type roundRobin struct{}
+
+func (roundRobin) Name() string { return RoundRobin }
+
+func (roundRobin) Pick(candidates []Candidate, count int) []string {
+    ordered := append([]Candidate(nil), candidates...)
+    sort.SliceStable(ordered, func(i, j int) bool {
+        if !ordered[i].LastAssignedAt.Equal(ordered[j].LastAssignedAt) {
+            return ordered[i].LastAssignedAt.Before(ordered[j].LastAssignedAt)
+        }
+        return ordered[i].UserID < ordered[j].UserID
+    })
+    return take(ordered, count)
+}
+
+// PickPreferring сначала выбирает стратегией из предпочтительных кандидатов, а недостающих - из остальных
+func PickPreferring(strategy Strategy, candidates []Candidate, count int, preferred func(Candidate) bool) []string {
+    var first, rest []Candidate
+    for _, candidate := range candidates {
+        if preferred(candidate) {
+            first = append(first, candidate)
+        } else {
+            rest = append(rest, candidate)
+        }
+    }
+
+    picked := strategy.Pick(first, count)
+    return append(picked, strategy.Pick(rest, count-len(picked))...)
+}
+
+// EnsureCovered следит, чтобы среди picked был хотя бы один кандидат, для которого covers истинно. Если такого нет,
+// заменяется последний из picked, кого не нужно сохранять по keep (или просто последний), лучшим по стратегии
+// подходящим кандидатом; при замене того, кого нужно было сохранить, предпочитаются подходящие кандидаты с keep.
+// keep может быть nil.
+func EnsureCovered(strategy Strategy, candidates []Candidate, picked []string, covers, keep func(Candidate) bool) []string {
+    if len(picked) == 0 {
+        return picked
+    }
+    if keep == nil {
+        keep = func(Candidate) bool { return false }
+    }
+
+    byID := make(map[string]Candidate, len(candidates))
+    for _, candidate := range candidates {
+        byID[candidate.UserID] = candidate
+    }
+    chosen := make(map[string]bool, len(picked))
+    for _, userID := range picked {
+        chosen[userID] = true
+    }
+    var matching []Candidate
+    for _, candidate := range candidates {
+        if !covers(candidate) {
+            continue
+        }
+        if chosen[candidate.UserID] {
+            return picked
+        }
+        matching = append(matching, candidate)
+    }
+
+    replaced := len(picked) - 1
+    for i := len(picked) - 1; i >= 0; i-- {
+        if candidate, ok := byID[picked[i]]; !ok || !keep(candidate) {
+            replaced = i
+            break
+        }
+    }
+
+    var best []string
+    if candidate, ok := byID[picked[replaced]]; ok && keep(candidate) {
+        best = PickPreferring(strategy, matching, 1, keep)
+    } else {
+        best = strategy.Pick(matching, 1)
+    }
+    if len(best) == 0 {
+        return picked
+    }
+    result := append([]string(nil), picked...)
+    result[replaced] = best[0]
+    return result
+}
+
+func shuffled(candidates []Candidate) []Candidate {
+    result := append([]Candidate(nil), candidates...)
+    rand.Shuffle(len(result), func(i, j int) {
+        result[i], result[j] = result[j], result[i]
+    })
+    return result
+}
+
+func take(candidates []Candidate, count int) []string {
+    if count > len(candidates) {
+        count = len(candidates)
+    }
+    if count < 0 {
+        count = 0
+    }
+    ids := make([]string, 0, count)
+    for _, candidate := range candidates[:count] {
+        ids = append(ids, candidate.UserID)
+    }
+    return ids
+}
@@ -0,0 +1,134 @@
+package events
+
+import (
+    "crypto/rand"
+    "encoding/hex"
+    "encoding/json"
+    "time"
+
+    "pr-reviewer/src/internal/domain/models"
+)
+
+const (
+    PRCreated        = "pr.created"
+    PRMerged         = "pr.merged"
+    PRClosed         = "pr.closed"
+    PRReopened       = "pr.reopened"
+    ReviewerAssigned = "reviewer.assigned"
+    ReviewerReplaced = "reviewer.replaced"
+    ReviewerRemoved  = "reviewer.removed"
+    ReviewEscalated  = "review.escalated"
+)
+
+var types = []string{PRCreated, PRMerged, PRClosed, PRReopened, ReviewerAssigned, ReviewerReplaced, ReviewerRemoved, ReviewEscalated}
+
+// Event - доменное событие сервиса; Data содержит JSON с полезной нагрузкой, зависящей от Type
+type Event struct {
+    ID         string          `json:"id"`
+    Type       string          `json:"type"`
+    OccurredAt time.Time       `json:"occurred_at"`
+    Data       json.RawMessage `json:"data"`
+}
+
+type PullRequestData struct {
+    PullRequest *models.PullRequest `json:"pull_request"`
+}
+
+type ReviewerAssignedData struct {
+    PullRequest *models.PullRequest `json:"pull_request"`
+    ReviewerID  string              `json:"reviewer_id"`
+}
+
+type ReviewerReplacedData struct {
+    PullRequest   *models.PullRequest `json:"pull_request"`
+    OldReviewerID string              `json:"old_reviewer_id"`
+    NewReviewerID string              `json:"new_reviewer_id"`
+}
+
+// PullRequestMergedData - PR смержен; Actor - кто смержил, в том числе в обход политики мержа
+type PullRequestMergedData struct {
+    PullRequest *models.PullRequest `json:"pull_request"`
+    Actor       string              `json:"actor"`
+}
+
+// ReviewEscalatedData - ревьювер не принял решение за SLA команды; ReplacedBy заполнен, если ревью переназначено
+type ReviewEscalatedData struct {
+    PullRequest *models.PullRequest `json:"pull_request"`
+    ReviewerID  string              `json:"reviewer_id"`
+    AssignedAt  time.Time           `json:"assigned_at"`
+    SLASeconds  int                 `json:"sla_seconds"`
+    ReplacedBy  string              `json:"replaced_by,omitempty"`
+}
+
+func Types() []string {
+    return append([]string(nil), types...)
+}
+
+func IsKnown(eventType string) bool {
+    for _, t := range types {
+        if t == eventType {
+            return true
+        }
+    }
+    return false
+}
+
+func New(eventType string, data interface{}) Event {
+    payload, _ := json.Marshal(data)
+    return Event{
+        ID:         NewID(),
+        Type:       eventType,
+        OccurredAt: time.Now().UTC(),
+        Data:       payload,
+    }
+}
+
+// PullRequestCreated возвращает pr.created и reviewer.assigned для каждого назначенного ревьювера
+func PullRequestCreated(pr *models.PullRequest) []Event {
+    result := []Event{New(PRCreated, PullRequestData{PullRequest: pr})}
+    for _, reviewerID := range pr.AssignedReviewers {
+        result = append(result, ReviewerAssignedEvent(pr, reviewerID))
+    }
+    return result
+}
+
+func ReviewerAssignedEvent(pr *models.PullRequest, reviewerID string) Event {
+    return New(ReviewerAssigned, ReviewerAssignedData{PullRequest: pr, ReviewerID: reviewerID})
+}
+
+// ReviewerRemovedEvent - ревьювер снят с PR без замены; Data совпадает с reviewer.assigned
+func ReviewerRemovedEvent(pr *models.PullRequest, reviewerID string) Event {
+    return New(ReviewerRemoved, ReviewerAssignedData{PullRequest: pr, ReviewerID: reviewerID})
+}
+
+func ReviewerReplacedEvent(pr *models.PullRequest, oldReviewerID, newReviewerID string) Event {
+    return New(ReviewerReplaced, ReviewerReplacedData{
+        PullRequest:   pr,
+        OldReviewerID: oldReviewerID,
+        NewReviewerID: newReviewerID,
+    })
+}
+
+func ReviewEscalatedEvent(pr *models.PullRequest, escalation models.ReviewEscalation) Event {
+    return New(ReviewEscalated, ReviewEscalatedData{
+        PullRequest: pr,
+        ReviewerID:  escalation.ReviewerID,
+        AssignedAt:  escalation.AssignedAt,
+        SLASeconds:  escalation.SLASeconds,
+        ReplacedBy:  escalation.ReplacedBy,
+    })
+}
+
+func PullRequestMergedEvent(pr *models.PullRequest, actor string) Event {
+    return New(PRMerged, PullRequestMergedData{PullRequest: pr, Actor: actor})
+}
+
+func PullRequestEvent(eventType string, pr *models.PullRequest) Event {
+    return New(eventType, PullRequestData{PullRequest: pr})
+}
+
+func NewID() string {
+    b := make([]byte, 16)
+    rand.Read(b)
+    return hex.EncodeToString(b)
+}
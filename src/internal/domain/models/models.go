@@ -1,26 +1,49 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 )
 
 type ErrorCodes string
 
 const (
-	CodeTeamExists     ErrorCodes = "TEAM_EXISTS"
-	CodePRExists       ErrorCodes = "PR_EXISTS"
-	CodePRMerged       ErrorCodes = "PR_MERGED"
-	CodeNotAssigned    ErrorCodes = "NOT_ASSIGNED"
-	CodeNoCandidate    ErrorCodes = "NO_CANDIDATE"
-	CodeNotFound       ErrorCodes = "NOT_FOUND"
-	CodeInvalidRequest ErrorCodes = "INVALID_REQUEST"
-	CodeInternalError  ErrorCodes = "INTERNAL_ERROR"
+	CodeTeamExists      ErrorCodes = "TEAM_EXISTS"
+	CodePRExists        ErrorCodes = "PR_EXISTS"
+	CodePRMerged        ErrorCodes = "PR_MERGED"
+	CodePRClosed        ErrorCodes = "PR_CLOSED"
+	CodeNotAssigned     ErrorCodes = "NOT_ASSIGNED"
+	CodeNoCandidate     ErrorCodes = "NO_CANDIDATE"
+	CodeNotFound        ErrorCodes = "NOT_FOUND"
+	CodeInvalidRequest  ErrorCodes = "INVALID_REQUEST"
+	CodeInternalError   ErrorCodes = "INTERNAL_ERROR"
+	CodeNotApproved     ErrorCodes = "NOT_APPROVED"
+	CodeBadSignature    ErrorCodes = "INVALID_SIGNATURE"
+	CodeNotDeadLetter   ErrorCodes = "NOT_DEAD_LETTER"
+	CodeUserInOtherTeam ErrorCodes = "USER_IN_OTHER_TEAM"
+	CodeTeamNotEmpty    ErrorCodes = "TEAM_NOT_EMPTY"
+	CodeUnauthorized    ErrorCodes = "UNAUTHORIZED"
+	CodeForbidden       ErrorCodes = "FORBIDDEN"
+)
+
+const (
+	ReviewPending          = "PENDING"
+	ReviewApproved         = "APPROVED"
+	ReviewChangesRequested = "CHANGES_REQUESTED"
+	ReviewCommented        = "COMMENTED"
+)
+
+const (
+	DeliveryPending   = "PENDING"
+	DeliveryDelivered = "DELIVERED"
+	DeliveryDead      = "DEAD"
 )
 
 type ErrorResponse struct {
 	Error struct {
-		Code    ErrorCodes `json:"code"`
-		Message string     `json:"message"`
+		Code    ErrorCodes  `json:"code"`
+		Message string      `json:"message"`
+		Details interface{} `json:"details,omitempty"`
 	} `json:"error"`
 }
 
@@ -28,11 +51,49 @@ type TeamMember struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	IsActive bool   `json:"is_active"`
+	// Level - junior, middle или senior; пустой оставляет текущий уровень, новым участникам - middle
+	Level string `json:"level,omitempty"`
 }
 
 type Team struct {
-	TeamName string       `json:"team_name"`
-	Members  []TeamMember `json:"members"`
+	TeamName           string       `json:"team_name"`
+	AssignmentStrategy string       `json:"assignment_strategy,omitempty"`
+	Members            []TeamMember `json:"members"`
+}
+
+type TeamSettings struct {
+	TeamName           string `json:"team_name"`
+	ReviewersCount     int    `json:"reviewers_count"`
+	MinReviewersCount  int    `json:"min_reviewers_count"`
+	AssignmentStrategy string `json:"assignment_strategy"`
+	RequiredApprovals  int    `json:"required_approvals"`
+	// MaxOpenReviews - сколько открытых ревью может быть у участника по умолчанию, 0 - без ограничения
+	MaxOpenReviews int `json:"max_open_reviews"`
+	// ReviewSLASeconds - за сколько ревьювер должен принять решение, 0 - SLA не отслеживается
+	ReviewSLASeconds int `json:"review_sla_seconds"`
+	// SLAAutoReassign - переназначать ревью, просроченное по SLA
+	SLAAutoReassign bool `json:"sla_auto_reassign"`
+	// FallbackTeams - команды, из которых по порядку добираются ревьюверы, если в команде не хватает кандидатов
+	FallbackTeams []string `json:"fallback_teams"`
+	// MinReviewerLevel - хотя бы один ревьювер не ниже этого уровня, пустая строка - без требования
+	MinReviewerLevel string `json:"min_reviewer_level"`
+	// ShadowReviewers - сколько junior назначать сверх reviewers_count; они не входят в кворум и не дают одобрений
+	ShadowReviewers int `json:"shadow_reviewers"`
+}
+
+type UpdateTeamSettingsRequest struct {
+	TeamName           string  `json:"team_name"`
+	ReviewersCount     *int    `json:"reviewers_count,omitempty"`
+	MinReviewersCount  *int    `json:"min_reviewers_count,omitempty"`
+	AssignmentStrategy *string `json:"assignment_strategy,omitempty"`
+	RequiredApprovals  *int    `json:"required_approvals,omitempty"`
+	MaxOpenReviews     *int    `json:"max_open_reviews,omitempty"`
+	ReviewSLASeconds   *int    `json:"review_sla_seconds,omitempty"`
+	SLAAutoReassign    *bool   `json:"sla_auto_reassign,omitempty"`
+	// FallbackTeams заменяет список запасных команд целиком, [] очищает его
+	FallbackTeams    *[]string `json:"fallback_teams,omitempty"`
+	MinReviewerLevel *string   `json:"min_reviewer_level,omitempty"`
+	ShadowReviewers  *int      `json:"shadow_reviewers,omitempty"`
 }
 
 type User struct {
@@ -40,16 +101,107 @@ type User struct {
 	Username string `json:"username"`
 	TeamName string `json:"team_name"`
 	IsActive bool   `json:"is_active"`
+	Level    string `json:"level"`
+	// MaxOpenReviews переопределяет ограничение команды, nil - действует max_open_reviews команды
+	MaxOpenReviews *int `json:"max_open_reviews,omitempty"`
+}
+
+type ReviewerState struct {
+	UserID     string     `json:"user_id"`
+	State      string     `json:"state"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	// Fallback - ревьювер взят из запасной команды
+	Fallback bool `json:"fallback,omitempty"`
+	// Shadow - junior, назначенный вместе с основными ревьюверами; его решение не влияет на мерж
+	Shadow bool `json:"shadow,omitempty"`
 }
 
 type PullRequest struct {
-	PullRequestID     string    `json:"pull_request_id"`
-	PullRequestName   string    `json:"pull_request_name"`
-	AuthorID          string    `json:"author_id"`
-	Status            string    `json:"status"`
-	AssignedReviewers []string  `json:"assigned_reviewers"`
-	CreatedAt         time.Time `json:"createdAt,omitempty"`
-	MergedAt          time.Time `json:"mergedAt,omitempty"`
+	PullRequestID     string          `json:"pull_request_id"`
+	PullRequestName   string          `json:"pull_request_name"`
+	AuthorID          string          `json:"author_id"`
+	Status            string          `json:"status"`
+	AssignedReviewers []string        `json:"assigned_reviewers"`
+	Reviewers         []ReviewerState `json:"reviewers"`
+	CreatedAt         time.Time       `json:"createdAt,omitempty"`
+	MergedAt          time.Time       `json:"mergedAt,omitempty"`
+	ClosedAt          time.Time       `json:"closedAt,omitempty"`
+	ForceMerged       bool            `json:"force_merged,omitempty"`
+	// MergedBy - кто зафиксировал мерж, сделанный вне сервиса (github); пусто для мержа через API
+	MergedBy   string   `json:"merged_by,omitempty"`
+	Repository string   `json:"repository,omitempty"`
+	Labels     []string `json:"labels,omitempty"`
+}
+
+const (
+	HistoryAssigned = "ASSIGNED"
+	HistoryReplaced = "REPLACED"
+	HistoryRemoved  = "REMOVED"
+)
+
+// Причины изменений в истории назначений
+const (
+	ReasonPRCreated       = "PR_CREATED"
+	ReasonPRReopened      = "PR_REOPENED"
+	ReasonManualReassign  = "MANUAL_REASSIGN"
+	ReasonUserDeactivated = "USER_DEACTIVATED"
+	ReasonUserUnavailable = "USER_UNAVAILABLE"
+	ReasonSLABreached     = "SLA_BREACHED"
+	ReasonUserMoved       = "USER_MOVED"
+	ReasonUserRemoved     = "USER_REMOVED"
+)
+
+// Инициаторы изменений: фоновые задачи, вебхуки GitHub и запросы к API
+const (
+	ActorSystem = "system"
+	ActorGitHub = "github"
+	ActorAPI    = "api"
+)
+
+// ReviewerHistoryEntry - запись журнала назначений ревьюверов; журнал только дополняется.
+// Для REPLACED и REMOVED AssignedAt - когда началось завершённое назначение.
+type ReviewerHistoryEntry struct {
+	HistoryID     int64      `json:"history_id"`
+	PullRequestID string     `json:"pull_request_id"`
+	ReviewerID    string     `json:"reviewer_id"`
+	Action        string     `json:"action"`
+	ReplacedBy    string     `json:"replaced_by,omitempty"`
+	Reason        string     `json:"reason"`
+	Actor         string     `json:"actor"`
+	AssignedAt    *time.Time `json:"assigned_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type PullRequestHistory struct {
+	PullRequestID string                 `json:"pull_request_id"`
+	Reassignments int                    `json:"reassignments"`
+	History       []ReviewerHistoryEntry `json:"history"`
+}
+
+// PullRequestFilter - условия выборки /pullRequest/list; пустые поля не ограничивают выборку,
+// диапазоны дат полуоткрытые: From включительно, To - нет
+type PullRequestFilter struct {
+	Status      string
+	AuthorID    string
+	ReviewerID  string
+	TeamName    string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	MergedFrom  *time.Time
+	MergedTo    *time.Time
+	After       *PullRequestCursor
+	Limit       int
+}
+
+// PullRequestCursor - последний PR предыдущей страницы, PR отсортированы от новых к старым
+type PullRequestCursor struct {
+	CreatedAt     time.Time
+	PullRequestID string
+}
+
+type PullRequestPage struct {
+	PullRequests []*PullRequest `json:"pull_requests"`
+	NextCursor   string         `json:"next_cursor,omitempty"`
 }
 
 type PullRequestShort struct {
@@ -64,14 +216,199 @@ type SetActiveRequest struct {
 	IsActive bool   `json:"is_active"`
 }
 
+// ReviewEscalation - ревью, по которому истёк SLA команды
+type ReviewEscalation struct {
+	PullRequestID string    `json:"pull_request_id"`
+	ReviewerID    string    `json:"reviewer_id"`
+	AssignedAt    time.Time `json:"assigned_at"`
+	SLASeconds    int       `json:"sla_seconds"`
+	ReplacedBy    string    `json:"replaced_by,omitempty"`
+}
+
+type SetMaxOpenReviewsRequest struct {
+	UserID         string `json:"user_id"`
+	MaxOpenReviews *int   `json:"max_open_reviews"`
+}
+
+type SetUserLevelRequest struct {
+	UserID string `json:"user_id"`
+	Level  string `json:"level"`
+}
+
+// UserSkills - навыки пользователя; при назначении предпочитается ревьювер, навыки которого пересекаются с метками PR
+type UserSkills struct {
+	UserID string   `json:"user_id"`
+	Skills []string `json:"skills"`
+}
+
+type SetUserSkillsRequest struct {
+	UserID string   `json:"user_id"`
+	Skills []string `json:"skills"`
+}
+
+const (
+	ExcludedAuthor     = "AUTHOR"
+	ExcludedAssigned   = "ALREADY_ASSIGNED"
+	ExcludedInactive   = "INACTIVE"
+	ExcludedAway       = "UNAVAILABLE"
+	ExcludedAtCapacity = "AT_CAPACITY"
+)
+
+// CandidateExclusion - почему участник команды не может стать ревьювером
+type CandidateExclusion struct {
+	UserID         string `json:"user_id"`
+	Reason         string `json:"reason"`
+	OpenReviews    int    `json:"open_reviews,omitempty"`
+	MaxOpenReviews int    `json:"max_open_reviews,omitempty"`
+}
+
+type DeactivateUsersRequest struct {
+	TeamName string   `json:"team_name"`
+	UserIDs  []string `json:"user_ids"`
+}
+
+type ReviewerReplacement struct {
+	OldUserID string `json:"old_user_id"`
+	NewUserID string `json:"new_user_id"`
+}
+
+// PRReassignment - что стало с ревьюверами одного PR после массовой деактивации
+type PRReassignment struct {
+	PullRequestID     string                `json:"pull_request_id"`
+	Replacements      []ReviewerReplacement `json:"replacements"`
+	Unassigned        []string              `json:"unassigned"`
+	AssignedReviewers []string              `json:"assigned_reviewers"`
+	LeftShort         bool                  `json:"left_short"`
+}
+
+type DeactivateUsersResponse struct {
+	TeamName         string           `json:"team_name"`
+	DeactivatedUsers []string         `json:"deactivated_users"`
+	ReassignedPRs    int              `json:"reassigned_prs"`
+	ShortPRs         int              `json:"short_prs"`
+	PullRequests     []PRReassignment `json:"pull_requests"`
+}
+
+type AddTeamMembersRequest struct {
+	TeamName string       `json:"team_name"`
+	Members  []TeamMember `json:"members"`
+	// AllowMove разрешает забирать пользователей из других команд, иначе это ошибка USER_IN_OTHER_TEAM
+	AllowMove bool `json:"allow_move"`
+}
+
+type RemoveTeamMembersRequest struct {
+	TeamName string   `json:"team_name"`
+	UserIDs  []string `json:"user_ids"`
+}
+
+type RenameTeamRequest struct {
+	TeamName    string `json:"team_name"`
+	NewTeamName string `json:"new_team_name"`
+}
+
+type DeleteTeamRequest struct {
+	TeamName string `json:"team_name"`
+	// RemoveMembers разрешает удалить команду с участниками, иначе это ошибка TEAM_NOT_EMPTY
+	RemoveMembers bool `json:"remove_members"`
+}
+
+// MemberConflict - пользователь, который уже состоит в другой команде
+type MemberConflict struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+}
+
+// TeamMembershipResponse - отчёт об изменении состава команды. Открытые ревью ушедших из команды
+// переназначаются как при деактивации, их собственные открытые PR остаются с прежними ревьюверами.
+type TeamMembershipResponse struct {
+	TeamName        string           `json:"team_name"`
+	Added           []string         `json:"added"`
+	Updated         []string         `json:"updated"`
+	Moved           []MemberConflict `json:"moved"`
+	Removed         []string         `json:"removed"`
+	AuthoredOpenPRs []string         `json:"authored_open_prs"`
+	ReassignedPRs   int              `json:"reassigned_prs"`
+	ShortPRs        int              `json:"short_prs"`
+	PullRequests    []PRReassignment `json:"pull_requests"`
+}
+
+// AvailabilityPeriod - период отсутствия пользователя, в который он не назначается ревьювером
+type AvailabilityPeriod struct {
+	AvailabilityID  int64      `json:"availability_id"`
+	UserID          string     `json:"user_id"`
+	StartsAt        time.Time  `json:"starts_at"`
+	EndsAt          time.Time  `json:"ends_at"`
+	Reason          string     `json:"reason,omitempty"`
+	ReassignReviews bool       `json:"reassign_reviews"`
+	ReassignedAt    *time.Time `json:"reassigned_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+type CreateAvailabilityRequest struct {
+	UserID          string    `json:"user_id"`
+	StartsAt        time.Time `json:"starts_at"`
+	EndsAt          time.Time `json:"ends_at"`
+	Reason          string    `json:"reason,omitempty"`
+	ReassignReviews bool      `json:"reassign_reviews,omitempty"`
+}
+
+type UpdateAvailabilityRequest struct {
+	AvailabilityID  int64      `json:"availability_id"`
+	StartsAt        *time.Time `json:"starts_at,omitempty"`
+	EndsAt          *time.Time `json:"ends_at,omitempty"`
+	Reason          *string    `json:"reason,omitempty"`
+	ReassignReviews *bool      `json:"reassign_reviews,omitempty"`
+}
+
+type DeleteAvailabilityRequest struct {
+	AvailabilityID int64 `json:"availability_id"`
+}
+
+// AvailabilityReassignment - результат переназначения ревью при начале периода отсутствия
+type AvailabilityReassignment struct {
+	AvailabilityID int64            `json:"availability_id"`
+	UserID         string           `json:"user_id"`
+	PullRequests   []PRReassignment `json:"pull_requests"`
+}
+
+type GitHubLogin struct {
+	UserID      string `json:"user_id"`
+	GitHubLogin string `json:"github_login"`
+}
+
+type SetGitHubLoginRequest struct {
+	UserID      string `json:"user_id"`
+	GitHubLogin string `json:"github_login"`
+}
+
+type RemoveGitHubLoginRequest struct {
+	GitHubLogin string `json:"github_login"`
+}
+
 type CreatePRRequest struct {
 	PullRequestID   string `json:"pull_request_id"`
 	PullRequestName string `json:"pull_request_name"`
 	AuthorID        string `json:"author_id"`
+	// Repository выбирает CODEOWNERS и команду-владельца; по умолчанию берётся из id вида "owner/repo#42"
+	Repository   string   `json:"repository,omitempty"`
+	ChangedFiles []string `json:"changed_files,omitempty"`
+	// Labels - метки PR (db, security, ...), сравниваются с навыками ревьюверов
+	Labels []string `json:"labels,omitempty"`
+	// CodeOwners - владельцы изменённых файлов из CODEOWNERS, заполняет обработчик
+	CodeOwners []string `json:"-"`
 }
 
 type MergePRRequest struct {
 	PullRequestID string `json:"pull_request_id"`
+	Force         bool   `json:"force,omitempty"`
+}
+
+type ClosePRRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+}
+
+type ReopenPRRequest struct {
+	PullRequestID string `json:"pull_request_id"`
 }
 
 type ReassignRequest struct {
@@ -79,6 +416,64 @@ type ReassignRequest struct {
 	OldUserID     string `json:"old_user_id"`
 }
 
+type SubmitReviewRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReviewerID    string `json:"reviewer_id"`
+	Decision      string `json:"decision"`
+}
+
+type WebhookSubscription struct {
+	SubscriptionID string    `json:"subscription_id"`
+	URL            string    `json:"url"`
+	Secret         string    `json:"secret,omitempty"`
+	Events         []string  `json:"events"`
+	IsActive       bool      `json:"is_active"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type CreateWebhookSubscriptionRequest struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret,omitempty"`
+	Events []string `json:"events,omitempty"`
+}
+
+type UpdateWebhookSubscriptionRequest struct {
+	SubscriptionID string    `json:"subscription_id"`
+	URL            *string   `json:"url,omitempty"`
+	Secret         *string   `json:"secret,omitempty"`
+	Events         *[]string `json:"events,omitempty"`
+	IsActive       *bool     `json:"is_active,omitempty"`
+}
+
+type DeleteWebhookSubscriptionRequest struct {
+	SubscriptionID string `json:"subscription_id"`
+}
+
+type WebhookDelivery struct {
+	DeliveryID     int64           `json:"delivery_id"`
+	SubscriptionID string          `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+type WebhookDeliveryFilter struct {
+	SubscriptionID string
+	Status         string
+	Limit          int
+}
+
+type ReplayWebhookDeliveryRequest struct {
+	DeliveryID int64 `json:"delivery_id"`
+}
+
 type UserPRsResponse struct {
 	UserID       string             `json:"user_id"`
 	PullRequests []PullRequestShort `json:"pull_requests"`
@@ -110,6 +505,7 @@ type SystemStats struct {
 	TotalPRs        int     `json:"total_prs"`
 	TotalOpenPRs    int     `json:"total_open_prs"`
 	TotalMergedPRs  int     `json:"total_merged_prs"`
+	TotalClosedPRs  int     `json:"total_closed_prs"`
 	TotalReviews    int     `json:"total_reviews"`
 	AvgReviewsPerPR float64 `json:"avg_reviews_per_pr"`
 }
@@ -126,3 +522,116 @@ type StatsResponse struct {
 	UserStats    []UserStats   `json:"user_stats,omitempty"`
 	PRStats      []PRStats     `json:"pr_stats,omitempty"`
 }
+
+// Области действия токенов API: admin может всё, user - действует от имени своего пользователя, bot - только читает
+const (
+	ScopeAdmin = "admin"
+	ScopeUser  = "user"
+	ScopeBot   = "bot"
+)
+
+// Роли, которые выводятся из токена: admin - org_admin, bot - bot, user - team_lead для руководителей команд, иначе member
+const (
+	RoleOrgAdmin = "org_admin"
+	RoleTeamLead = "team_lead"
+	RoleMember   = "member"
+	RoleBot      = "bot"
+)
+
+// UserAccess - то, от чего зависят права пользователя: его команда и команды, которыми он руководит
+type UserAccess struct {
+	UserID    string   `json:"user_id"`
+	TeamName  string   `json:"team_name"`
+	LeadTeams []string `json:"lead_teams"`
+}
+
+type TeamLeadRequest struct {
+	TeamName string `json:"team_name"`
+	UserID   string `json:"user_id"`
+}
+
+type TeamLeadsResponse struct {
+	TeamName string   `json:"team_name"`
+	Leads    []string `json:"leads"`
+}
+
+// Permission - действие и то, над чем оно разрешено: над всеми, над собой и/или над перечисленными командами
+type Permission struct {
+	Action string   `json:"action"`
+	All    bool     `json:"all,omitempty"`
+	Self   bool     `json:"self,omitempty"`
+	Teams  []string `json:"teams,omitempty"`
+}
+
+type PermissionsResponse struct {
+	Role        string       `json:"role"`
+	TokenID     string       `json:"token_id,omitempty"`
+	UserID      string       `json:"user_id,omitempty"`
+	TeamName    string       `json:"team_name,omitempty"`
+	LeadTeams   []string     `json:"lead_teams"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// APIToken - выданный токен API; сам секрет не хранится, только его хэш
+type APIToken struct {
+	TokenID   string     `json:"token_id"`
+	Name      string     `json:"name"`
+	Scope     string     `json:"scope"`
+	UserID    string     `json:"user_id,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+type IssueTokenRequest struct {
+	Name   string `json:"name"`
+	Scope  string `json:"scope"`
+	UserID string `json:"user_id"`
+}
+
+type RevokeTokenRequest struct {
+	TokenID string `json:"token_id"`
+}
+
+// CodeOwnersFile - загруженный через API файл CODEOWNERS репозитория
+type CodeOwnersFile struct {
+	Repository string    `json:"repository"`
+	Content    string    `json:"content"`
+	Source     string    `json:"source"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Источники CODEOWNERS: загруженный через API имеет приоритет над файлом из CODEOWNERS_FILES
+const (
+	CodeOwnersSourceAPI  = "api"
+	CodeOwnersSourceFile = "file"
+)
+
+type SetCodeOwnersRequest struct {
+	Repository string `json:"repository"`
+	Content    string `json:"content"`
+}
+
+type DeleteCodeOwnersRequest struct {
+	Repository string `json:"repository"`
+}
+
+// Repository - репозиторий, PR в котором ревьюит команда-владелец, даже если автор из другой команды.
+// Незаданные настройки берутся из настроек команды.
+type Repository struct {
+	Repository        string    `json:"repository"`
+	TeamName          string    `json:"team_name"`
+	ReviewersCount    *int      `json:"reviewers_count"`
+	RequiredApprovals *int      `json:"required_approvals"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+type SetRepositoryRequest struct {
+	Repository        string `json:"repository"`
+	TeamName          string `json:"team_name"`
+	ReviewersCount    *int   `json:"reviewers_count,omitempty"`
+	RequiredApprovals *int   `json:"required_approvals,omitempty"`
+}
+
+type DeleteRepositoryRequest struct {
+	Repository string `json:"repository"`
+}
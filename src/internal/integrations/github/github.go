@@ -0,0 +1,90 @@
+package github
+
+import (
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/hex"
+    "fmt"
+    "strings"
+)
+
+const (
+    EventPing        = "ping"
+    EventPullRequest = "pull_request"
+
+    ActionOpened         = "opened"
+    ActionReopened       = "reopened"
+    ActionClosed         = "closed"
+    ActionReadyForReview = "ready_for_review"
+)
+
+type User struct {
+    Login string `json:"login"`
+}
+
+type Repository struct {
+    FullName string `json:"full_name"`
+}
+
+type Label struct {
+    Name string `json:"name"`
+}
+
+type PullRequest struct {
+    Number int     `json:"number"`
+    Title  string  `json:"title"`
+    Draft  bool    `json:"draft"`
+    Merged bool    `json:"merged"`
+    User   User    `json:"user"`
+    Labels []Label `json:"labels"`
+}
+
+// PullRequestEvent содержит поля события pull_request, которые нужны сервису
+type PullRequestEvent struct {
+    Action      string      `json:"action"`
+    Number      int         `json:"number"`
+    PullRequest PullRequest `json:"pull_request"`
+    Repository  Repository  `json:"repository"`
+}
+
+// PullRequestID строит идентификатор PR в сервисе вида "owner/repo#42"
+func (e PullRequestEvent) PullRequestID() string {
+    return fmt.Sprintf("%s#%d", e.Repository.FullName, e.PullRequest.Number)
+}
+
+// LabelNames возвращает названия меток PR
+func (e PullRequestEvent) LabelNames() []string {
+    names := make([]string, 0, len(e.PullRequest.Labels))
+    for _, label := range e.PullRequest.Labels {
+        names = append(names, label.Name)
+    }
+    return names
+}
+
+// VerifySignature проверяет заголовок X-Hub-Signature-256 ("sha256=<hex>") для тела запроса
+func VerifySignature(secret, body []byte, header string) bool {
+    if len(secret) == 0 {
+        return false
+    }
+
+    signature, ok := strings.CutPrefix(header, "sha256=")
+    if !ok {
+        return false
+    }
+    expected, err := hex.DecodeString(signature)
+    if err != nil {
+        return false
+    }
+
+    return hmac.Equal(expected, Sign(secret, body))
+}
+
+func Sign(secret, body []byte) []byte {
+    mac := hmac.New(sha256.New, secret)
+    mac.Write(body)
+    return mac.Sum(nil)
+}
+
+func NormalizeLogin(login string) string {
+    return strings.ToLower(strings.TrimSpace(login))
+}
@@ -0,0 +1,109 @@
+package outbox
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "time"
+
+    "pr-reviewer/src/internal/backoff"
+    "pr-reviewer/src/internal/storage"
+)
+
+type Config struct {
+    PollInterval  time.Duration
+    RetryBase     time.Duration
+    MaxRetryDelay time.Duration
+    BatchSize     int
+    Lease         time.Duration
+    // Retention - сколько хранить уже отправленные события
+    Retention time.Duration
+}
+
+func DefaultConfig() Config {
+    return Config{
+        PollInterval:  500 * time.Millisecond,
+        RetryBase:     time.Second,
+        MaxRetryDelay: 5 * time.Minute,
+        BatchSize:     100,
+        Lease:         time.Minute,
+        Retention:     7 * 24 * time.Hour,
+    }
+}
+
+// Dispatcher вычитывает outbox и передаёт каждое событие во все sinks.
+// Доставка "хотя бы один раз": если хотя бы один sink вернул ошибку, событие
+// будет отправлено повторно во все sinks.
+type Dispatcher struct {
+    repo   database.OutboxRepository
+    sinks  []Sink
+    config Config
+}
+
+func NewDispatcher(repo database.OutboxRepository, config Config, sinks ...Sink) *Dispatcher {
+    return &Dispatcher{repo: repo, sinks: sinks, config: config}
+}
+
+// Run отправляет события, пока не будет отменён ctx
+func (d *Dispatcher) Run(ctx context.Context) {
+    ticker := time.NewTicker(d.config.PollInterval)
+    defer ticker.Stop()
+
+    lastPurge := time.Now()
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+            d.DispatchPending(ctx)
+
+            if time.Since(lastPurge) >= time.Hour {
+                lastPurge = time.Now()
+                if _, err := d.repo.PurgeOutbox(d.config.Retention); err != nil {
+                    log.Printf("outbox: failed to purge dispatched events: %v", err)
+                }
+            }
+        }
+    }
+}
+
+// DispatchPending отправляет все события, время которых наступило
+func (d *Dispatcher) DispatchPending(ctx context.Context) {
+    for ctx.Err() == nil {
+        entries, err := d.repo.ClaimOutboxEvents(d.config.Lease, d.config.BatchSize)
+        if err != nil {
+            log.Printf("outbox: failed to claim events: %v", err)
+            return
+        }
+
+        for _, entry := range entries {
+            if err := d.send(ctx, entry); err != nil {
+                retryIn := d.retryDelay(entry.Attempts + 1)
+                log.Printf("outbox: event %s (%s) failed, retry in %v: %v", entry.Event.ID, entry.Event.Type, retryIn, err)
+                err = d.repo.MarkOutboxFailed(entry.ID, err.Error(), retryIn)
+            } else {
+                err = d.repo.MarkOutboxDispatched(entry.ID)
+            }
+            if err != nil {
+                log.Printf("outbox: failed to update event %s: %v", entry.Event.ID, err)
+            }
+        }
+
+        if len(entries) < d.config.BatchSize {
+            return
+        }
+    }
+}
+
+func (d *Dispatcher) send(ctx context.Context, entry database.OutboxEntry) error {
+    for _, sink := range d.sinks {
+        if err := sink.Send(ctx, entry.Event); err != nil {
+            return fmt.Errorf("%s: %w", sink.Name(), err)
+        }
+    }
+    return nil
+}
+
+func (d *Dispatcher) retryDelay(attempts int) time.Duration {
+    return backoff.Delay(d.config.RetryBase, d.config.MaxRetryDelay, attempts)
+}
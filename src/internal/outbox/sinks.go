@@ -0,0 +1,94 @@
+package outbox
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "strings"
+
+    "pr-reviewer/src/internal/domain/events"
+    "pr-reviewer/src/internal/storage"
+)
+
+const (
+    SinkWebhooks = "webhooks"
+    SinkLog      = "log"
+)
+
+// Sink получает события из outbox; Send должен быть идемпотентным по event.ID
+type Sink interface {
+    Name() string
+    Send(ctx context.Context, event events.Event) error
+}
+
+// WebhookSink ставит событие в очередь доставки HTTP-вебхуков всем подписчикам
+type WebhookSink struct {
+    repo database.WebhookRepository
+}
+
+func NewWebhookSink(repo database.WebhookRepository) *WebhookSink {
+    return &WebhookSink{repo: repo}
+}
+
+func (s *WebhookSink) Name() string {
+    return SinkWebhooks
+}
+
+func (s *WebhookSink) Send(ctx context.Context, event events.Event) error {
+    _, err := s.repo.EnqueueWebhookEvent(event)
+    return err
+}
+
+type LogSink struct{}
+
+func (LogSink) Name() string {
+    return SinkLog
+}
+
+func (LogSink) Send(ctx context.Context, event events.Event) error {
+    log.Printf("event %s %s: %s", event.ID, event.Type, event.Data)
+    return nil
+}
+
+// ChannelSink передаёт события в канал внутри процесса, например для тестов и встраивания сервиса
+type ChannelSink struct {
+    C chan events.Event
+}
+
+func NewChannelSink(buffer int) *ChannelSink {
+    return &ChannelSink{C: make(chan events.Event, buffer)}
+}
+
+func (s *ChannelSink) Name() string {
+    return "channel"
+}
+
+func (s *ChannelSink) Send(ctx context.Context, event events.Event) error {
+    select {
+    case s.C <- event:
+        return nil
+    case <-ctx.Done():
+        return ctx.Err()
+    }
+}
+
+// SinksFromNames собирает sinks по списку имён через запятую, например "webhooks,log".
+// Пустой список - ошибка: без sinks события помечались бы доставленными и терялись
+func SinksFromNames(names string, webhooks database.WebhookRepository) ([]Sink, error) {
+    var sinks []Sink
+    for _, name := range strings.Split(names, ",") {
+        switch strings.TrimSpace(name) {
+        case "":
+        case SinkWebhooks:
+            sinks = append(sinks, NewWebhookSink(webhooks))
+        case SinkLog:
+            sinks = append(sinks, LogSink{})
+        default:
+            return nil, fmt.Errorf("unknown outbox sink %q", name)
+        }
+    }
+    if len(sinks) == 0 {
+        return nil, fmt.Errorf("no outbox sinks configured in %q", names)
+    }
+    return sinks, nil
+}
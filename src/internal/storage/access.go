@@ -0,0 +1,127 @@
+package database
+
+import (
+    "database/sql"
+    "pr-reviewer/src/internal/domain/models"
+)
+
+// AddTeamLead назначает пользователя руководителем команды; состоять в ней он не обязан
+func (db *DB) AddTeamLead(teamName, userID string) (*models.TeamLeadsResponse, error) {
+    tx, err := db.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    if err := lockTeam(tx, teamName); err != nil {
+        return nil, err
+    }
+
+    result, err := tx.Exec(`
+        INSERT INTO team_leads (team_name, user_id)
+        SELECT $1, user_id FROM users WHERE user_id = $2
+        ON CONFLICT DO NOTHING
+    `, teamName, userID)
+    if err != nil {
+        return nil, err
+    }
+    if added, err := result.RowsAffected(); err != nil {
+        return nil, err
+    } else if added == 0 {
+        var exists bool
+        if err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE user_id = $1)", userID).Scan(&exists); err != nil {
+            return nil, err
+        }
+        if !exists {
+            return nil, ErrNotFound
+        }
+    }
+
+    leads, err := teamLeads(tx, teamName)
+    if err != nil {
+        return nil, err
+    }
+    return leads, tx.Commit()
+}
+
+// RemoveTeamLead снимает руководителя, ErrNotFound - если он не руководит командой
+func (db *DB) RemoveTeamLead(teamName, userID string) (*models.TeamLeadsResponse, error) {
+    tx, err := db.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    result, err := tx.Exec("DELETE FROM team_leads WHERE team_name = $1 AND user_id = $2", teamName, userID)
+    if err != nil {
+        return nil, err
+    }
+    removed, err := result.RowsAffected()
+    if err != nil {
+        return nil, err
+    }
+    if removed == 0 {
+        return nil, ErrNotFound
+    }
+
+    leads, err := teamLeads(tx, teamName)
+    if err != nil {
+        return nil, err
+    }
+    return leads, tx.Commit()
+}
+
+func (db *DB) ListTeamLeads(teamName string) (*models.TeamLeadsResponse, error) {
+    var exists bool
+    if err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)", teamName).Scan(&exists); err != nil {
+        return nil, err
+    }
+    if !exists {
+        return nil, ErrNotFound
+    }
+    return teamLeads(db, teamName)
+}
+
+func (db *DB) GetUserAccess(userID string) (*models.UserAccess, error) {
+    access := models.UserAccess{UserID: userID, LeadTeams: []string{}}
+    err := db.QueryRow("SELECT COALESCE(team_name, '') FROM users WHERE user_id = $1", userID).Scan(&access.TeamName)
+    if err == sql.ErrNoRows {
+        return nil, ErrNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    rows, err := db.Query("SELECT team_name FROM team_leads WHERE user_id = $1 ORDER BY team_name", userID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    for rows.Next() {
+        var teamName string
+        if err := rows.Scan(&teamName); err != nil {
+            return nil, err
+        }
+        access.LeadTeams = append(access.LeadTeams, teamName)
+    }
+    return &access, rows.Err()
+}
+
+func teamLeads(q queryer, teamName string) (*models.TeamLeadsResponse, error) {
+    rows, err := q.Query("SELECT user_id FROM team_leads WHERE team_name = $1 ORDER BY user_id", teamName)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    response := &models.TeamLeadsResponse{TeamName: teamName, Leads: []string{}}
+    for rows.Next() {
+        var userID string
+        if err := rows.Scan(&userID); err != nil {
+            return nil, err
+        }
+        response.Leads = append(response.Leads, userID)
+    }
+    return response, rows.Err()
+}
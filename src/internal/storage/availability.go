@@ -0,0 +1,168 @@
+package database
+
+import (
+    "database/sql"
+    "pr-reviewer/src/internal/domain/models"
+
+    "github.com/lib/pq"
+)
+
+const availabilityColumns = "availability_id, user_id, starts_at, ends_at, reason, reassign_reviews, reassigned_at, created_at"
+
+func (db *DB) CreateAvailability(period models.AvailabilityPeriod) (*models.AvailabilityPeriod, error) {
+    var exists bool
+    err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE user_id = $1)", period.UserID).Scan(&exists)
+    if err != nil {
+        return nil, err
+    }
+    if !exists {
+        return nil, ErrNotFound
+    }
+
+    return scanAvailability(db.QueryRow(`
+        INSERT INTO user_availability (user_id, starts_at, ends_at, reason, reassign_reviews)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING `+availabilityColumns,
+        period.UserID, period.StartsAt, period.EndsAt, period.Reason, period.ReassignReviews))
+}
+
+func (db *DB) GetAvailability(availabilityID int64) (*models.AvailabilityPeriod, error) {
+    return scanAvailability(db.QueryRow(
+        "SELECT "+availabilityColumns+" FROM user_availability WHERE availability_id = $1", availabilityID))
+}
+
+func (db *DB) ListAvailability(userID string, includePast bool) ([]models.AvailabilityPeriod, error) {
+    var exists bool
+    err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE user_id = $1)", userID).Scan(&exists)
+    if err != nil {
+        return nil, err
+    }
+    if !exists {
+        return nil, ErrNotFound
+    }
+
+    rows, err := db.Query(`
+        SELECT `+availabilityColumns+`
+        FROM user_availability
+        WHERE user_id = $1 AND ($2 OR ends_at > CURRENT_TIMESTAMP)
+        ORDER BY starts_at, availability_id
+    `, userID, includePast)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    periods := []models.AvailabilityPeriod{}
+    for rows.Next() {
+        period, err := scanAvailability(rows)
+        if err != nil {
+            return nil, err
+        }
+        periods = append(periods, *period)
+    }
+
+    return periods, rows.Err()
+}
+
+// UpdateAvailability сбрасывает reassigned_at при переносе начала, чтобы переназначение сработало заново
+func (db *DB) UpdateAvailability(period models.AvailabilityPeriod) (*models.AvailabilityPeriod, error) {
+    return scanAvailability(db.QueryRow(`
+        UPDATE user_availability
+        SET starts_at = $2, ends_at = $3, reason = $4, reassign_reviews = $5,
+            reassigned_at = CASE WHEN starts_at = $2 THEN reassigned_at END
+        WHERE availability_id = $1
+        RETURNING `+availabilityColumns,
+        period.AvailabilityID, period.StartsAt, period.EndsAt, period.Reason, period.ReassignReviews))
+}
+
+func (db *DB) DeleteAvailability(availabilityID int64) error {
+    result, err := db.Exec("DELETE FROM user_availability WHERE availability_id = $1", availabilityID)
+    if err != nil {
+        return err
+    }
+
+    affected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+    if affected == 0 {
+        return ErrNotFound
+    }
+
+    return nil
+}
+
+func (db *DB) StartDueAvailability() ([]models.AvailabilityReassignment, error) {
+    tx, err := db.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    rows, err := tx.Query(`
+        SELECT availability_id, user_id
+        FROM user_availability
+        WHERE reassign_reviews AND reassigned_at IS NULL
+        AND starts_at <= CURRENT_TIMESTAMP AND ends_at > CURRENT_TIMESTAMP
+        ORDER BY starts_at, availability_id
+        FOR UPDATE SKIP LOCKED
+    `)
+    if err != nil {
+        return nil, err
+    }
+
+    results := []models.AvailabilityReassignment{}
+    var ids []int64
+    for rows.Next() {
+        var item models.AvailabilityReassignment
+        if err := rows.Scan(&item.AvailabilityID, &item.UserID); err != nil {
+            rows.Close()
+            return nil, err
+        }
+        results = append(results, item)
+        ids = append(ids, item.AvailabilityID)
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+    if len(results) == 0 {
+        return results, nil
+    }
+
+    for i := range results {
+        if results[i].PullRequests, err = reassignOpenReviews(tx, []string{results[i].UserID}, models.ReasonUserUnavailable, models.ActorSystem); err != nil {
+            return nil, err
+        }
+    }
+
+    _, err = tx.Exec("UPDATE user_availability SET reassigned_at = CURRENT_TIMESTAMP WHERE availability_id = ANY($1)", pq.Array(ids))
+    if err != nil {
+        return nil, err
+    }
+
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+
+    return results, nil
+}
+
+func scanAvailability(row rowScanner) (*models.AvailabilityPeriod, error) {
+    var p models.AvailabilityPeriod
+    var reassignedAt sql.NullTime
+
+    err := row.Scan(&p.AvailabilityID, &p.UserID, &p.StartsAt, &p.EndsAt, &p.Reason, &p.ReassignReviews, &reassignedAt, &p.CreatedAt)
+    if err == sql.ErrNoRows {
+        return nil, ErrNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    if reassignedAt.Valid {
+        p.ReassignedAt = &reassignedAt.Time
+    }
+
+    return &p, nil
+}
@@ -0,0 +1,80 @@
+package database
+
+import (
+    "pr-reviewer/src/internal/domain/assignment"
+    "pr-reviewer/src/internal/domain/models"
+)
+
+// NoCandidateError сообщает, что ревьюверов не хватило, и объясняет, почему не подошли остальные
+type NoCandidateError struct {
+    Required int                         `json:"required"`
+    Found    int                         `json:"found"`
+    // MinLevel - не нашлось ревьювера не ниже этого уровня
+    MinLevel string                      `json:"min_level,omitempty"`
+    Excluded []models.CandidateExclusion `json:"excluded"`
+}
+
+func (e *NoCandidateError) Error() string {
+    return ErrNoCandidate.Error()
+}
+
+func (e *NoCandidateError) Is(target error) bool {
+    return target == ErrNoCandidate
+}
+
+// MemberState - участник команды с тем, что влияет на его назначение в ревьюверы конкретного PR
+type MemberState struct {
+    assignment.Candidate
+    IsActive bool
+    IsAuthor bool
+    Assigned bool
+    Away     bool
+    // MaxOpenReviews - действующее ограничение открытых ревью, 0 - без ограничения
+    MaxOpenReviews int
+}
+
+// ExclusionReason возвращает причину, по которой участника нельзя назначить, или пустую строку
+func (m MemberState) ExclusionReason() string {
+    switch {
+    case m.IsAuthor:
+        return models.ExcludedAuthor
+    case m.Assigned:
+        return models.ExcludedAssigned
+    case !m.IsActive:
+        return models.ExcludedInactive
+    case m.Away:
+        return models.ExcludedAway
+    case m.MaxOpenReviews > 0 && m.OpenReviews >= m.MaxOpenReviews:
+        return models.ExcludedAtCapacity
+    }
+    return ""
+}
+
+// SplitCandidates делит участников команды на кандидатов в ревьюверы и исключённых с причинами
+func SplitCandidates(members []MemberState) ([]assignment.Candidate, []models.CandidateExclusion) {
+    var candidates []assignment.Candidate
+    excluded := []models.CandidateExclusion{}
+    for _, m := range members {
+        reason := m.ExclusionReason()
+        if reason == "" {
+            candidates = append(candidates, m.Candidate)
+            continue
+        }
+
+        exclusion := models.CandidateExclusion{UserID: m.UserID, Reason: reason}
+        if reason == models.ExcludedAtCapacity {
+            exclusion.OpenReviews = m.OpenReviews
+            exclusion.MaxOpenReviews = m.MaxOpenReviews
+        }
+        excluded = append(excluded, exclusion)
+    }
+    return candidates, excluded
+}
+
+// EffectiveMaxOpenReviews выбирает личное ограничение пользователя, если оно задано, иначе ограничение команды
+func EffectiveMaxOpenReviews(userLimit *int, teamLimit int) int {
+    if userLimit != nil {
+        return *userLimit
+    }
+    return teamLimit
+}
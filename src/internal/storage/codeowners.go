@@ -0,0 +1,98 @@
+package database
+
+import (
+    "database/sql"
+    "pr-reviewer/src/internal/domain/models"
+    "strings"
+)
+
+func (db *DB) SetCodeOwners(repository, content string) (*models.CodeOwnersFile, error) {
+    file := models.CodeOwnersFile{Repository: repository, Source: models.CodeOwnersSourceAPI}
+    err := db.QueryRow(`
+        INSERT INTO code_owners (repository, content)
+        VALUES ($1, $2)
+        ON CONFLICT (repository)
+        DO UPDATE SET content = $2, updated_at = CURRENT_TIMESTAMP
+        RETURNING content, updated_at
+    `, repository, content).Scan(&file.Content, &file.UpdatedAt)
+    if err != nil {
+        return nil, err
+    }
+    return &file, nil
+}
+
+func (db *DB) GetCodeOwners(repository string) (*models.CodeOwnersFile, error) {
+    file := models.CodeOwnersFile{Repository: repository, Source: models.CodeOwnersSourceAPI}
+    err := db.QueryRow("SELECT content, updated_at FROM code_owners WHERE repository = $1", repository).
+        Scan(&file.Content, &file.UpdatedAt)
+    if err == sql.ErrNoRows {
+        return nil, ErrNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+    return &file, nil
+}
+
+func (db *DB) DeleteCodeOwners(repository string) error {
+    result, err := db.Exec("DELETE FROM code_owners WHERE repository = $1", repository)
+    if err != nil {
+        return err
+    }
+    deleted, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+    if deleted == 0 {
+        return ErrNotFound
+    }
+    return nil
+}
+
+// IsCodeOwner сообщает, назван ли участник команды среди владельцев: по user_id, по логину GitHub
+// или через @org/team, где team - название его команды в сервисе
+func IsCodeOwner(owners []string, userID, githubLogin, teamName string) bool {
+    for _, owner := range owners {
+        handle := strings.TrimPrefix(owner, "@")
+        if slash := strings.LastIndex(handle, "/"); slash >= 0 {
+            if teamName != "" && strings.EqualFold(handle[slash+1:], teamName) {
+                return true
+            }
+            continue
+        }
+        if handle == userID || (githubLogin != "" && strings.EqualFold(handle, githubLogin)) {
+            return true
+        }
+    }
+    return false
+}
+
+// codeOwnerIDs отбирает из участников команды владельцев изменённых файлов
+func codeOwnerIDs(tx *sql.Tx, teamName string, owners []string) (map[string]bool, error) {
+    result := make(map[string]bool)
+    if len(owners) == 0 {
+        return result, nil
+    }
+
+    rows, err := tx.Query(`
+        SELECT u.user_id, COALESCE(g.github_login, '')
+        FROM users u
+        LEFT JOIN github_users g ON g.user_id = u.user_id
+        WHERE u.team_name = $1
+    `, teamName)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    for rows.Next() {
+        var userID, login string
+        if err := rows.Scan(&userID, &login); err != nil {
+            return nil, err
+        }
+        if IsCodeOwner(owners, userID, login, teamName) {
+            result[userID] = true
+        }
+    }
+    return result, rows.Err()
+}
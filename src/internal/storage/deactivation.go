@@ -0,0 +1,421 @@
+package database
+
+import (
+    "database/sql"
+    "pr-reviewer/src/internal/domain/assignment"
+    "pr-reviewer/src/internal/domain/events"
+    "pr-reviewer/src/internal/domain/models"
+    "slices"
+    "sort"
+    "time"
+
+    "github.com/lib/pq"
+)
+
+// DeactivateTeamUsers деактивирует участников команды и в той же транзакции переназначает их открытые ревью
+func (db *DB) DeactivateTeamUsers(teamName string, userIDs []string, actor string) (*models.DeactivateUsersResponse, error) {
+    tx, err := db.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    deactivated, err := deactivateUsers(tx, teamName, userIDs)
+    if err != nil {
+        return nil, err
+    }
+
+    reassignments, err := reassignOpenReviews(tx, deactivated, models.ReasonUserDeactivated, actor)
+    if err != nil {
+        return nil, err
+    }
+
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+
+    return NewDeactivateUsersResponse(teamName, deactivated, reassignments), nil
+}
+
+func NewDeactivateUsersResponse(teamName string, deactivated []string, reassignments []models.PRReassignment) *models.DeactivateUsersResponse {
+    report := &models.DeactivateUsersResponse{
+        TeamName:         teamName,
+        DeactivatedUsers: deactivated,
+        PullRequests:     reassignments,
+    }
+    for _, item := range reassignments {
+        if item.LeftShort {
+            report.ShortPRs++
+        }
+        if len(item.Replacements) > 0 {
+            report.ReassignedPRs++
+        }
+    }
+    return report
+}
+
+// reassignOpenReviews снимает userIDs со всех открытых PR и подбирает им замены по правилам PickReplacement.
+// Данные читаются несколькими запросами целиком, подбор идёт в памяти, чтобы время
+// не росло с числом PR из-за отдельных запросов на каждый из них.
+func reassignOpenReviews(tx *sql.Tx, userIDs []string, reason, actor string) ([]models.PRReassignment, error) {
+    affected, err := openReviewsOf(tx, userIDs)
+    if err != nil {
+        return nil, err
+    }
+
+    reassignments := []models.PRReassignment{}
+    if len(affected) == 0 {
+        return reassignments, nil
+    }
+
+    pools := make(map[string]*reviewerPool)
+    poolOf := func(teamName string) (*reviewerPool, error) {
+        if pool, ok := pools[teamName]; ok {
+            return pool, nil
+        }
+        pool, err := loadReviewerPool(tx, teamName)
+        if err != nil {
+            return nil, err
+        }
+        pools[teamName] = pool
+        return pool, nil
+    }
+
+    var removedPRs, removedReviewers, replacedBy, addedPRs, addedReviewers []string
+    var addedFallback, addedShadow []bool
+    for _, pr := range affected {
+        pool, err := poolOf(pr.teamName)
+        if err != nil {
+            return nil, err
+        }
+        fallbackPools := make([]*reviewerPool, 0, len(pool.settings.FallbackTeams))
+        for _, team := range pool.settings.FallbackTeams {
+            fallbackPool, err := poolOf(team)
+            if err != nil {
+                return nil, err
+            }
+            fallbackPools = append(fallbackPools, fallbackPool)
+        }
+
+        minLevel := pool.settings.MinReviewerLevel
+        item := models.PRReassignment{PullRequestID: pr.id, Replacements: []models.ReviewerReplacement{}, Unassigned: []string{}}
+        for _, oldID := range pr.leaving {
+            removedPRs = append(removedPRs, pr.id)
+            removedReviewers = append(removedReviewers, oldID)
+
+            old := pr.remove(oldID)
+            fallbacks := make([]TeamCandidates, len(fallbackPools))
+            for i, fallbackPool := range fallbackPools {
+                fallbacks[i] = fallbackPool.eligible(pr)
+            }
+            newID, fallback, ok := PickReplacement(pool.eligible(pr), fallbacks, old.shadow, pr.levelCovered(minLevel), minLevel,
+                pool.skilled(pr))
+            replacedBy = append(replacedBy, newID)
+            if !ok {
+                item.Unassigned = append(item.Unassigned, oldID)
+                continue
+            }
+
+            added := affectedReviewer{userID: newID, shadow: old.shadow}
+            for _, owner := range append([]*reviewerPool{pool}, fallbackPools...) {
+                if candidate, ok := owner.assign(newID); ok {
+                    added.level = candidate.Level
+                    added.skilled = owner.hasSkill(newID, pr.labels)
+                }
+            }
+            pr.reviewers = append(pr.reviewers, added)
+            addedPRs = append(addedPRs, pr.id)
+            addedReviewers = append(addedReviewers, newID)
+            addedFallback = append(addedFallback, fallback)
+            addedShadow = append(addedShadow, old.shadow)
+            item.Replacements = append(item.Replacements, models.ReviewerReplacement{OldUserID: oldID, NewUserID: newID})
+        }
+
+        item.LeftShort = len(item.Unassigned) > 0
+        reassignments = append(reassignments, item)
+    }
+
+    if err := unassignReviewers(tx, removedPRs, removedReviewers, replacedBy, reason, actor); err != nil {
+        return nil, err
+    }
+
+    _, err = tx.Exec(`
+        INSERT INTO pr_reviewers (pull_request_id, reviewer_id, fallback, shadow)
+        SELECT * FROM unnest($1::text[], $2::text[], $3::boolean[], $4::boolean[])
+    `, pq.Array(addedPRs), pq.Array(addedReviewers), pq.Array(addedFallback), pq.Array(addedShadow))
+    if err != nil {
+        return nil, err
+    }
+
+    prIDs := make([]string, len(affected))
+    for i, pr := range affected {
+        prIDs[i] = pr.id
+    }
+    prs, err := getPullRequests(tx, prIDs)
+    if err != nil {
+        return nil, err
+    }
+
+    var evts []events.Event
+    for i, pr := range prs {
+        item := &reassignments[i]
+        item.AssignedReviewers = pr.AssignedReviewers
+        for _, r := range item.Replacements {
+            evts = append(evts, events.ReviewerReplacedEvent(pr, r.OldUserID, r.NewUserID))
+        }
+        for _, oldID := range item.Unassigned {
+            evts = append(evts, events.ReviewerRemovedEvent(pr, oldID))
+        }
+    }
+    if err := insertEvents(tx, evts...); err != nil {
+        return nil, err
+    }
+
+    return reassignments, nil
+}
+
+// deactivateUsers возвращает ErrNotFound, если кого-то из userIDs нет в команде
+func deactivateUsers(tx *sql.Tx, teamName string, userIDs []string) ([]string, error) {
+    rows, err := tx.Query(`
+        UPDATE users SET is_active = false
+        WHERE team_name = $1 AND user_id = ANY($2)
+        RETURNING user_id
+    `, teamName, pq.Array(userIDs))
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    deactivated := []string{}
+    for rows.Next() {
+        var userID string
+        if err := rows.Scan(&userID); err != nil {
+            return nil, err
+        }
+        deactivated = append(deactivated, userID)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    unique := make(map[string]bool)
+    for _, userID := range userIDs {
+        unique[userID] = true
+    }
+    if len(deactivated) != len(unique) {
+        return nil, ErrNotFound
+    }
+    sort.Strings(deactivated)
+
+    return deactivated, nil
+}
+
+type affectedPR struct {
+    id        string
+    authorID  string
+    teamName  string
+    labels    []string
+    reviewers []affectedReviewer
+    // leaving остаются недоступны для назначения на PR и после снятия
+    leaving []string
+}
+
+type affectedReviewer struct {
+    userID string
+    level  string
+    shadow bool
+    // skilled - навыки ревьювера пересекаются с метками PR
+    skilled bool
+}
+
+// remove снимает ревьювера с PR и возвращает его
+func (pr *affectedPR) remove(userID string) affectedReviewer {
+    for i, r := range pr.reviewers {
+        if r.userID == userID {
+            pr.reviewers = append(pr.reviewers[:i], pr.reviewers[i+1:]...)
+            return r
+        }
+    }
+    return affectedReviewer{userID: userID}
+}
+
+func (pr *affectedPR) unavailable(userID string) bool {
+    if userID == pr.authorID || slices.Contains(pr.leaving, userID) {
+        return true
+    }
+    return slices.ContainsFunc(pr.reviewers, func(r affectedReviewer) bool { return r.userID == userID })
+}
+
+// levelCovered повторяет replacedReviewer: остаётся ли среди основных ревьюверов кто-то не ниже minLevel
+func (pr *affectedPR) levelCovered(minLevel string) bool {
+    return minLevel == "" || slices.ContainsFunc(pr.reviewers, func(r affectedReviewer) bool {
+        return !r.shadow && assignment.AtLeast(r.level, minLevel)
+    })
+}
+
+func openReviewsOf(tx *sql.Tx, userIDs []string) ([]*affectedPR, error) {
+    rows, err := tx.Query(`
+        SELECT pr.pull_request_id, pr.author_id, COALESCE(repo.team_name, u.team_name, ''), pr.labels,
+            prr.reviewer_id, prr.reviewer_id = ANY($1), ru.level, prr.shadow,
+            EXISTS(SELECT 1 FROM user_skills s WHERE s.user_id = prr.reviewer_id AND s.skill = ANY(pr.labels))
+        FROM pull_requests pr
+        JOIN users u ON u.user_id = pr.author_id
+        LEFT JOIN repositories repo ON repo.repository = pr.repository
+        JOIN pr_reviewers prr ON prr.pull_request_id = pr.pull_request_id
+        JOIN users ru ON ru.user_id = prr.reviewer_id
+        WHERE pr.status = 'OPEN'
+        AND pr.pull_request_id IN (
+            SELECT pull_request_id FROM pr_reviewers WHERE reviewer_id = ANY($1)
+        )
+        ORDER BY pr.created_at, pr.pull_request_id, prr.assigned_at, prr.reviewer_id
+        FOR UPDATE OF pr
+    `, pq.Array(userIDs))
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var prs []*affectedPR
+    for rows.Next() {
+        var prID, authorID, teamName string
+        var labels []string
+        var r affectedReviewer
+        var leaving bool
+        err := rows.Scan(&prID, &authorID, &teamName, pq.Array(&labels), &r.userID, &leaving, &r.level, &r.shadow, &r.skilled)
+        if err != nil {
+            return nil, err
+        }
+        if len(prs) == 0 || prs[len(prs)-1].id != prID {
+            prs = append(prs, &affectedPR{id: prID, authorID: authorID, teamName: teamName, labels: labels})
+        }
+        pr := prs[len(prs)-1]
+        pr.reviewers = append(pr.reviewers, r)
+        if leaving {
+            pr.leaving = append(pr.leaving, r.userID)
+        }
+    }
+
+    return prs, rows.Err()
+}
+
+// reviewerPool - активные участники команды с текущей нагрузкой, которая обновляется по мере назначений,
+// и их навыками
+type reviewerPool struct {
+    settings   *models.TeamSettings
+    strategy   assignment.Strategy
+    candidates []assignment.Candidate
+    limits     map[string]int
+    skills     map[string][]string
+}
+
+func loadReviewerPool(tx *sql.Tx, teamName string) (*reviewerPool, error) {
+    settings, err := teamSettings(tx, teamName)
+    if err == sql.ErrNoRows {
+        settings = &models.TeamSettings{}
+        return &reviewerPool{settings: settings, strategy: teamStrategy(settings)}, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    rows, err := tx.Query(`
+        SELECT u.user_id, u.level, COALESCE(u.max_open_reviews, t.max_open_reviews), COUNT(p.pull_request_id), MAX(prr.assigned_at)
+        FROM users u
+        JOIN teams t ON t.team_name = u.team_name
+        LEFT JOIN pr_reviewers prr ON prr.reviewer_id = u.user_id
+        LEFT JOIN pull_requests p ON p.pull_request_id = prr.pull_request_id AND p.status = 'OPEN'
+        WHERE u.team_name = $1 AND u.is_active = true AND NOT EXISTS (
+            SELECT 1 FROM user_availability a
+            WHERE a.user_id = u.user_id AND a.starts_at <= CURRENT_TIMESTAMP AND a.ends_at > CURRENT_TIMESTAMP
+        )
+        GROUP BY u.user_id, t.team_name
+        ORDER BY u.user_id
+    `, teamName)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    pool := &reviewerPool{settings: settings, strategy: teamStrategy(settings), limits: make(map[string]int), skills: make(map[string][]string)}
+    for rows.Next() {
+        var candidate assignment.Candidate
+        var limit int
+        var lastAssignedAt sql.NullTime
+        if err := rows.Scan(&candidate.UserID, &candidate.Level, &limit, &candidate.OpenReviews, &lastAssignedAt); err != nil {
+            return nil, err
+        }
+        pool.limits[candidate.UserID] = limit
+        if lastAssignedAt.Valid {
+            candidate.LastAssignedAt = lastAssignedAt.Time
+        }
+        pool.candidates = append(pool.candidates, candidate)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    skillRows, err := tx.Query(`
+        SELECT s.user_id, s.skill
+        FROM user_skills s
+        JOIN users u ON u.user_id = s.user_id
+        WHERE u.team_name = $1
+    `, teamName)
+    if err != nil {
+        return nil, err
+    }
+    defer skillRows.Close()
+
+    for skillRows.Next() {
+        var userID, skill string
+        if err := skillRows.Scan(&userID, &skill); err != nil {
+            return nil, err
+        }
+        pool.skills[userID] = append(pool.skills[userID], skill)
+    }
+
+    return pool, skillRows.Err()
+}
+
+// eligible возвращает кандидатов команды на pr: без автора, уже назначенных, снимаемых
+// и тех, кто набрал максимум открытых ревью
+func (p *reviewerPool) eligible(pr *affectedPR) TeamCandidates {
+    eligible := make([]assignment.Candidate, 0, len(p.candidates))
+    for _, c := range p.candidates {
+        limit := p.limits[c.UserID]
+        if !pr.unavailable(c.UserID) && (limit == 0 || c.OpenReviews < limit) {
+            eligible = append(eligible, c)
+        }
+    }
+    return TeamCandidates{Strategy: p.strategy, Candidates: eligible}
+}
+
+// skilled повторяет replacementSkills: участники с навыками из меток pr нужны, только если
+// оставшиеся ревьюверы эти метки не покрывают
+func (p *reviewerPool) skilled(pr *affectedPR) map[string]bool {
+    result := make(map[string]bool)
+    if slices.ContainsFunc(pr.reviewers, func(r affectedReviewer) bool { return r.skilled }) {
+        return result
+    }
+    for userID := range p.skills {
+        if p.hasSkill(userID, pr.labels) {
+            result[userID] = true
+        }
+    }
+    return result
+}
+
+func (p *reviewerPool) hasSkill(userID string, labels []string) bool {
+    return slices.ContainsFunc(p.skills[userID], func(skill string) bool { return slices.Contains(labels, skill) })
+}
+
+// assign учитывает новое назначение userID в нагрузке, если он из этой команды
+func (p *reviewerPool) assign(userID string) (assignment.Candidate, bool) {
+    for i := range p.candidates {
+        if p.candidates[i].UserID == userID {
+            p.candidates[i].OpenReviews++
+            p.candidates[i].LastAssignedAt = time.Now()
+            return p.candidates[i], true
+        }
+    }
+    return assignment.Candidate{}, false
+}
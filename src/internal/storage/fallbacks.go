@@ -0,0 +1,97 @@
+package database
+
+import (
+    "database/sql"
+    "pr-reviewer/src/internal/domain/assignment"
+    "slices"
+
+    "github.com/lib/pq"
+)
+
+// fallbackTeams возвращает запасные команды teamName в порядке обращения к ним
+func fallbackTeams(q queryer, teamName string) ([]string, error) {
+    rows, err := q.Query("SELECT fallback_team FROM team_fallbacks WHERE team_name = $1 ORDER BY position", teamName)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    teams := []string{}
+    for rows.Next() {
+        var team string
+        if err := rows.Scan(&team); err != nil {
+            return nil, err
+        }
+        teams = append(teams, team)
+    }
+    return teams, rows.Err()
+}
+
+// setFallbackTeams заменяет список запасных команд; ErrNotFound, если какой-то из них нет
+func setFallbackTeams(tx *sql.Tx, teamName string, teams []string) error {
+    if _, err := tx.Exec("DELETE FROM team_fallbacks WHERE team_name = $1", teamName); err != nil {
+        return err
+    }
+
+    result, err := tx.Exec(`
+        INSERT INTO team_fallbacks (team_name, fallback_team, position)
+        SELECT $1, t.team_name, f.position
+        FROM unnest($2::text[]) WITH ORDINALITY AS f(team_name, position)
+        JOIN teams t ON t.team_name = f.team_name
+    `, teamName, pq.Array(teams))
+    if err != nil {
+        return err
+    }
+    inserted, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+    if int(inserted) != len(teams) {
+        return ErrNotFound
+    }
+    return nil
+}
+
+// TeamCandidates - кандидаты одной команды вместе с её стратегией выбора
+type TeamCandidates struct {
+    Strategy   assignment.Strategy
+    Candidates []assignment.Candidate
+}
+
+// PickFallback добирает до count ревьюверов из запасных команд по порядку: пока в первой есть подходящие
+// кандидаты, следующие не трогаются. Уже выбранные в picked пропускаются, match может быть nil.
+func PickFallback(teams []TeamCandidates, count int, picked []string, match func(assignment.Candidate) bool) []string {
+    var result []string
+    for _, team := range teams {
+        if len(result) >= count {
+            break
+        }
+
+        var eligible []assignment.Candidate
+        for _, candidate := range team.Candidates {
+            if !slices.Contains(picked, candidate.UserID) && (match == nil || match(candidate)) {
+                eligible = append(eligible, candidate)
+            }
+        }
+        result = append(result, team.Strategy.Pick(eligible, count-len(result))...)
+    }
+    return result
+}
+
+// fallbackCandidates возвращает кандидатов запасных команд на prID в порядке обращения к ним;
+// в каждой команде кандидатов выбирает её собственная стратегия
+func fallbackCandidates(tx *sql.Tx, teams []string, authorID, prID string) ([]TeamCandidates, error) {
+    result := make([]TeamCandidates, 0, len(teams))
+    for _, team := range teams {
+        settings, err := teamSettings(tx, team)
+        if err != nil {
+            return nil, err
+        }
+        candidates, _, err := reviewCandidates(tx, team, authorID, prID)
+        if err != nil {
+            return nil, err
+        }
+        result = append(result, TeamCandidates{Strategy: teamStrategy(settings), Candidates: candidates})
+    }
+    return result, nil
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+    "database/sql"
+    "pr-reviewer/src/internal/domain/models"
+)
+
+func (db *DB) SetGitHubLogin(userID, login string) (*models.GitHubLogin, error) {
+    tx, err := db.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    var exists bool
+    err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE user_id = $1)", userID).Scan(&exists)
+    if err != nil {
+        return nil, err
+    }
+    if !exists {
+        return nil, ErrNotFound
+    }
+
+    _, err = tx.Exec("DELETE FROM github_users WHERE user_id = $1 OR github_login = $2", userID, login)
+    if err != nil {
+        return nil, err
+    }
+
+    _, err = tx.Exec("INSERT INTO github_users (github_login, user_id) VALUES ($1, $2)", login, userID)
+    if err != nil {
+        return nil, err
+    }
+
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+
+    return &models.GitHubLogin{UserID: userID, GitHubLogin: login}, nil
+}
+
+func (db *DB) RemoveGitHubLogin(login string) error {
+    result, err := db.Exec("DELETE FROM github_users WHERE github_login = $1", login)
+    if err != nil {
+        return err
+    }
+
+    affected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+    if affected == 0 {
+        return ErrNotFound
+    }
+
+    return nil
+}
+
+func (db *DB) GetUserIDByGitHubLogin(login string) (string, error) {
+    var userID string
+    err := db.QueryRow("SELECT user_id FROM github_users WHERE github_login = $1", login).Scan(&userID)
+    if err == sql.ErrNoRows {
+        return "", ErrNotFound
+    }
+    if err != nil {
+        return "", err
+    }
+
+    return userID, nil
+}
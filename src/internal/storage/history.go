@@ -0,0 +1,113 @@
+package database
+
+import (
+    "database/sql"
+    "pr-reviewer/src/internal/domain/models"
+
+    "github.com/lib/pq"
+)
+
+func (db *DB) GetPullRequestHistory(prID string) (*models.PullRequestHistory, error) {
+    var exists bool
+    err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM pull_requests WHERE pull_request_id = $1)", prID).Scan(&exists)
+    if err != nil {
+        return nil, err
+    }
+    if !exists {
+        return nil, ErrNotFound
+    }
+
+    rows, err := db.Query(`
+        SELECT history_id, pull_request_id, reviewer_id, action, COALESCE(replaced_by, ''), reason, actor, assigned_at, created_at
+        FROM pr_reviewer_history
+        WHERE pull_request_id = $1
+        ORDER BY history_id
+    `, prID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    entries := []models.ReviewerHistoryEntry{}
+    for rows.Next() {
+        var e models.ReviewerHistoryEntry
+        var assignedAt sql.NullTime
+        err := rows.Scan(&e.HistoryID, &e.PullRequestID, &e.ReviewerID, &e.Action, &e.ReplacedBy, &e.Reason, &e.Actor,
+            &assignedAt, &e.CreatedAt)
+        if err != nil {
+            return nil, err
+        }
+        if assignedAt.Valid {
+            e.AssignedAt = &assignedAt.Time
+        }
+        entries = append(entries, e)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    return NewPullRequestHistory(prID, entries), nil
+}
+
+func NewPullRequestHistory(prID string, entries []models.ReviewerHistoryEntry) *models.PullRequestHistory {
+    history := &models.PullRequestHistory{PullRequestID: prID, History: entries}
+    for _, e := range entries {
+        if e.Action == models.HistoryReplaced {
+            history.Reassignments++
+        }
+    }
+    return history
+}
+
+// reviewerKind - как ревьювер попал на PR
+type reviewerKind int
+
+const (
+    regularReviewer reviewerKind = iota
+    // fallbackReviewer взят из запасной команды
+    fallbackReviewer
+    // shadowReviewer - junior сверх reviewers_count, не входит в кворум
+    shadowReviewer
+)
+
+// assignReviewers назначает ревьюверов на PR и записывает это в историю одним запросом
+func assignReviewers(tx *sql.Tx, prID string, reviewerIDs []string, kind reviewerKind, reason, actor string) error {
+    if len(reviewerIDs) == 0 {
+        return nil
+    }
+
+    _, err := tx.Exec(`
+        WITH added AS (
+            INSERT INTO pr_reviewers (pull_request_id, reviewer_id, fallback, shadow)
+            SELECT $1, unnest($2::text[]), $5, $6
+            RETURNING pull_request_id, reviewer_id, assigned_at
+        )
+        INSERT INTO pr_reviewer_history (pull_request_id, reviewer_id, action, reason, actor, created_at)
+        SELECT pull_request_id, reviewer_id, 'ASSIGNED', $3, $4, assigned_at FROM added
+    `, prID, pq.Array(reviewerIDs), reason, actor, kind == fallbackReviewer, kind == shadowReviewer)
+    return err
+}
+
+// unassignReviewers снимает ревьюверов с PR и записывает REPLACED (если есть замена) или REMOVED.
+// Замены передаются параллельно reviewerIDs, пустая строка - без замены; сами замены не назначаются.
+func unassignReviewers(tx *sql.Tx, prIDs, reviewerIDs, replacedBy []string, reason, actor string) error {
+    if len(prIDs) == 0 {
+        return nil
+    }
+
+    _, err := tx.Exec(`
+        WITH r AS (
+            SELECT * FROM unnest($1::text[], $2::text[], $3::text[]) AS r(pull_request_id, reviewer_id, replaced_by)
+        ), removed AS (
+            DELETE FROM pr_reviewers prr
+            USING r
+            WHERE prr.pull_request_id = r.pull_request_id AND prr.reviewer_id = r.reviewer_id
+            RETURNING prr.pull_request_id, prr.reviewer_id, prr.assigned_at, r.replaced_by
+        )
+        INSERT INTO pr_reviewer_history (pull_request_id, reviewer_id, action, replaced_by, reason, actor, assigned_at)
+        SELECT pull_request_id, reviewer_id, CASE WHEN replaced_by = '' THEN 'REMOVED' ELSE 'REPLACED' END,
+            NULLIF(replaced_by, ''), $4, $5, assigned_at
+        FROM removed
+    `, pq.Array(prIDs), pq.Array(reviewerIDs), pq.Array(replacedBy), reason, actor)
+    return err
+}
@@ -0,0 +1,330 @@
+package database
+
+import (
+    "database/sql"
+    "errors"
+    "pr-reviewer/src/internal/domain/assignment"
+    "pr-reviewer/src/internal/domain/models"
+    "sort"
+
+    "github.com/lib/pq"
+)
+
+var (
+    ErrUserInOtherTeam = errors.New("USER_IN_OTHER_TEAM")
+    ErrTeamNotEmpty    = errors.New("TEAM_NOT_EMPTY")
+)
+
+// MemberConflictError перечисляет пользователей, которых без allow_move нельзя забрать из их команд
+type MemberConflictError struct {
+    Conflicts []models.MemberConflict `json:"conflicts"`
+}
+
+func (e *MemberConflictError) Error() string {
+    return ErrUserInOtherTeam.Error()
+}
+
+func (e *MemberConflictError) Is(target error) bool {
+    return target == ErrUserInOtherTeam
+}
+
+// AddTeamMembers добавляет пользователей в команду или обновляет их данные. Пользователи из других команд
+// переводятся только с allowMove, их открытые ревью в той же транзакции переназначаются в прежних командах.
+func (db *DB) AddTeamMembers(teamName string, members []models.TeamMember, allowMove bool, actor string) (*models.TeamMembershipResponse, error) {
+    tx, err := db.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    if err := lockTeam(tx, teamName); err != nil {
+        return nil, err
+    }
+
+    userIDs := make([]string, len(members))
+    for i, member := range members {
+        userIDs[i] = member.UserID
+    }
+    current, err := currentTeams(tx, userIDs)
+    if err != nil {
+        return nil, err
+    }
+
+    conflicts := MemberConflicts(teamName, current)
+    if len(conflicts) > 0 && !allowMove {
+        return nil, &MemberConflictError{Conflicts: conflicts}
+    }
+
+    report := NewTeamMembershipResponse(teamName)
+    for _, member := range members {
+        if err := upsertMember(tx, teamName, member); err != nil {
+            return nil, err
+        }
+        if current[member.UserID] == teamName {
+            report.Updated = append(report.Updated, member.UserID)
+        } else if current[member.UserID] == "" {
+            report.Added = append(report.Added, member.UserID)
+        }
+    }
+    report.Moved = conflicts
+
+    moved := make([]string, len(conflicts))
+    for i, conflict := range conflicts {
+        moved[i] = conflict.UserID
+    }
+    if err := detachReviews(tx, report, moved, models.ReasonUserMoved, actor); err != nil {
+        return nil, err
+    }
+
+    return report, tx.Commit()
+}
+
+// RemoveTeamMembers оставляет пользователей без команды и переназначает их открытые ревью
+func (db *DB) RemoveTeamMembers(teamName string, userIDs []string, actor string) (*models.TeamMembershipResponse, error) {
+    tx, err := db.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    if err := lockTeam(tx, teamName); err != nil {
+        return nil, err
+    }
+
+    report, err := detachMembers(tx, teamName, userIDs, actor)
+    if err != nil {
+        return nil, err
+    }
+
+    return report, tx.Commit()
+}
+
+func (db *DB) RenameTeam(teamName, newTeamName string) error {
+    tx, err := db.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    var exists bool
+    err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)", newTeamName).Scan(&exists)
+    if err != nil {
+        return err
+    }
+    if exists {
+        return ErrTeamExists
+    }
+
+    // участники переезжают за командой через ON UPDATE CASCADE
+    result, err := tx.Exec("UPDATE teams SET team_name = $2 WHERE team_name = $1", teamName, newTeamName)
+    if err != nil {
+        return err
+    }
+    renamed, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+    if renamed == 0 {
+        return ErrNotFound
+    }
+
+    return tx.Commit()
+}
+
+// DeleteTeam удаляет команду; непустую - только с removeMembers, тогда участники уходят как через RemoveTeamMembers
+func (db *DB) DeleteTeam(teamName string, removeMembers bool, actor string) (*models.TeamMembershipResponse, error) {
+    tx, err := db.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    if err := lockTeam(tx, teamName); err != nil {
+        return nil, err
+    }
+
+    rows, err := tx.Query("SELECT user_id FROM users WHERE team_name = $1 ORDER BY user_id FOR UPDATE", teamName)
+    if err != nil {
+        return nil, err
+    }
+    var userIDs []string
+    for rows.Next() {
+        var userID string
+        if err := rows.Scan(&userID); err != nil {
+            rows.Close()
+            return nil, err
+        }
+        userIDs = append(userIDs, userID)
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    if len(userIDs) > 0 && !removeMembers {
+        return nil, ErrTeamNotEmpty
+    }
+
+    report, err := detachMembers(tx, teamName, userIDs, actor)
+    if err != nil {
+        return nil, err
+    }
+
+    if _, err := tx.Exec("DELETE FROM teams WHERE team_name = $1", teamName); err != nil {
+        return nil, err
+    }
+
+    return report, tx.Commit()
+}
+
+// MemberConflicts выбирает из текущих команд пользователей тех, кто состоит в команде, отличной от teamName
+func MemberConflicts(teamName string, current map[string]string) []models.MemberConflict {
+    conflicts := []models.MemberConflict{}
+    for userID, team := range current {
+        if team != "" && team != teamName {
+            conflicts = append(conflicts, models.MemberConflict{UserID: userID, TeamName: team})
+        }
+    }
+    sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].UserID < conflicts[j].UserID })
+    return conflicts
+}
+
+// CountReassignments заполняет счётчики отчёта по уже собранным PullRequests
+func CountReassignments(report *models.TeamMembershipResponse) {
+    for _, item := range report.PullRequests {
+        if item.LeftShort {
+            report.ShortPRs++
+        }
+        if len(item.Replacements) > 0 {
+            report.ReassignedPRs++
+        }
+    }
+}
+
+func NewTeamMembershipResponse(teamName string) *models.TeamMembershipResponse {
+    return &models.TeamMembershipResponse{
+        TeamName:        teamName,
+        Added:           []string{},
+        Updated:         []string{},
+        Moved:           []models.MemberConflict{},
+        Removed:         []string{},
+        AuthoredOpenPRs: []string{},
+        PullRequests:    []models.PRReassignment{},
+    }
+}
+
+// detachMembers оставляет участников без команды, ErrNotFound - если кого-то из userIDs нет в команде
+func detachMembers(tx *sql.Tx, teamName string, userIDs []string, actor string) (*models.TeamMembershipResponse, error) {
+    rows, err := tx.Query(`
+        UPDATE users SET team_name = NULL
+        WHERE team_name = $1 AND user_id = ANY($2)
+        RETURNING user_id
+    `, teamName, pq.Array(userIDs))
+    if err != nil {
+        return nil, err
+    }
+
+    report := NewTeamMembershipResponse(teamName)
+    for rows.Next() {
+        var userID string
+        if err := rows.Scan(&userID); err != nil {
+            rows.Close()
+            return nil, err
+        }
+        report.Removed = append(report.Removed, userID)
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    unique := make(map[string]bool)
+    for _, userID := range userIDs {
+        unique[userID] = true
+    }
+    if len(report.Removed) != len(unique) {
+        return nil, ErrNotFound
+    }
+    sort.Strings(report.Removed)
+
+    if err := detachReviews(tx, report, report.Removed, models.ReasonUserRemoved, actor); err != nil {
+        return nil, err
+    }
+    return report, nil
+}
+
+// detachReviews переназначает открытые ревью ушедших из команды и находит их собственные открытые PR
+func detachReviews(tx *sql.Tx, report *models.TeamMembershipResponse, userIDs []string, reason, actor string) error {
+    if len(userIDs) == 0 {
+        return nil
+    }
+
+    reassignments, err := reassignOpenReviews(tx, userIDs, reason, actor)
+    if err != nil {
+        return err
+    }
+    report.PullRequests = reassignments
+    CountReassignments(report)
+
+    rows, err := tx.Query(`
+        SELECT pull_request_id FROM pull_requests
+        WHERE status = 'OPEN' AND author_id = ANY($1)
+        ORDER BY created_at, pull_request_id
+    `, pq.Array(userIDs))
+    if err != nil {
+        return err
+    }
+    defer rows.Close()
+
+    for rows.Next() {
+        var prID string
+        if err := rows.Scan(&prID); err != nil {
+            return err
+        }
+        report.AuthoredOpenPRs = append(report.AuthoredOpenPRs, prID)
+    }
+    return rows.Err()
+}
+
+func lockTeam(tx *sql.Tx, teamName string) error {
+    var locked string
+    err := tx.QueryRow("SELECT team_name FROM teams WHERE team_name = $1 FOR UPDATE", teamName).Scan(&locked)
+    if err == sql.ErrNoRows {
+        return ErrNotFound
+    }
+    return err
+}
+
+// currentTeams возвращает команды существующих пользователей, пустая строка - пользователь без команды
+func currentTeams(tx *sql.Tx, userIDs []string) (map[string]string, error) {
+    rows, err := tx.Query(`
+        SELECT user_id, COALESCE(team_name, '') FROM users
+        WHERE user_id = ANY($1)
+        FOR UPDATE
+    `, pq.Array(userIDs))
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    current := make(map[string]string)
+    for rows.Next() {
+        var userID, teamName string
+        if err := rows.Scan(&userID, &teamName); err != nil {
+            return nil, err
+        }
+        current[userID] = teamName
+    }
+    return current, rows.Err()
+}
+
+// upsertMember заводит пользователя или обновляет его; пустой level не меняет уровень
+func upsertMember(tx *sql.Tx, teamName string, member models.TeamMember) error {
+    _, err := tx.Exec(`
+        INSERT INTO users (user_id, username, team_name, is_active, level)
+        VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), $6))
+        ON CONFLICT (user_id)
+        DO UPDATE SET username = $2, team_name = $3, is_active = $4, level = COALESCE(NULLIF($5, ''), users.level)
+    `, member.UserID, member.Username, teamName, member.IsActive, member.Level, assignment.DefaultLevel)
+    return err
+}
@@ -0,0 +1,76 @@
+package memory
+
+import (
+    "sort"
+
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/storage"
+)
+
+func (s *Store) AddTeamLead(teamName, userID string) (*models.TeamLeadsResponse, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    t, exists := s.teams[teamName]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+    if _, exists := s.users[userID]; !exists {
+        return nil, database.ErrNotFound
+    }
+
+    t.leads[userID] = true
+    return teamLeads(teamName, t), nil
+}
+
+func (s *Store) RemoveTeamLead(teamName, userID string) (*models.TeamLeadsResponse, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    t, exists := s.teams[teamName]
+    if !exists || !t.leads[userID] {
+        return nil, database.ErrNotFound
+    }
+
+    delete(t.leads, userID)
+    return teamLeads(teamName, t), nil
+}
+
+func (s *Store) ListTeamLeads(teamName string) (*models.TeamLeadsResponse, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    t, exists := s.teams[teamName]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+    return teamLeads(teamName, t), nil
+}
+
+func (s *Store) GetUserAccess(userID string) (*models.UserAccess, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    u, exists := s.users[userID]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+
+    access := &models.UserAccess{UserID: userID, TeamName: u.TeamName, LeadTeams: []string{}}
+    for teamName, t := range s.teams {
+        if t.leads[userID] {
+            access.LeadTeams = append(access.LeadTeams, teamName)
+        }
+    }
+    sort.Strings(access.LeadTeams)
+    return access, nil
+}
+
+func teamLeads(teamName string, t *team) *models.TeamLeadsResponse {
+    response := &models.TeamLeadsResponse{TeamName: teamName, Leads: []string{}}
+    for userID := range t.leads {
+        response.Leads = append(response.Leads, userID)
+    }
+    sort.Strings(response.Leads)
+    return response
+}
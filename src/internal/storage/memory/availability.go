@@ -0,0 +1,150 @@
+package memory
+
+import (
+    "sort"
+    "time"
+
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/storage"
+)
+
+func (s *Store) CreateAvailability(period models.AvailabilityPeriod) (*models.AvailabilityPeriod, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if _, exists := s.users[period.UserID]; !exists {
+        return nil, database.ErrNotFound
+    }
+
+    s.lastAvailabilityID++
+    period.AvailabilityID = s.lastAvailabilityID
+    period.ReassignedAt = nil
+    period.CreatedAt = time.Now()
+    s.availability[period.AvailabilityID] = &period
+
+    return copyAvailability(&period), nil
+}
+
+func (s *Store) GetAvailability(availabilityID int64) (*models.AvailabilityPeriod, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    period, exists := s.availability[availabilityID]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+
+    return copyAvailability(period), nil
+}
+
+func (s *Store) ListAvailability(userID string, includePast bool) ([]models.AvailabilityPeriod, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if _, exists := s.users[userID]; !exists {
+        return nil, database.ErrNotFound
+    }
+
+    now := time.Now()
+    periods := []models.AvailabilityPeriod{}
+    for _, period := range s.availability {
+        if period.UserID == userID && (includePast || period.EndsAt.After(now)) {
+            periods = append(periods, *copyAvailability(period))
+        }
+    }
+    sort.Slice(periods, func(i, j int) bool {
+        if !periods[i].StartsAt.Equal(periods[j].StartsAt) {
+            return periods[i].StartsAt.Before(periods[j].StartsAt)
+        }
+        return periods[i].AvailabilityID < periods[j].AvailabilityID
+    })
+
+    return periods, nil
+}
+
+func (s *Store) UpdateAvailability(period models.AvailabilityPeriod) (*models.AvailabilityPeriod, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    existing, exists := s.availability[period.AvailabilityID]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+
+    if !existing.StartsAt.Equal(period.StartsAt) {
+        existing.ReassignedAt = nil
+    }
+    existing.StartsAt = period.StartsAt
+    existing.EndsAt = period.EndsAt
+    existing.Reason = period.Reason
+    existing.ReassignReviews = period.ReassignReviews
+
+    return copyAvailability(existing), nil
+}
+
+func (s *Store) DeleteAvailability(availabilityID int64) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if _, exists := s.availability[availabilityID]; !exists {
+        return database.ErrNotFound
+    }
+    delete(s.availability, availabilityID)
+
+    return nil
+}
+
+func (s *Store) StartDueAvailability() ([]models.AvailabilityReassignment, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    now := time.Now()
+    var due []*models.AvailabilityPeriod
+    for _, period := range s.availability {
+        if period.ReassignReviews && period.ReassignedAt == nil && isCurrent(period, now) {
+            due = append(due, period)
+        }
+    }
+    sort.Slice(due, func(i, j int) bool {
+        if !due[i].StartsAt.Equal(due[j].StartsAt) {
+            return due[i].StartsAt.Before(due[j].StartsAt)
+        }
+        return due[i].AvailabilityID < due[j].AvailabilityID
+    })
+
+    results := []models.AvailabilityReassignment{}
+    for _, period := range due {
+        results = append(results, models.AvailabilityReassignment{
+            AvailabilityID: period.AvailabilityID,
+            UserID:         period.UserID,
+            PullRequests:   s.reassignOpenReviews(map[string]bool{period.UserID: true}, models.ReasonUserUnavailable, models.ActorSystem),
+        })
+        reassignedAt := now
+        period.ReassignedAt = &reassignedAt
+    }
+
+    return results, nil
+}
+
+// isAway сообщает, попадает ли now в какой-нибудь период отсутствия пользователя
+func (s *Store) isAway(userID string, now time.Time) bool {
+    for _, period := range s.availability {
+        if period.UserID == userID && isCurrent(period, now) {
+            return true
+        }
+    }
+    return false
+}
+
+func isCurrent(period *models.AvailabilityPeriod, now time.Time) bool {
+    return !period.StartsAt.After(now) && period.EndsAt.After(now)
+}
+
+func copyAvailability(period *models.AvailabilityPeriod) *models.AvailabilityPeriod {
+    result := *period
+    if period.ReassignedAt != nil {
+        reassignedAt := *period.ReassignedAt
+        result.ReassignedAt = &reassignedAt
+    }
+    return &result
+}
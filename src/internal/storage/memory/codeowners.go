@@ -0,0 +1,66 @@
+package memory
+
+import (
+    "time"
+
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/storage"
+)
+
+func (s *Store) SetCodeOwners(repository, content string) (*models.CodeOwnersFile, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    file := &models.CodeOwnersFile{
+        Repository: repository,
+        Content:    content,
+        Source:     models.CodeOwnersSourceAPI,
+        UpdatedAt:  time.Now(),
+    }
+    s.codeOwners[repository] = file
+
+    result := *file
+    return &result, nil
+}
+
+func (s *Store) GetCodeOwners(repository string) (*models.CodeOwnersFile, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    file, exists := s.codeOwners[repository]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+    result := *file
+    return &result, nil
+}
+
+func (s *Store) DeleteCodeOwners(repository string) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if _, exists := s.codeOwners[repository]; !exists {
+        return database.ErrNotFound
+    }
+    delete(s.codeOwners, repository)
+    return nil
+}
+
+// codeOwnerIDs отбирает из участников команды владельцев изменённых файлов
+func (s *Store) codeOwnerIDs(teamName string, owners []string) map[string]bool {
+    result := make(map[string]bool)
+    if len(owners) == 0 {
+        return result
+    }
+
+    logins := make(map[string]string, len(s.githubLogins))
+    for login, userID := range s.githubLogins {
+        logins[userID] = login
+    }
+    for _, u := range s.teamMembers(teamName) {
+        if database.IsCodeOwner(owners, u.UserID, logins[u.UserID], teamName) {
+            result[u.UserID] = true
+        }
+    }
+    return result
+}
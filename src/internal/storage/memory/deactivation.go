@@ -0,0 +1,108 @@
+package memory
+
+import (
+    "pr-reviewer/src/internal/domain/assignment"
+    "pr-reviewer/src/internal/domain/events"
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/storage"
+)
+
+func (s *Store) DeactivateTeamUsers(teamName string, userIDs []string, actor string) (*models.DeactivateUsersResponse, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    leaving := make(map[string]bool)
+    for _, userID := range userIDs {
+        u, exists := s.users[userID]
+        if !exists || u.TeamName != teamName {
+            return nil, database.ErrNotFound
+        }
+        leaving[userID] = true
+    }
+
+    deactivated := []string{}
+    for _, u := range s.teamMembers(teamName) {
+        if leaving[u.UserID] {
+            u.IsActive = false
+            deactivated = append(deactivated, u.UserID)
+        }
+    }
+
+    return database.NewDeactivateUsersResponse(teamName, deactivated, s.reassignOpenReviews(leaving, models.ReasonUserDeactivated, actor)), nil
+}
+
+// reassignOpenReviews снимает leaving со всех открытых PR и подбирает им замены, как одноимённая функция DB
+func (s *Store) reassignOpenReviews(leaving map[string]bool, reason, actor string) []models.PRReassignment {
+    reassignments := []models.PRReassignment{}
+    loads := s.reviewerLoads()
+    var evts []events.Event
+    for _, pr := range s.sortedPullRequests() {
+        if pr.status != "OPEN" {
+            continue
+        }
+
+        item := models.PRReassignment{PullRequestID: pr.id, Replacements: []models.ReviewerReplacement{}, Unassigned: []string{}}
+        var removed []string
+        for _, r := range pr.reviewers {
+            if leaving[r.userID] {
+                removed = append(removed, r.userID)
+            }
+        }
+        if len(removed) == 0 {
+            continue
+        }
+
+        for _, oldID := range removed {
+            replacement, _, ok := s.pickReplacement(pr, oldID, loads, leaving)
+            if !ok {
+                s.unassignReviewer(pr, oldID, "", reason, actor)
+                item.Unassigned = append(item.Unassigned, oldID)
+                continue
+            }
+
+            s.unassignReviewer(pr, oldID, replacement.userID, reason, actor)
+            pr.reviewers = append(pr.reviewers, replacement)
+            load := loads[replacement.userID]
+            load.OpenReviews++
+            load.LastAssignedAt = replacement.assignedAt
+            loads[replacement.userID] = load
+            item.Replacements = append(item.Replacements, models.ReviewerReplacement{OldUserID: oldID, NewUserID: replacement.userID})
+        }
+
+        result := pr.toModel()
+        item.AssignedReviewers = result.AssignedReviewers
+        item.LeftShort = len(item.Unassigned) > 0
+        reassignments = append(reassignments, item)
+
+        for _, r := range item.Replacements {
+            evts = append(evts, events.ReviewerReplacedEvent(result, r.OldUserID, r.NewUserID))
+        }
+        for _, oldID := range item.Unassigned {
+            evts = append(evts, events.ReviewerRemovedEvent(result, oldID))
+        }
+    }
+    s.addEvents(evts...)
+
+    return reassignments
+}
+
+// reviewerLoads считает открытые ревью и время последнего назначения всех пользователей за один проход
+func (s *Store) reviewerLoads() map[string]assignment.Candidate {
+    loads := make(map[string]assignment.Candidate, len(s.users))
+    for userID := range s.users {
+        loads[userID] = assignment.Candidate{UserID: userID}
+    }
+    for _, pr := range s.prs {
+        for _, r := range pr.reviewers {
+            load := loads[r.userID]
+            if pr.status == "OPEN" {
+                load.OpenReviews++
+            }
+            if r.assignedAt.After(load.LastAssignedAt) {
+                load.LastAssignedAt = r.assignedAt
+            }
+            loads[r.userID] = load
+        }
+    }
+    return loads
+}
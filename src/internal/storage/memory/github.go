@@ -0,0 +1,48 @@
+package memory
+
+import (
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/storage"
+)
+
+func (s *Store) SetGitHubLogin(userID, login string) (*models.GitHubLogin, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if _, exists := s.users[userID]; !exists {
+        return nil, database.ErrNotFound
+    }
+
+    for existingLogin, existingUserID := range s.githubLogins {
+        if existingUserID == userID {
+            delete(s.githubLogins, existingLogin)
+        }
+    }
+    s.githubLogins[login] = userID
+
+    return &models.GitHubLogin{UserID: userID, GitHubLogin: login}, nil
+}
+
+func (s *Store) RemoveGitHubLogin(login string) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if _, exists := s.githubLogins[login]; !exists {
+        return database.ErrNotFound
+    }
+    delete(s.githubLogins, login)
+
+    return nil
+}
+
+func (s *Store) GetUserIDByGitHubLogin(login string) (string, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    userID, exists := s.githubLogins[login]
+    if !exists {
+        return "", database.ErrNotFound
+    }
+
+    return userID, nil
+}
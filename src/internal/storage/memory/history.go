@@ -0,0 +1,76 @@
+package memory
+
+import (
+    "time"
+
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/storage"
+)
+
+func (s *Store) GetPullRequestHistory(prID string) (*models.PullRequestHistory, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if _, exists := s.prs[prID]; !exists {
+        return nil, database.ErrNotFound
+    }
+
+    entries := []models.ReviewerHistoryEntry{}
+    for _, e := range s.history {
+        if e.PullRequestID == prID {
+            entries = append(entries, e)
+        }
+    }
+
+    return database.NewPullRequestHistory(prID, entries), nil
+}
+
+// assignReviewers назначает ревьюверов и пишет ASSIGNED в историю, как одноимённая функция DB;
+// флаги fallback и shadow берутся из kind
+func (s *Store) assignReviewers(pr *pullRequest, reviewerIDs []string, kind reviewer, reason, actor string) {
+    now := time.Now()
+    for _, reviewerID := range reviewerIDs {
+        pr.reviewers = append(pr.reviewers, reviewer{userID: reviewerID, assignedAt: now, fallback: kind.fallback, shadow: kind.shadow})
+        s.addHistory(models.ReviewerHistoryEntry{
+            PullRequestID: pr.id,
+            ReviewerID:    reviewerID,
+            Action:        models.HistoryAssigned,
+            Reason:        reason,
+            Actor:         actor,
+        })
+    }
+}
+
+// unassignReviewer снимает ревьювера и пишет REPLACED, если задана замена, иначе REMOVED; замена не назначается
+func (s *Store) unassignReviewer(pr *pullRequest, reviewerID, replacedBy, reason, actor string) {
+    kept := pr.reviewers[:0]
+    for _, r := range pr.reviewers {
+        if r.userID != reviewerID {
+            kept = append(kept, r)
+            continue
+        }
+
+        action := models.HistoryRemoved
+        if replacedBy != "" {
+            action = models.HistoryReplaced
+        }
+        assignedAt := r.assignedAt
+        s.addHistory(models.ReviewerHistoryEntry{
+            PullRequestID: pr.id,
+            ReviewerID:    reviewerID,
+            Action:        action,
+            ReplacedBy:    replacedBy,
+            Reason:        reason,
+            Actor:         actor,
+            AssignedAt:    &assignedAt,
+        })
+    }
+    pr.reviewers = kept
+}
+
+func (s *Store) addHistory(entry models.ReviewerHistoryEntry) {
+    s.lastHistoryID++
+    entry.HistoryID = s.lastHistoryID
+    entry.CreatedAt = time.Now()
+    s.history = append(s.history, entry)
+}
@@ -0,0 +1,186 @@
+package memory
+
+import (
+    "slices"
+    "sort"
+    "time"
+
+    "pr-reviewer/src/internal/domain/assignment"
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/storage"
+)
+
+func (s *Store) AddTeamMembers(teamName string, members []models.TeamMember, allowMove bool, actor string) (*models.TeamMembershipResponse, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if _, exists := s.teams[teamName]; !exists {
+        return nil, database.ErrNotFound
+    }
+
+    current := s.currentTeams(members)
+    conflicts := database.MemberConflicts(teamName, current)
+    if len(conflicts) > 0 && !allowMove {
+        return nil, &database.MemberConflictError{Conflicts: conflicts}
+    }
+
+    report := database.NewTeamMembershipResponse(teamName)
+    for _, member := range members {
+        s.upsertMember(teamName, member)
+        if current[member.UserID] == teamName {
+            report.Updated = append(report.Updated, member.UserID)
+        } else if current[member.UserID] == "" {
+            report.Added = append(report.Added, member.UserID)
+        }
+    }
+    report.Moved = conflicts
+
+    moved := make(map[string]bool, len(conflicts))
+    for _, conflict := range conflicts {
+        moved[conflict.UserID] = true
+    }
+    s.detachReviews(report, moved, models.ReasonUserMoved, actor)
+
+    return report, nil
+}
+
+func (s *Store) RemoveTeamMembers(teamName string, userIDs []string, actor string) (*models.TeamMembershipResponse, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if _, exists := s.teams[teamName]; !exists {
+        return nil, database.ErrNotFound
+    }
+    for _, userID := range userIDs {
+        if u, exists := s.users[userID]; !exists || u.TeamName != teamName {
+            return nil, database.ErrNotFound
+        }
+    }
+
+    return s.detachMembers(teamName, userIDs, actor), nil
+}
+
+func (s *Store) RenameTeam(teamName, newTeamName string) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if _, exists := s.teams[newTeamName]; exists {
+        return database.ErrTeamExists
+    }
+    t, exists := s.teams[teamName]
+    if !exists {
+        return database.ErrNotFound
+    }
+
+    for _, u := range s.teamMembers(teamName) {
+        u.TeamName = newTeamName
+    }
+    t.settings.TeamName = newTeamName
+    delete(s.teams, teamName)
+    s.teams[newTeamName] = t
+    for _, repo := range s.repositories {
+        if repo.TeamName == teamName {
+            repo.TeamName = newTeamName
+        }
+    }
+    for _, other := range s.teams {
+        for i, fallback := range other.settings.FallbackTeams {
+            if fallback == teamName {
+                other.settings.FallbackTeams[i] = newTeamName
+            }
+        }
+    }
+
+    return nil
+}
+
+func (s *Store) DeleteTeam(teamName string, removeMembers bool, actor string) (*models.TeamMembershipResponse, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if _, exists := s.teams[teamName]; !exists {
+        return nil, database.ErrNotFound
+    }
+
+    var userIDs []string
+    for _, u := range s.teamMembers(teamName) {
+        userIDs = append(userIDs, u.UserID)
+    }
+    if len(userIDs) > 0 && !removeMembers {
+        return nil, database.ErrTeamNotEmpty
+    }
+
+    report := s.detachMembers(teamName, userIDs, actor)
+    delete(s.teams, teamName)
+    for name, repo := range s.repositories {
+        if repo.TeamName == teamName {
+            delete(s.repositories, name)
+        }
+    }
+    for _, other := range s.teams {
+        other.settings.FallbackTeams = slices.DeleteFunc(other.settings.FallbackTeams, func(fallback string) bool {
+            return fallback == teamName
+        })
+    }
+
+    return report, nil
+}
+
+// detachMembers оставляет участников без команды, как одноимённая функция DB; участие в команде уже проверено
+func (s *Store) detachMembers(teamName string, userIDs []string, actor string) *models.TeamMembershipResponse {
+    report := database.NewTeamMembershipResponse(teamName)
+    leaving := make(map[string]bool, len(userIDs))
+    for _, userID := range userIDs {
+        if !leaving[userID] {
+            s.users[userID].TeamName = ""
+            report.Removed = append(report.Removed, userID)
+        }
+        leaving[userID] = true
+    }
+    sort.Strings(report.Removed)
+
+    s.detachReviews(report, leaving, models.ReasonUserRemoved, actor)
+    return report
+}
+
+func (s *Store) detachReviews(report *models.TeamMembershipResponse, leaving map[string]bool, reason, actor string) {
+    if len(leaving) == 0 {
+        return
+    }
+
+    report.PullRequests = s.reassignOpenReviews(leaving, reason, actor)
+    database.CountReassignments(report)
+
+    for _, pr := range s.sortedPullRequests() {
+        if pr.status == "OPEN" && leaving[pr.authorID] {
+            report.AuthoredOpenPRs = append(report.AuthoredOpenPRs, pr.id)
+        }
+    }
+}
+
+// currentTeams возвращает команды уже существующих пользователей, пустая строка - пользователь без команды
+func (s *Store) currentTeams(members []models.TeamMember) map[string]string {
+    current := make(map[string]string)
+    for _, member := range members {
+        if u, exists := s.users[member.UserID]; exists {
+            current[member.UserID] = u.TeamName
+        }
+    }
+    return current
+}
+
+func (s *Store) upsertMember(teamName string, member models.TeamMember) {
+    u, exists := s.users[member.UserID]
+    if !exists {
+        u = &user{createdAt: time.Now()}
+        u.Level = assignment.DefaultLevel
+        s.users[member.UserID] = u
+    }
+    u.UserID = member.UserID
+    u.Username = member.Username
+    u.TeamName = teamName
+    u.IsActive = member.IsActive
+    if member.Level != "" {
+        u.Level = member.Level
+    }
+}
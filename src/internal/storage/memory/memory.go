@@ -0,0 +1,339 @@
+package memory
+
+import (
+    "sort"
+    "sync"
+    "time"
+
+    "pr-reviewer/src/internal/domain/assignment"
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/storage"
+)
+
+type team struct {
+    settings  models.TeamSettings
+    leads     map[string]bool
+    createdAt time.Time
+}
+
+type user struct {
+    models.User
+    skills    []string
+    createdAt time.Time
+}
+
+type reviewer struct {
+    userID      string
+    assignedAt  time.Time
+    decision    string
+    decidedAt   time.Time
+    escalatedAt time.Time
+    fallback    bool
+    shadow      bool
+}
+
+type pullRequest struct {
+    id        string
+    name      string
+    authorID  string
+    repo      string
+    labels    []string
+    status    string
+    createdAt time.Time
+    mergedAt  time.Time
+    closedAt  time.Time
+    forced    bool
+    mergedBy  string
+    reviewers []reviewer
+}
+
+// Store хранит данные сервиса в памяти процесса с той же семантикой, что и database.DB
+type Store struct {
+    mu           sync.Mutex
+    teams        map[string]*team
+    users        map[string]*user
+    prs          map[string]*pullRequest
+    githubLogins map[string]string
+    webhooks     map[string]*models.WebhookSubscription
+    deliveries   []*models.WebhookDelivery
+    outbox       []*outboxEntry
+    availability map[int64]*models.AvailabilityPeriod
+    history      []models.ReviewerHistoryEntry
+    tokens       map[string]*apiToken
+    codeOwners   map[string]*models.CodeOwnersFile
+    repositories map[string]*models.Repository
+
+    lastDeliveryID     int64
+    lastOutboxID       int64
+    lastAvailabilityID int64
+    lastHistoryID      int64
+}
+
+var _ database.Repository = (*Store)(nil)
+
+func New() *Store {
+    return &Store{
+        teams:        make(map[string]*team),
+        users:        make(map[string]*user),
+        prs:          make(map[string]*pullRequest),
+        githubLogins: make(map[string]string),
+        webhooks:     make(map[string]*models.WebhookSubscription),
+        availability: make(map[int64]*models.AvailabilityPeriod),
+        tokens:       make(map[string]*apiToken),
+        codeOwners:   make(map[string]*models.CodeOwnersFile),
+        repositories: make(map[string]*models.Repository),
+    }
+}
+
+func (s *Store) Close() error {
+    return nil
+}
+
+func (s *Store) CreateTeam(t models.Team) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if _, exists := s.teams[t.TeamName]; exists {
+        return database.ErrTeamExists
+    }
+
+    strategy := t.AssignmentStrategy
+    if strategy == "" {
+        strategy = assignment.Default
+    }
+
+    if conflicts := database.MemberConflicts(t.TeamName, s.currentTeams(t.Members)); len(conflicts) > 0 {
+        return &database.MemberConflictError{Conflicts: conflicts}
+    }
+
+    now := time.Now()
+    s.teams[t.TeamName] = &team{
+        settings: models.TeamSettings{
+            TeamName:           t.TeamName,
+            ReviewersCount:     2,
+            MinReviewersCount:  0,
+            AssignmentStrategy: strategy,
+            FallbackTeams:      []string{},
+        },
+        leads:     make(map[string]bool),
+        createdAt: now,
+    }
+
+    for _, member := range t.Members {
+        s.upsertMember(t.TeamName, member)
+    }
+
+    return nil
+}
+
+func (s *Store) GetTeam(teamName string) (*models.Team, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    t, exists := s.teams[teamName]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+
+    result := models.Team{
+        TeamName:           teamName,
+        AssignmentStrategy: t.settings.AssignmentStrategy,
+    }
+    for _, u := range s.teamMembers(teamName) {
+        result.Members = append(result.Members, models.TeamMember{
+            UserID:   u.UserID,
+            Username: u.Username,
+            IsActive: u.IsActive,
+            Level:    u.Level,
+        })
+    }
+
+    if len(result.Members) == 0 {
+        return nil, database.ErrNotFound
+    }
+
+    return &result, nil
+}
+
+func (s *Store) GetTeamSettings(teamName string) (*models.TeamSettings, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    t, exists := s.teams[teamName]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+
+    return copySettings(t.settings), nil
+}
+
+func (s *Store) UpdateTeamSettings(settings models.TeamSettings) (*models.TeamSettings, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    t, exists := s.teams[settings.TeamName]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+    for _, fallback := range settings.FallbackTeams {
+        if _, exists := s.teams[fallback]; !exists {
+            return nil, database.ErrNotFound
+        }
+    }
+
+    t.settings = *copySettings(settings)
+    return copySettings(t.settings), nil
+}
+
+func (s *Store) SetUserActive(userID string, isActive bool) (*models.User, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    u, exists := s.users[userID]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+
+    u.IsActive = isActive
+    return copyUser(u), nil
+}
+
+func (s *Store) SetUserMaxOpenReviews(userID string, maxOpenReviews *int) (*models.User, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    u, exists := s.users[userID]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+
+    u.MaxOpenReviews = nil
+    if maxOpenReviews != nil {
+        limit := *maxOpenReviews
+        u.MaxOpenReviews = &limit
+    }
+    return copyUser(u), nil
+}
+
+func (s *Store) SetUserLevel(userID, level string) (*models.User, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    u, exists := s.users[userID]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+
+    u.Level = level
+    return copyUser(u), nil
+}
+
+func (s *Store) GetUserPullRequests(userID string) (*models.UserPRsResponse, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if _, exists := s.users[userID]; !exists {
+        return nil, database.ErrNotFound
+    }
+
+    response := models.UserPRsResponse{UserID: userID}
+    for _, pr := range s.sortedPullRequests() {
+        if pr.status != "CLOSED" && pr.hasReviewer(userID) {
+            response.PullRequests = append(response.PullRequests, models.PullRequestShort{
+                PullRequestID:   pr.id,
+                PullRequestName: pr.name,
+                AuthorID:        pr.authorID,
+                Status:          pr.status,
+            })
+        }
+    }
+
+    return &response, nil
+}
+
+func (s *Store) teamMembers(teamName string) []*user {
+    // пользователи без команды не образуют команду ""
+    if teamName == "" {
+        return nil
+    }
+
+    var members []*user
+    for _, u := range s.users {
+        if u.TeamName == teamName {
+            members = append(members, u)
+        }
+    }
+    sort.Slice(members, func(i, j int) bool {
+        return members[i].UserID < members[j].UserID
+    })
+    return members
+}
+
+func (s *Store) sortedPullRequests() []*pullRequest {
+    prs := make([]*pullRequest, 0, len(s.prs))
+    for _, pr := range s.prs {
+        prs = append(prs, pr)
+    }
+    sort.Slice(prs, func(i, j int) bool {
+        if !prs[i].createdAt.Equal(prs[j].createdAt) {
+            return prs[i].createdAt.Before(prs[j].createdAt)
+        }
+        return prs[i].id < prs[j].id
+    })
+    return prs
+}
+
+func (pr *pullRequest) hasReviewer(userID string) bool {
+    return pr.reviewer(userID) != nil
+}
+
+func (pr *pullRequest) reviewer(userID string) *reviewer {
+    for i := range pr.reviewers {
+        if pr.reviewers[i].userID == userID {
+            return &pr.reviewers[i]
+        }
+    }
+    return nil
+}
+
+func (pr *pullRequest) toModel() *models.PullRequest {
+    result := &models.PullRequest{
+        PullRequestID:   pr.id,
+        PullRequestName: pr.name,
+        AuthorID:        pr.authorID,
+        Status:          pr.status,
+        CreatedAt:       pr.createdAt,
+        MergedAt:        pr.mergedAt,
+        ClosedAt:        pr.closedAt,
+        ForceMerged:     pr.forced,
+        MergedBy:        pr.mergedBy,
+        Repository:      pr.repo,
+        Labels:          append([]string(nil), pr.labels...),
+    }
+    for _, r := range pr.reviewers {
+        state := models.ReviewerState{UserID: r.userID, State: models.ReviewPending, Fallback: r.fallback, Shadow: r.shadow}
+        if r.decision != "" {
+            decidedAt := r.decidedAt
+            state.State = r.decision
+            state.ReviewedAt = &decidedAt
+        }
+        result.AssignedReviewers = append(result.AssignedReviewers, r.userID)
+        result.Reviewers = append(result.Reviewers, state)
+    }
+    return result
+}
+
+func copySettings(settings models.TeamSettings) *models.TeamSettings {
+    result := settings
+    result.FallbackTeams = append([]string{}, settings.FallbackTeams...)
+    return &result
+}
+
+func copyUser(u *user) *models.User {
+    result := u.User
+    if u.MaxOpenReviews != nil {
+        limit := *u.MaxOpenReviews
+        result.MaxOpenReviews = &limit
+    }
+    return &result
+}
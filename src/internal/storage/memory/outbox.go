@@ -0,0 +1,97 @@
+package memory
+
+import (
+    "time"
+
+    "pr-reviewer/src/internal/domain/events"
+    "pr-reviewer/src/internal/storage"
+)
+
+type outboxEntry struct {
+    id            int64
+    event         events.Event
+    attempts      int
+    lastError     string
+    nextAttemptAt time.Time
+    dispatchedAt  time.Time
+}
+
+// addEvents вызывается под s.mu вместе с изменением данных, как insertEvents в транзакции DB
+func (s *Store) addEvents(evts ...events.Event) {
+    now := time.Now()
+    for _, event := range evts {
+        s.lastOutboxID++
+        s.outbox = append(s.outbox, &outboxEntry{id: s.lastOutboxID, event: event, nextAttemptAt: now})
+    }
+}
+
+func (s *Store) ClaimOutboxEvents(lease time.Duration, limit int) ([]database.OutboxEntry, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    now := time.Now()
+    entries := []database.OutboxEntry{}
+    for _, e := range s.outbox {
+        if len(entries) >= limit {
+            break
+        }
+        if !e.dispatchedAt.IsZero() || e.nextAttemptAt.After(now) {
+            continue
+        }
+
+        e.nextAttemptAt = now.Add(lease)
+        entries = append(entries, database.OutboxEntry{ID: e.id, Event: e.event, Attempts: e.attempts})
+    }
+
+    return entries, nil
+}
+
+func (s *Store) MarkOutboxDispatched(id int64) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if e := s.outboxEntry(id); e != nil {
+        e.attempts++
+        e.lastError = ""
+        e.dispatchedAt = time.Now()
+    }
+    return nil
+}
+
+func (s *Store) MarkOutboxFailed(id int64, errMsg string, retryIn time.Duration) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if e := s.outboxEntry(id); e != nil {
+        e.attempts++
+        e.lastError = errMsg
+        e.nextAttemptAt = time.Now().Add(retryIn)
+    }
+    return nil
+}
+
+func (s *Store) PurgeOutbox(olderThan time.Duration) (int, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    threshold := time.Now().Add(-olderThan)
+    kept := s.outbox[:0]
+    for _, e := range s.outbox {
+        if e.dispatchedAt.IsZero() || !e.dispatchedAt.Before(threshold) {
+            kept = append(kept, e)
+        }
+    }
+    purged := len(s.outbox) - len(kept)
+    s.outbox = kept
+
+    return purged, nil
+}
+
+func (s *Store) outboxEntry(id int64) *outboxEntry {
+    for _, e := range s.outbox {
+        if e.id == id {
+            return e
+        }
+    }
+    return nil
+}
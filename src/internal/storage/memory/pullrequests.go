@@ -0,0 +1,470 @@
+package memory
+
+import (
+    "slices"
+    "time"
+
+    "pr-reviewer/src/internal/domain/assignment"
+    "pr-reviewer/src/internal/domain/events"
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/storage"
+)
+
+func (s *Store) CreatePullRequest(req models.CreatePRRequest, actor string) (*models.PullRequest, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if _, exists := s.prs[req.PullRequestID]; exists {
+        return nil, database.ErrPRExists
+    }
+
+    if _, exists := s.users[req.AuthorID]; !exists {
+        return nil, database.ErrNotFound
+    }
+    teamName, repo := s.reviewTeam(req.AuthorID, req.Repository)
+    if teamName == "" {
+        return nil, database.ErrNotFound
+    }
+
+    pr := &pullRequest{
+        id:        req.PullRequestID,
+        name:      req.PullRequestName,
+        authorID:  req.AuthorID,
+        repo:      req.Repository,
+        labels:    append([]string(nil), req.Labels...),
+        status:    "OPEN",
+        createdAt: time.Now(),
+    }
+
+    settings := s.reviewSettings(teamName, repo)
+    candidates, excluded := s.reviewCandidates(teamName, pr)
+    owners := s.codeOwnerIDs(teamName, req.CodeOwners)
+    strategy := teamStrategy(settings)
+    isOwner := func(c assignment.Candidate) bool {
+        return owners[c.UserID]
+    }
+    reviewers := assignment.PickPreferring(strategy, candidates, settings.ReviewersCount, isOwner)
+    skilled := s.skilledMembers(teamName, req.Labels)
+    isSkilled := func(c assignment.Candidate) bool {
+        return skilled[c.UserID]
+    }
+    reviewers = assignment.EnsureCovered(strategy, candidates, reviewers, isSkilled, isOwner)
+    fallbackPools := s.fallbackCandidates(settings.FallbackTeams, pr, s.reviewerLoads())
+    fallback := database.PickFallback(fallbackPools, settings.ReviewersCount-len(reviewers), nil, nil)
+    team := database.TeamCandidates{Strategy: strategy, Candidates: candidates}
+    reviewers, fallback, err := database.EnsureLevel(team, fallbackPools, reviewers, fallback, settings.ReviewersCount,
+        settings.MinReviewerLevel, func(c assignment.Candidate) bool {
+            return isOwner(c) || isSkilled(c)
+        }, excluded)
+    if err != nil {
+        return nil, err
+    }
+    if found := len(reviewers) + len(fallback); found < settings.MinReviewersCount {
+        return nil, &database.NoCandidateError{Required: settings.MinReviewersCount, Found: found, Excluded: excluded}
+    }
+    shadows := database.PickShadowReviewers(strategy, candidates, reviewers, settings.ShadowReviewers)
+
+    s.assignReviewers(pr, reviewers, reviewer{}, models.ReasonPRCreated, actor)
+    s.assignReviewers(pr, fallback, reviewer{fallback: true}, models.ReasonPRCreated, actor)
+    s.assignReviewers(pr, shadows, reviewer{shadow: true}, models.ReasonPRCreated, actor)
+    s.prs[pr.id] = pr
+
+    result := pr.toModel()
+    s.addEvents(events.PullRequestCreated(result)...)
+
+    return result, nil
+}
+
+func (s *Store) MergePullRequest(prID string, force bool, actor string) (*models.PullRequest, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    pr, exists := s.prs[prID]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+
+    if pr.status == "MERGED" {
+        return pr.toModel(), nil
+    }
+    if pr.status == "CLOSED" {
+        return nil, database.ErrPRClosed
+    }
+
+    if !force {
+        settings := s.reviewSettings(s.reviewTeam(pr.authorID, pr.repo))
+        if err := database.CheckMergePolicy(settings.RequiredApprovals, pr.toModel().Reviewers); err != nil {
+            return nil, err
+        }
+    }
+
+    pr.status = "MERGED"
+    pr.mergedAt = time.Now()
+    pr.forced = force
+    if force {
+        pr.mergedBy = actor
+    }
+
+    result := pr.toModel()
+    s.addEvents(events.PullRequestMergedEvent(result, actor))
+
+    return result, nil
+}
+
+func (s *Store) MergeExternally(prID, actor string) (*models.PullRequest, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    pr, exists := s.prs[prID]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+
+    if pr.status == "MERGED" {
+        return pr.toModel(), nil
+    }
+
+    pr.status = "MERGED"
+    pr.mergedAt = time.Now()
+    pr.closedAt = time.Time{}
+    pr.mergedBy = actor
+
+    result := pr.toModel()
+    s.addEvents(events.PullRequestMergedEvent(result, actor))
+
+    return result, nil
+}
+
+func (s *Store) ClosePullRequest(prID string) (*models.PullRequest, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    pr, exists := s.prs[prID]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+
+    if pr.status == "MERGED" {
+        return nil, database.ErrPRMerged
+    }
+    if pr.status == "CLOSED" {
+        return pr.toModel(), nil
+    }
+
+    pr.status = "CLOSED"
+    pr.closedAt = time.Now()
+
+    result := pr.toModel()
+    s.addEvents(events.PullRequestEvent(events.PRClosed, result))
+
+    return result, nil
+}
+
+func (s *Store) ReopenPullRequest(prID, actor string) (*models.PullRequest, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    pr, exists := s.prs[prID]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+
+    if pr.status == "MERGED" {
+        return nil, database.ErrPRMerged
+    }
+    if pr.status == "OPEN" {
+        return pr.toModel(), nil
+    }
+
+    // замены подбираются на черновике без снятых ревьюверов, чтобы при ошибке PR остался как был
+    teamName, _ := s.reviewTeam(pr.authorID, pr.repo)
+    fallbackTeams := s.teamSettings(teamName).FallbackTeams
+    draft := *pr
+    draft.status = "OPEN"
+    draft.reviewers = nil
+    var gone, removed []string
+    for _, r := range pr.reviewers {
+        u := s.users[r.userID]
+        keep := u.TeamName == teamName || r.fallback && slices.Contains(fallbackTeams, u.TeamName)
+        if u.IsActive && keep {
+            draft.reviewers = append(draft.reviewers, r)
+            continue
+        }
+        gone = append(gone, r.userID)
+        if !r.shadow {
+            removed = append(removed, r.userID)
+        }
+    }
+
+    var added []reviewer
+    if len(removed) > 0 && teamName != "" {
+        var err error
+        if added, err = s.refillReviewers(&draft, teamName, removed); err != nil {
+            return nil, err
+        }
+    }
+
+    pr.status = "OPEN"
+    pr.closedAt = time.Time{}
+    for _, reviewerID := range gone {
+        s.unassignReviewer(pr, reviewerID, "", models.ReasonPRReopened, actor)
+    }
+    var picked []string
+    for _, r := range added {
+        s.assignReviewers(pr, []string{r.userID}, r, models.ReasonPRReopened, actor)
+        picked = append(picked, r.userID)
+    }
+
+    result := pr.toModel()
+    s.addEvents(events.PullRequestEvent(events.PRReopened, result))
+    for _, reviewerID := range picked {
+        s.addEvents(events.ReviewerAssignedEvent(result, reviewerID))
+    }
+
+    return result, nil
+}
+
+// refillReviewers повторяет одноимённую функцию DB на черновике pr, с которого ревьюверы уже сняты;
+// подобранные замены добавляются в черновик и возвращаются
+func (s *Store) refillReviewers(pr *pullRequest, teamName string, removed []string) ([]reviewer, error) {
+    settings := s.teamSettings(teamName)
+    var added []reviewer
+    var excluded []models.CandidateExclusion
+    for _, oldUserID := range removed {
+        replacement, exclusions, ok := s.pickReplacement(pr, oldUserID, s.reviewerLoads(), nil)
+        excluded = exclusions
+        if !ok {
+            continue
+        }
+        pr.reviewers = append(pr.reviewers, replacement)
+        added = append(added, replacement)
+    }
+
+    found := 0
+    levelCovered := settings.MinReviewerLevel == ""
+    for _, r := range pr.reviewers {
+        if r.shadow {
+            continue
+        }
+        found++
+        if u, ok := s.users[r.userID]; ok && assignment.AtLeast(u.Level, settings.MinReviewerLevel) {
+            levelCovered = true
+        }
+    }
+    if found < settings.MinReviewersCount {
+        return nil, &database.NoCandidateError{Required: settings.MinReviewersCount, Found: found, Excluded: excluded}
+    }
+    if !levelCovered {
+        return nil, &database.NoCandidateError{Required: 1, Found: found, MinLevel: settings.MinReviewerLevel, Excluded: excluded}
+    }
+    return added, nil
+}
+
+func (s *Store) ReassignReviewer(prID, oldUserID, actor string) (*models.PullRequest, string, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    pr, exists := s.prs[prID]
+    if !exists {
+        return nil, "", database.ErrNotFound
+    }
+
+    if pr.status == "MERGED" {
+        return nil, "", database.ErrPRMerged
+    }
+    if pr.status == "CLOSED" {
+        return nil, "", database.ErrPRClosed
+    }
+
+    if !pr.hasReviewer(oldUserID) {
+        return nil, "", database.ErrNotAssigned
+    }
+
+    newUserID, err := s.replaceReviewer(pr, oldUserID, models.ReasonManualReassign, actor)
+    if err != nil {
+        return nil, "", err
+    }
+
+    result := pr.toModel()
+    s.addEvents(events.ReviewerReplacedEvent(result, oldUserID, newUserID))
+
+    return result, newUserID, nil
+}
+
+// replaceReviewer повторяет одноимённую функцию DB: замена по правилам PickReplacement, SLA отсчитывается заново
+func (s *Store) replaceReviewer(pr *pullRequest, oldUserID, reason, actor string) (string, error) {
+    replacement, excluded, ok := s.pickReplacement(pr, oldUserID, s.reviewerLoads(), nil)
+    if !ok {
+        return "", &database.NoCandidateError{Required: 1, Excluded: excluded}
+    }
+
+    s.unassignReviewer(pr, oldUserID, replacement.userID, reason, actor)
+    pr.reviewers = append(pr.reviewers, replacement)
+    return replacement.userID, nil
+}
+
+// pickReplacement подбирает замену oldUserID на pr с нагрузкой из loads, но не назначает её; oldUserID может быть
+// уже снят с PR, leaving не подбираются, даже если уже сняты
+func (s *Store) pickReplacement(pr *pullRequest, oldUserID string, loads map[string]assignment.Candidate,
+    leaving map[string]bool) (reviewer, []models.CandidateExclusion, bool) {
+    teamName, _ := s.reviewTeam(pr.authorID, pr.repo)
+    settings := s.teamSettings(teamName)
+    candidates, excluded := database.SplitCandidates(s.memberStates(teamName, pr, loads))
+    skilled := s.replacementSkills(teamName, pr, oldUserID)
+
+    shadow := false
+    if old := pr.reviewer(oldUserID); old != nil {
+        shadow = old.shadow
+    }
+    levelCovered := settings.MinReviewerLevel == ""
+    for _, r := range pr.reviewers {
+        if u, ok := s.users[r.userID]; ok && r.userID != oldUserID && !r.shadow && assignment.AtLeast(u.Level, settings.MinReviewerLevel) {
+            levelCovered = true
+        }
+    }
+
+    team := database.TeamCandidates{Strategy: teamStrategy(settings), Candidates: withoutUsers(candidates, leaving)}
+    fallbacks := s.fallbackCandidates(settings.FallbackTeams, pr, loads)
+    for i := range fallbacks {
+        fallbacks[i].Candidates = withoutUsers(fallbacks[i].Candidates, leaving)
+    }
+    newUserID, fallback, ok := database.PickReplacement(team, fallbacks, shadow, levelCovered, settings.MinReviewerLevel, skilled)
+    if !ok {
+        return reviewer{}, excluded, false
+    }
+    return reviewer{userID: newUserID, assignedAt: time.Now(), fallback: fallback, shadow: shadow}, excluded, true
+}
+
+func withoutUsers(candidates []assignment.Candidate, userIDs map[string]bool) []assignment.Candidate {
+    return slices.DeleteFunc(candidates, func(c assignment.Candidate) bool {
+        return userIDs[c.UserID]
+    })
+}
+
+// fallbackCandidates повторяет одноимённую функцию DB: кандидаты запасных команд с нагрузкой из loads
+func (s *Store) fallbackCandidates(teams []string, pr *pullRequest, loads map[string]assignment.Candidate) []database.TeamCandidates {
+    result := make([]database.TeamCandidates, 0, len(teams))
+    for _, team := range teams {
+        candidates, _ := database.SplitCandidates(s.memberStates(team, pr, loads))
+        result = append(result, database.TeamCandidates{Strategy: teamStrategy(s.teamSettings(team)), Candidates: candidates})
+    }
+    return result
+}
+
+func (s *Store) SubmitReview(prID, reviewerID, decision string) (*models.PullRequest, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    pr, exists := s.prs[prID]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+
+    if pr.status == "MERGED" {
+        return nil, database.ErrPRMerged
+    }
+    if pr.status == "CLOSED" {
+        return nil, database.ErrPRClosed
+    }
+
+    r := pr.reviewer(reviewerID)
+    if r == nil {
+        return nil, database.ErrNotAssigned
+    }
+    r.decision = decision
+    r.decidedAt = time.Now()
+
+    return pr.toModel(), nil
+}
+
+func (s *Store) GetPullRequest(prID string) (*models.PullRequest, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    pr, exists := s.prs[prID]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+
+    return pr.toModel(), nil
+}
+
+func (s *Store) ListPullRequests(filter models.PullRequestFilter) (*models.PullRequestPage, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    sorted := s.sortedPullRequests()
+    prs := []*models.PullRequest{}
+    for i := len(sorted) - 1; i >= 0 && len(prs) <= filter.Limit; i-- {
+        if pr := sorted[i]; s.matchesFilter(pr, filter) {
+            prs = append(prs, pr.toModel())
+        }
+    }
+
+    return database.NewPullRequestPage(prs, filter.Limit), nil
+}
+
+func (s *Store) matchesFilter(pr *pullRequest, filter models.PullRequestFilter) bool {
+    switch {
+    case filter.Status != "" && pr.status != filter.Status,
+        filter.AuthorID != "" && pr.authorID != filter.AuthorID,
+        filter.ReviewerID != "" && !pr.hasReviewer(filter.ReviewerID),
+        filter.TeamName != "" && s.users[pr.authorID].TeamName != filter.TeamName,
+        !inRange(pr.createdAt, filter.CreatedFrom, filter.CreatedTo),
+        (filter.MergedFrom != nil || filter.MergedTo != nil) && pr.mergedAt.IsZero(),
+        !inRange(pr.mergedAt, filter.MergedFrom, filter.MergedTo):
+        return false
+    }
+
+    if after := filter.After; after != nil {
+        if pr.createdAt.Equal(after.CreatedAt) {
+            return pr.id < after.PullRequestID
+        }
+        return pr.createdAt.Before(after.CreatedAt)
+    }
+    return true
+}
+
+// inRange проверяет попадание t в полуоткрытый диапазон [from, to), nil - без границы
+func inRange(t time.Time, from, to *time.Time) bool {
+    return (from == nil || !t.Before(*from)) && (to == nil || t.Before(*to))
+}
+
+func (s *Store) teamSettings(teamName string) models.TeamSettings {
+    if t, exists := s.teams[teamName]; exists {
+        return t.settings
+    }
+    return models.TeamSettings{TeamName: teamName, ReviewersCount: 2, AssignmentStrategy: assignment.Default, FallbackTeams: []string{}}
+}
+
+func teamStrategy(settings models.TeamSettings) assignment.Strategy {
+    strategy, err := assignment.Get(settings.AssignmentStrategy)
+    if err != nil {
+        strategy, _ = assignment.Get(assignment.Default)
+    }
+    return strategy
+}
+
+func (s *Store) reviewCandidates(teamName string, pr *pullRequest) ([]assignment.Candidate, []models.CandidateExclusion) {
+    return database.SplitCandidates(s.memberStates(teamName, pr, s.reviewerLoads()))
+}
+
+// memberStates повторяет выборку reviewCandidates в DB: участники команды с нагрузкой из loads
+func (s *Store) memberStates(teamName string, pr *pullRequest, loads map[string]assignment.Candidate) []database.MemberState {
+    now := time.Now()
+    teamLimit := s.teamSettings(teamName).MaxOpenReviews
+    var members []database.MemberState
+    for _, u := range s.teamMembers(teamName) {
+        candidate := loads[u.UserID]
+        candidate.Level = u.Level
+        members = append(members, database.MemberState{
+            Candidate:      candidate,
+            IsActive:       u.IsActive,
+            IsAuthor:       u.UserID == pr.authorID,
+            Assigned:       pr.hasReviewer(u.UserID),
+            Away:           s.isAway(u.UserID, now),
+            MaxOpenReviews: database.EffectiveMaxOpenReviews(u.MaxOpenReviews, teamLimit),
+        })
+    }
+    return members
+}
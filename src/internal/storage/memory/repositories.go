@@ -0,0 +1,90 @@
+package memory
+
+import (
+    "sort"
+    "time"
+
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/storage"
+)
+
+func (s *Store) SetRepository(repo models.Repository) (*models.Repository, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if _, exists := s.teams[repo.TeamName]; !exists {
+        return nil, database.ErrNotFound
+    }
+
+    stored := copyRepository(&repo)
+    stored.UpdatedAt = time.Now()
+    s.repositories[repo.Repository] = stored
+    return copyRepository(stored), nil
+}
+
+func (s *Store) GetRepository(repository string) (*models.Repository, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    repo, exists := s.repositories[repository]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+    return copyRepository(repo), nil
+}
+
+func (s *Store) ListRepositories(teamName string) ([]models.Repository, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    repos := []models.Repository{}
+    for _, repo := range s.repositories {
+        if teamName == "" || repo.TeamName == teamName {
+            repos = append(repos, *copyRepository(repo))
+        }
+    }
+    sort.Slice(repos, func(i, j int) bool {
+        return repos[i].Repository < repos[j].Repository
+    })
+    return repos, nil
+}
+
+func (s *Store) DeleteRepository(repository string) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if _, exists := s.repositories[repository]; !exists {
+        return database.ErrNotFound
+    }
+    delete(s.repositories, repository)
+    return nil
+}
+
+// reviewTeam - команда, которая ревьюит pr, как одноимённая функция DB: владелец заведённого репозитория или команда автора
+func (s *Store) reviewTeam(authorID, repository string) (string, *models.Repository) {
+    if repo, exists := s.repositories[repository]; exists {
+        return repo.TeamName, repo
+    }
+    if author, exists := s.users[authorID]; exists {
+        return author.TeamName, nil
+    }
+    return "", nil
+}
+
+// reviewSettings - настройки ревьюящей команды с учётом настроек репозитория
+func (s *Store) reviewSettings(teamName string, repo *models.Repository) models.TeamSettings {
+    return database.RepositorySettings(s.teamSettings(teamName), repo)
+}
+
+func copyRepository(repo *models.Repository) *models.Repository {
+    result := *repo
+    if repo.ReviewersCount != nil {
+        count := *repo.ReviewersCount
+        result.ReviewersCount = &count
+    }
+    if repo.RequiredApprovals != nil {
+        approvals := *repo.RequiredApprovals
+        result.RequiredApprovals = &approvals
+    }
+    return &result
+}
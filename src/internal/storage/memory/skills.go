@@ -0,0 +1,63 @@
+package memory
+
+import (
+    "slices"
+
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/storage"
+)
+
+func (s *Store) SetUserSkills(userID string, skills []string) (*models.UserSkills, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    u, exists := s.users[userID]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+    u.skills = append([]string{}, skills...)
+    return &models.UserSkills{UserID: userID, Skills: append([]string{}, u.skills...)}, nil
+}
+
+func (s *Store) GetUserSkills(userID string) (*models.UserSkills, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    u, exists := s.users[userID]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+    return &models.UserSkills{UserID: userID, Skills: append([]string{}, u.skills...)}, nil
+}
+
+// hasSkill сообщает, есть ли у пользователя навык из меток
+func (u *user) hasSkill(labels []string) bool {
+    for _, skill := range u.skills {
+        if slices.Contains(labels, skill) {
+            return true
+        }
+    }
+    return false
+}
+
+// skilledMembers отбирает участников команды, навыки которых пересекаются с метками PR
+func (s *Store) skilledMembers(teamName string, labels []string) map[string]bool {
+    result := make(map[string]bool)
+    for _, u := range s.teamMembers(teamName) {
+        if u.hasSkill(labels) {
+            result[u.UserID] = true
+        }
+    }
+    return result
+}
+
+// replacementSkills повторяет одноимённую функцию DB: участники с навыками нужны, только если без oldUserID
+// метки PR никто из ревьюверов не покрывает
+func (s *Store) replacementSkills(teamName string, pr *pullRequest, oldUserID string) map[string]bool {
+    for _, r := range pr.reviewers {
+        if u, ok := s.users[r.userID]; ok && r.userID != oldUserID && u.hasSkill(pr.labels) {
+            return map[string]bool{}
+        }
+    }
+    return s.skilledMembers(teamName, pr.labels)
+}
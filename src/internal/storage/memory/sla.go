@@ -0,0 +1,88 @@
+package memory
+
+import (
+    "errors"
+    "sort"
+    "time"
+
+    "pr-reviewer/src/internal/domain/events"
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/storage"
+)
+
+func (s *Store) EscalateOverdueReviews(limit int) ([]models.ReviewEscalation, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    type overdueReview struct {
+        pr         *pullRequest
+        escalation models.ReviewEscalation
+        reassign   bool
+    }
+
+    now := time.Now()
+    var overdue []overdueReview
+    for _, pr := range s.prs {
+        if pr.status != "OPEN" {
+            continue
+        }
+        teamName, _ := s.reviewTeam(pr.authorID, pr.repo)
+        settings := s.teamSettings(teamName)
+        if settings.ReviewSLASeconds <= 0 {
+            continue
+        }
+        sla := time.Duration(settings.ReviewSLASeconds) * time.Second
+        for _, r := range pr.reviewers {
+            if r.decision == "" && r.escalatedAt.IsZero() && !r.assignedAt.Add(sla).After(now) {
+                overdue = append(overdue, overdueReview{
+                    pr: pr,
+                    escalation: models.ReviewEscalation{
+                        PullRequestID: pr.id,
+                        ReviewerID:    r.userID,
+                        AssignedAt:    r.assignedAt,
+                        SLASeconds:    settings.ReviewSLASeconds,
+                    },
+                    reassign: settings.SLAAutoReassign,
+                })
+            }
+        }
+    }
+    sort.Slice(overdue, func(i, j int) bool {
+        a, b := overdue[i].escalation, overdue[j].escalation
+        if !a.AssignedAt.Equal(b.AssignedAt) {
+            return a.AssignedAt.Before(b.AssignedAt)
+        }
+        if a.PullRequestID != b.PullRequestID {
+            return a.PullRequestID < b.PullRequestID
+        }
+        return a.ReviewerID < b.ReviewerID
+    })
+    if len(overdue) > limit {
+        overdue = overdue[:limit]
+    }
+
+    escalations := []models.ReviewEscalation{}
+    for _, o := range overdue {
+        escalation := o.escalation
+        if r := o.pr.reviewer(escalation.ReviewerID); r != nil {
+            r.escalatedAt = now
+        }
+
+        if o.reassign {
+            newUserID, err := s.replaceReviewer(o.pr, escalation.ReviewerID, models.ReasonSLABreached, models.ActorSystem)
+            if err != nil && !errors.Is(err, database.ErrNoCandidate) {
+                return nil, err
+            }
+            escalation.ReplacedBy = newUserID
+        }
+
+        result := o.pr.toModel()
+        s.addEvents(events.ReviewEscalatedEvent(result, escalation))
+        if escalation.ReplacedBy != "" {
+            s.addEvents(events.ReviewerReplacedEvent(result, escalation.ReviewerID, escalation.ReplacedBy))
+        }
+        escalations = append(escalations, escalation)
+    }
+
+    return escalations, nil
+}
@@ -0,0 +1,134 @@
+package memory
+
+import (
+    "sort"
+
+    "pr-reviewer/src/internal/domain/models"
+)
+
+func (s *Store) GetSystemStats() (*models.SystemStats, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    stats := models.SystemStats{
+        TotalTeams: len(s.teams),
+        TotalUsers: len(s.users),
+        TotalPRs:   len(s.prs),
+    }
+
+    for _, pr := range s.prs {
+        switch pr.status {
+        case "OPEN":
+            stats.TotalOpenPRs++
+        case "MERGED":
+            stats.TotalMergedPRs++
+        case "CLOSED":
+            stats.TotalClosedPRs++
+        }
+        stats.TotalReviews += len(pr.reviewers)
+    }
+
+    if stats.TotalPRs > 0 {
+        stats.AvgReviewsPerPR = float64(stats.TotalReviews) / float64(stats.TotalPRs)
+    }
+
+    return &stats, nil
+}
+
+func (s *Store) GetTopReviewers(limit int) ([]models.TopReviewer, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    counts := s.reviewCounts()
+
+    var reviewers []models.TopReviewer
+    for _, u := range s.users {
+        reviewers = append(reviewers, models.TopReviewer{
+            UserID:   u.UserID,
+            Username: u.Username,
+            Count:    counts[u.UserID],
+        })
+    }
+    sort.SliceStable(reviewers, func(i, j int) bool {
+        if reviewers[i].Count != reviewers[j].Count {
+            return reviewers[i].Count > reviewers[j].Count
+        }
+        return reviewers[i].UserID < reviewers[j].UserID
+    })
+
+    if len(reviewers) > limit {
+        reviewers = reviewers[:limit]
+    }
+    return reviewers, nil
+}
+
+func (s *Store) GetUserStats() ([]models.UserStats, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    reviews := s.reviewCounts()
+    authored := make(map[string]int)
+    for _, pr := range s.prs {
+        authored[pr.authorID]++
+    }
+
+    var userStats []models.UserStats
+    for _, u := range s.users {
+        userStats = append(userStats, models.UserStats{
+            UserID:       u.UserID,
+            Username:     u.Username,
+            TeamName:     u.TeamName,
+            IsActive:     u.IsActive,
+            PRsCount:     authored[u.UserID],
+            ReviewsCount: reviews[u.UserID],
+        })
+    }
+    sort.SliceStable(userStats, func(i, j int) bool {
+        if userStats[i].ReviewsCount != userStats[j].ReviewsCount {
+            return userStats[i].ReviewsCount > userStats[j].ReviewsCount
+        }
+        if userStats[i].PRsCount != userStats[j].PRsCount {
+            return userStats[i].PRsCount > userStats[j].PRsCount
+        }
+        return userStats[i].UserID < userStats[j].UserID
+    })
+
+    return userStats, nil
+}
+
+func (s *Store) GetPRStats() ([]models.PRStats, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    prs := s.sortedPullRequests()
+
+    var prStats []models.PRStats
+    for i := len(prs) - 1; i >= 0; i-- {
+        pr := prs[i]
+        stat := models.PRStats{
+            PullRequestID:   pr.id,
+            PullRequestName: pr.name,
+            AuthorID:        pr.authorID,
+            Status:          pr.status,
+            ReviewersCount:  len(pr.reviewers),
+            CreatedAt:       pr.createdAt,
+            MergedAt:        pr.mergedAt,
+        }
+        if author, exists := s.users[pr.authorID]; exists {
+            stat.AuthorName = author.Username
+        }
+        prStats = append(prStats, stat)
+    }
+
+    return prStats, nil
+}
+
+func (s *Store) reviewCounts() map[string]int {
+    counts := make(map[string]int)
+    for _, pr := range s.prs {
+        for _, r := range pr.reviewers {
+            counts[r.userID]++
+        }
+    }
+    return counts
+}
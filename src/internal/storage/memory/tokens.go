@@ -0,0 +1,82 @@
+package memory
+
+import (
+    "sort"
+    "time"
+
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/storage"
+)
+
+type apiToken struct {
+    models.APIToken
+    hash string
+}
+
+func (s *Store) CreateAPIToken(token models.APIToken, tokenHash string) (*models.APIToken, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if _, exists := s.users[token.UserID]; token.UserID != "" && !exists {
+        return nil, database.ErrNotFound
+    }
+
+    token.CreatedAt = time.Now()
+    token.RevokedAt = nil
+    s.tokens[token.TokenID] = &apiToken{APIToken: token, hash: tokenHash}
+
+    return copyToken(&token), nil
+}
+
+func (s *Store) GetAPITokenByHash(tokenHash string) (*models.APIToken, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for _, token := range s.tokens {
+        if token.hash == tokenHash && token.RevokedAt == nil {
+            return copyToken(&token.APIToken), nil
+        }
+    }
+    return nil, database.ErrNotFound
+}
+
+func (s *Store) ListAPITokens() ([]models.APIToken, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    tokens := []models.APIToken{}
+    for _, token := range s.tokens {
+        tokens = append(tokens, *copyToken(&token.APIToken))
+    }
+    sort.Slice(tokens, func(i, j int) bool {
+        if !tokens[i].CreatedAt.Equal(tokens[j].CreatedAt) {
+            return tokens[i].CreatedAt.Before(tokens[j].CreatedAt)
+        }
+        return tokens[i].TokenID < tokens[j].TokenID
+    })
+    return tokens, nil
+}
+
+func (s *Store) RevokeAPIToken(tokenID string) (*models.APIToken, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    token, exists := s.tokens[tokenID]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+    if token.RevokedAt == nil {
+        revokedAt := time.Now()
+        token.RevokedAt = &revokedAt
+    }
+    return copyToken(&token.APIToken), nil
+}
+
+func copyToken(token *models.APIToken) *models.APIToken {
+    result := *token
+    if token.RevokedAt != nil {
+        revokedAt := *token.RevokedAt
+        result.RevokedAt = &revokedAt
+    }
+    return &result
+}
@@ -0,0 +1,282 @@
+package memory
+
+import (
+    "encoding/json"
+    "sort"
+    "time"
+
+    "pr-reviewer/src/internal/domain/events"
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/storage"
+)
+
+func (s *Store) CreateWebhookSubscription(sub models.WebhookSubscription) (*models.WebhookSubscription, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    sub.Events = append([]string{}, sub.Events...)
+    sub.CreatedAt = time.Now()
+    s.webhooks[sub.SubscriptionID] = &sub
+
+    return copySubscription(&sub), nil
+}
+
+func (s *Store) GetWebhookSubscription(subscriptionID string) (*models.WebhookSubscription, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    sub, exists := s.webhooks[subscriptionID]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+
+    return copySubscription(sub), nil
+}
+
+func (s *Store) ListWebhookSubscriptions() ([]models.WebhookSubscription, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    subs := []models.WebhookSubscription{}
+    for _, sub := range s.webhooks {
+        subs = append(subs, *copySubscription(sub))
+    }
+    sort.Slice(subs, func(i, j int) bool {
+        if !subs[i].CreatedAt.Equal(subs[j].CreatedAt) {
+            return subs[i].CreatedAt.Before(subs[j].CreatedAt)
+        }
+        return subs[i].SubscriptionID < subs[j].SubscriptionID
+    })
+
+    return subs, nil
+}
+
+func (s *Store) UpdateWebhookSubscription(sub models.WebhookSubscription) (*models.WebhookSubscription, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    existing, exists := s.webhooks[sub.SubscriptionID]
+    if !exists {
+        return nil, database.ErrNotFound
+    }
+
+    existing.URL = sub.URL
+    existing.Secret = sub.Secret
+    existing.Events = append([]string{}, sub.Events...)
+    existing.IsActive = sub.IsActive
+
+    return copySubscription(existing), nil
+}
+
+func (s *Store) DeleteWebhookSubscription(subscriptionID string) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if _, exists := s.webhooks[subscriptionID]; !exists {
+        return database.ErrNotFound
+    }
+    delete(s.webhooks, subscriptionID)
+
+    deliveries := s.deliveries[:0]
+    for _, d := range s.deliveries {
+        if d.SubscriptionID != subscriptionID {
+            deliveries = append(deliveries, d)
+        }
+    }
+    s.deliveries = deliveries
+
+    return nil
+}
+
+func (s *Store) EnqueueWebhookEvent(event events.Event) (int, error) {
+    payload, err := json.Marshal(event)
+    if err != nil {
+        return 0, err
+    }
+
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    queued := 0
+    now := time.Now()
+    for _, sub := range s.sortedSubscriptions() {
+        if !sub.IsActive || !subscribedTo(sub, event.Type) || s.hasDelivery(sub.SubscriptionID, event.ID) {
+            continue
+        }
+
+        next := now
+        s.deliveries = append(s.deliveries, &models.WebhookDelivery{
+            DeliveryID:     s.nextDeliveryID(),
+            SubscriptionID: sub.SubscriptionID,
+            EventID:        event.ID,
+            EventType:      event.Type,
+            Payload:        payload,
+            Status:         models.DeliveryPending,
+            NextAttemptAt:  &next,
+            CreatedAt:      now,
+        })
+        queued++
+    }
+
+    return queued, nil
+}
+
+func (s *Store) ClaimWebhookDeliveries(lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    now := time.Now()
+    var due []*models.WebhookDelivery
+    for _, d := range s.deliveries {
+        sub := s.webhooks[d.SubscriptionID]
+        if d.Status == models.DeliveryPending && sub != nil && sub.IsActive && !d.NextAttemptAt.After(now) {
+            due = append(due, d)
+        }
+    }
+    sort.SliceStable(due, func(i, j int) bool {
+        return due[i].NextAttemptAt.Before(*due[j].NextAttemptAt)
+    })
+    if len(due) > limit {
+        due = due[:limit]
+    }
+
+    claimed := []models.WebhookDelivery{}
+    for _, d := range due {
+        next := now.Add(lease)
+        d.NextAttemptAt = &next
+        claimed = append(claimed, *copyDelivery(d))
+    }
+
+    return claimed, nil
+}
+
+func (s *Store) RecordWebhookAttempt(deliveryID int64, attempt database.DeliveryAttempt) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    d := s.delivery(deliveryID)
+    if d == nil {
+        return nil
+    }
+
+    now := time.Now()
+    d.Status = attempt.Status
+    d.Attempts++
+    d.LastStatusCode = attempt.StatusCode
+    d.LastError = attempt.Error
+    d.NextAttemptAt = nil
+    d.DeliveredAt = nil
+    switch attempt.Status {
+    case models.DeliveryPending:
+        next := now.Add(attempt.RetryIn)
+        d.NextAttemptAt = &next
+    case models.DeliveryDelivered:
+        d.DeliveredAt = &now
+    }
+
+    return nil
+}
+
+func (s *Store) ListWebhookDeliveries(filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    deliveries := []models.WebhookDelivery{}
+    for i := len(s.deliveries) - 1; i >= 0 && len(deliveries) < filter.Limit; i-- {
+        d := s.deliveries[i]
+        if filter.SubscriptionID != "" && d.SubscriptionID != filter.SubscriptionID {
+            continue
+        }
+        if filter.Status != "" && d.Status != filter.Status {
+            continue
+        }
+        deliveries = append(deliveries, *copyDelivery(d))
+    }
+
+    return deliveries, nil
+}
+
+func (s *Store) ReplayWebhookDelivery(deliveryID int64) (*models.WebhookDelivery, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    d := s.delivery(deliveryID)
+    if d == nil {
+        return nil, database.ErrNotFound
+    }
+    if d.Status != models.DeliveryDead {
+        return nil, database.ErrNotDeadLetter
+    }
+
+    now := time.Now()
+    d.Status = models.DeliveryPending
+    d.Attempts = 0
+    d.NextAttemptAt = &now
+
+    return copyDelivery(d), nil
+}
+
+func (s *Store) sortedSubscriptions() []*models.WebhookSubscription {
+    subs := make([]*models.WebhookSubscription, 0, len(s.webhooks))
+    for _, sub := range s.webhooks {
+        subs = append(subs, sub)
+    }
+    sort.Slice(subs, func(i, j int) bool {
+        return subs[i].SubscriptionID < subs[j].SubscriptionID
+    })
+    return subs
+}
+
+func (s *Store) hasDelivery(subscriptionID, eventID string) bool {
+    for _, d := range s.deliveries {
+        if d.SubscriptionID == subscriptionID && d.EventID == eventID {
+            return true
+        }
+    }
+    return false
+}
+
+func (s *Store) delivery(deliveryID int64) *models.WebhookDelivery {
+    for _, d := range s.deliveries {
+        if d.DeliveryID == deliveryID {
+            return d
+        }
+    }
+    return nil
+}
+
+func (s *Store) nextDeliveryID() int64 {
+    s.lastDeliveryID++
+    return s.lastDeliveryID
+}
+
+func subscribedTo(sub *models.WebhookSubscription, eventType string) bool {
+    if len(sub.Events) == 0 {
+        return true
+    }
+    for _, t := range sub.Events {
+        if t == eventType {
+            return true
+        }
+    }
+    return false
+}
+
+func copySubscription(sub *models.WebhookSubscription) *models.WebhookSubscription {
+    c := *sub
+    c.Events = append([]string{}, sub.Events...)
+    return &c
+}
+
+func copyDelivery(d *models.WebhookDelivery) *models.WebhookDelivery {
+    c := *d
+    if d.NextAttemptAt != nil {
+        next := *d.NextAttemptAt
+        c.NextAttemptAt = &next
+    }
+    if d.DeliveredAt != nil {
+        delivered := *d.DeliveredAt
+        c.DeliveredAt = &delivered
+    }
+    return &c
+}
@@ -0,0 +1,128 @@
+package database
+
+import (
+    "context"
+    "embed"
+    "errors"
+    "fmt"
+    "io/fs"
+
+    "github.com/golang-migrate/migrate/v4"
+    "github.com/golang-migrate/migrate/v4/database/postgres"
+    "github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+type MigrationStatus struct {
+    Version       uint
+    LatestVersion uint
+    Pending       int
+    Dirty         bool
+}
+
+func (db *DB) MigrateUp() error {
+    m, err := db.newMigrator()
+    if err != nil {
+        return err
+    }
+    defer m.Close()
+
+    if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+        return fmt.Errorf("failed to apply migrations: %v", err)
+    }
+    return nil
+}
+
+func (db *DB) MigrateDown(steps int) error {
+    if steps <= 0 {
+        return fmt.Errorf("steps must be positive, got %d", steps)
+    }
+
+    m, err := db.newMigrator()
+    if err != nil {
+        return err
+    }
+    defer m.Close()
+
+    if err := m.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+        return fmt.Errorf("failed to roll back migrations: %v", err)
+    }
+    return nil
+}
+
+func (db *DB) MigrationStatus() (*MigrationStatus, error) {
+    m, err := db.newMigrator()
+    if err != nil {
+        return nil, err
+    }
+    defer m.Close()
+
+    var status MigrationStatus
+    status.Version, status.Dirty, err = m.Version()
+    if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+        return nil, err
+    }
+
+    versions, err := embeddedVersions()
+    if err != nil {
+        return nil, err
+    }
+    for _, version := range versions {
+        if version > status.Version {
+            status.Pending++
+        }
+        status.LatestVersion = version
+    }
+
+    return &status, nil
+}
+
+func (db *DB) newMigrator() (*migrate.Migrate, error) {
+    ctx := context.Background()
+
+    conn, err := db.Conn(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{})
+    if err != nil {
+        conn.Close()
+        return nil, err
+    }
+
+    src, err := iofs.New(migrationsFS, "migrations")
+    if err != nil {
+        driver.Close()
+        return nil, err
+    }
+
+    return migrate.NewWithInstance("iofs", src, "postgres", driver)
+}
+
+func embeddedVersions() ([]uint, error) {
+    src, err := iofs.New(migrationsFS, "migrations")
+    if err != nil {
+        return nil, err
+    }
+    defer src.Close()
+
+    version, err := src.First()
+    if err != nil {
+        return nil, err
+    }
+
+    versions := []uint{version}
+    for {
+        version, err = src.Next(version)
+        if errors.Is(err, fs.ErrNotExist) {
+            return versions, nil
+        }
+        if err != nil {
+            return nil, err
+        }
+        versions = append(versions, version)
+    }
+}
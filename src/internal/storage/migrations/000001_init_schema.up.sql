@@ -1,14 +1,9 @@
-DROP TABLE IF EXISTS pr_reviewers CASCADE;
-DROP TABLE IF EXISTS pull_requests CASCADE;
-DROP TABLE IF EXISTS users CASCADE;
-DROP TABLE IF EXISTS teams CASCADE;
-
-CREATE TABLE teams (
+CREATE TABLE IF NOT EXISTS teams (
     team_name VARCHAR(255) PRIMARY KEY,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
-CREATE TABLE users (
+CREATE TABLE IF NOT EXISTS users (
     user_id VARCHAR(255) PRIMARY KEY,
     username VARCHAR(255) NOT NULL,
     team_name VARCHAR(255) REFERENCES teams(team_name) ON DELETE CASCADE,
@@ -16,7 +11,7 @@ CREATE TABLE users (
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
-CREATE TABLE pull_requests (
+CREATE TABLE IF NOT EXISTS pull_requests (
     pull_request_id VARCHAR(255) PRIMARY KEY,
     pull_request_name VARCHAR(255) NOT NULL,
     author_id VARCHAR(255) REFERENCES users(user_id),
@@ -25,16 +20,16 @@ CREATE TABLE pull_requests (
     merged_at TIMESTAMP
 );
 
-CREATE TABLE pr_reviewers (
+CREATE TABLE IF NOT EXISTS pr_reviewers (
     pull_request_id VARCHAR(255) REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
     reviewer_id VARCHAR(255) REFERENCES users(user_id),
     assigned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     PRIMARY KEY (pull_request_id, reviewer_id)
 );
 
-CREATE INDEX idx_users_team_active ON users(team_name, is_active);
-CREATE INDEX idx_users_active ON users(is_active);
-CREATE INDEX idx_pr_status ON pull_requests(status);
-CREATE INDEX idx_pr_author ON pull_requests(author_id);
-CREATE INDEX idx_reviewers_pr_id ON pr_reviewers(pull_request_id);
-CREATE INDEX idx_reviewers_user_id ON pr_reviewers(reviewer_id);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_users_team_active ON users(team_name, is_active);
+CREATE INDEX IF NOT EXISTS idx_users_active ON users(is_active);
+CREATE INDEX IF NOT EXISTS idx_pr_status ON pull_requests(status);
+CREATE INDEX IF NOT EXISTS idx_pr_author ON pull_requests(author_id);
+CREATE INDEX IF NOT EXISTS idx_reviewers_pr_id ON pr_reviewers(pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_reviewers_user_id ON pr_reviewers(reviewer_id);
@@ -0,0 +1,3 @@
+ALTER TABLE teams DROP COLUMN IF EXISTS min_reviewers_count;
+ALTER TABLE teams DROP COLUMN IF EXISTS reviewers_count;
+ALTER TABLE teams DROP COLUMN IF EXISTS assignment_strategy;
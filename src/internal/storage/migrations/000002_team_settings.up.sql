@@ -0,0 +1,3 @@
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS assignment_strategy VARCHAR(50) NOT NULL DEFAULT 'least_loaded';
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS reviewers_count INT NOT NULL DEFAULT 2;
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS min_reviewers_count INT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE pr_reviewers ADD COLUMN IF NOT EXISTS decision VARCHAR(50);
+ALTER TABLE pr_reviewers ADD COLUMN IF NOT EXISTS decided_at TIMESTAMP;
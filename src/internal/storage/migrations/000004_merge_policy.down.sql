@@ -0,0 +1,2 @@
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS force_merged;
+ALTER TABLE teams DROP COLUMN IF EXISTS required_approvals;
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS github_users (
+    github_login VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL UNIQUE REFERENCES users(user_id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
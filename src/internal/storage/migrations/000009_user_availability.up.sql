@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS user_availability (
+    availability_id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    reassign_reviews BOOLEAN NOT NULL DEFAULT FALSE,
+    reassigned_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_availability_user ON user_availability(user_id, ends_at);
+CREATE INDEX IF NOT EXISTS idx_user_availability_pending ON user_availability(starts_at) WHERE reassign_reviews AND reassigned_at IS NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_reviewers_pending_sla;
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS escalated_at;
+ALTER TABLE teams DROP COLUMN IF EXISTS sla_auto_reassign;
+ALTER TABLE teams DROP COLUMN IF EXISTS review_sla_seconds;
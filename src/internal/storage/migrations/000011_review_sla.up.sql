@@ -0,0 +1,5 @@
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS review_sla_seconds INT NOT NULL DEFAULT 0;
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS sla_auto_reassign BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE pr_reviewers ADD COLUMN IF NOT EXISTS escalated_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_reviewers_pending_sla ON pr_reviewers(assigned_at) WHERE decision IS NULL AND escalated_at IS NULL;
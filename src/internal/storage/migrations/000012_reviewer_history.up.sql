@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS pr_reviewer_history (
+    history_id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    reviewer_id VARCHAR(255) NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('ASSIGNED', 'REPLACED', 'REMOVED')),
+    replaced_by VARCHAR(255),
+    reason VARCHAR(50) NOT NULL,
+    actor VARCHAR(255) NOT NULL DEFAULT '',
+    assigned_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviewer_history_pr ON pr_reviewer_history(pull_request_id, history_id);
+
+-- Текущие назначения становятся началом истории
+INSERT INTO pr_reviewer_history (pull_request_id, reviewer_id, action, reason, actor, created_at)
+SELECT pull_request_id, reviewer_id, 'ASSIGNED', 'BACKFILL', 'system', COALESCE(assigned_at, CURRENT_TIMESTAMP)
+FROM pr_reviewers
+ORDER BY assigned_at, pull_request_id, reviewer_id;
@@ -0,0 +1,4 @@
+-- Переименование команды переносит участников, удаление команды оставляет их без команды, а не удаляет
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_team_name_fkey;
+ALTER TABLE users ADD CONSTRAINT users_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON UPDATE CASCADE ON DELETE SET NULL;
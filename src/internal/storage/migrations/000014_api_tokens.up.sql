@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS api_tokens (
+    token_id VARCHAR(64) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    scope VARCHAR(20) NOT NULL CHECK (scope IN ('admin', 'user')),
+    user_id VARCHAR(255) REFERENCES users(user_id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP,
+    CHECK (scope <> 'user' OR user_id IS NOT NULL)
+);
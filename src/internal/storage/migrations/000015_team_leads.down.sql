@@ -0,0 +1,5 @@
+DELETE FROM api_tokens WHERE scope = 'bot';
+ALTER TABLE api_tokens DROP CONSTRAINT IF EXISTS api_tokens_scope_check;
+ALTER TABLE api_tokens ADD CONSTRAINT api_tokens_scope_check CHECK (scope IN ('admin', 'user'));
+
+DROP TABLE IF EXISTS team_leads;
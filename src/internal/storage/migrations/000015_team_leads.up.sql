@@ -0,0 +1,13 @@
+-- Руководители команд; переименование команды переносит их, удаление команды или пользователя - удаляет
+CREATE TABLE IF NOT EXISTS team_leads (
+    team_name VARCHAR(255) NOT NULL REFERENCES teams(team_name) ON UPDATE CASCADE ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (team_name, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_leads_user ON team_leads(user_id);
+
+-- Токены ботов только читают
+ALTER TABLE api_tokens DROP CONSTRAINT IF EXISTS api_tokens_scope_check;
+ALTER TABLE api_tokens ADD CONSTRAINT api_tokens_scope_check CHECK (scope IN ('admin', 'user', 'bot'));
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS code_owners (
+    repository VARCHAR(255) PRIMARY KEY,
+    content TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_pr_repository;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS repository;
+DROP TABLE IF EXISTS repositories;
@@ -0,0 +1,15 @@
+-- Репозитории и команды-владельцы; NULL в настройках - как у команды
+CREATE TABLE IF NOT EXISTS repositories (
+    repository VARCHAR(255) PRIMARY KEY,
+    team_name VARCHAR(255) NOT NULL REFERENCES teams(team_name) ON UPDATE CASCADE ON DELETE CASCADE,
+    reviewers_count INTEGER CHECK (reviewers_count >= 0),
+    required_approvals INTEGER CHECK (required_approvals >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_repositories_team ON repositories(team_name);
+
+-- Репозиторий PR не ссылается на repositories: его можно завести позже, и PR перейдёт к команде-владельцу
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS repository VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_pr_repository ON pull_requests(repository);
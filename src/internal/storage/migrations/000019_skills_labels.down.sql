@@ -0,0 +1,2 @@
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS labels;
+DROP TABLE IF EXISTS user_skills;
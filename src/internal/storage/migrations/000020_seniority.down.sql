@@ -0,0 +1,4 @@
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS shadow;
+ALTER TABLE teams DROP COLUMN IF EXISTS shadow_reviewers;
+ALTER TABLE teams DROP COLUMN IF EXISTS min_reviewer_level;
+ALTER TABLE users DROP COLUMN IF EXISTS level;
@@ -0,0 +1,9 @@
+-- Уровни участников, правило "хотя бы один старший ревьювер" и теневые ревьюверы-junior
+ALTER TABLE users ADD COLUMN IF NOT EXISTS level VARCHAR(20) NOT NULL DEFAULT 'middle'
+    CHECK (level IN ('junior', 'middle', 'senior'));
+
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS min_reviewer_level VARCHAR(20) NOT NULL DEFAULT ''
+    CHECK (min_reviewer_level IN ('', 'junior', 'middle', 'senior'));
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS shadow_reviewers INTEGER NOT NULL DEFAULT 0 CHECK (shadow_reviewers >= 0);
+
+ALTER TABLE pr_reviewers ADD COLUMN IF NOT EXISTS shadow BOOLEAN NOT NULL DEFAULT FALSE;
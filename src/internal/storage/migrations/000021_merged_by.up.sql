@@ -0,0 +1,2 @@
+-- Кто зафиксировал мерж, сделанный вне сервиса (например, github)
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS merged_by VARCHAR(50);
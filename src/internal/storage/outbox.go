@@ -0,0 +1,120 @@
+package database
+
+import (
+    "database/sql"
+    "encoding/json"
+    "pr-reviewer/src/internal/domain/events"
+    "sort"
+    "time"
+
+    "github.com/lib/pq"
+)
+
+// OutboxEntry - событие из outbox, ожидающее отправки
+type OutboxEntry struct {
+    ID       int64
+    Event    events.Event
+    Attempts int
+}
+
+// insertEvents записывает события в outbox в той же транзакции, что и изменения данных
+func insertEvents(tx *sql.Tx, evts ...events.Event) error {
+    if len(evts) == 0 {
+        return nil
+    }
+
+    ids := make([]string, len(evts))
+    types := make([]string, len(evts))
+    payloads := make([]string, len(evts))
+    for i, event := range evts {
+        payload, err := json.Marshal(event)
+        if err != nil {
+            return err
+        }
+        ids[i], types[i], payloads[i] = event.ID, event.Type, string(payload)
+    }
+
+    _, err := tx.Exec(`
+        INSERT INTO outbox_events (event_id, event_type, payload)
+        SELECT e.event_id, e.event_type, e.payload
+        FROM unnest($1::text[], $2::text[], $3::jsonb[]) WITH ORDINALITY AS e(event_id, event_type, payload, n)
+        ORDER BY e.n
+    `, pq.Array(ids), pq.Array(types), pq.Array(payloads))
+    return err
+}
+
+func (db *DB) ClaimOutboxEvents(lease time.Duration, limit int) ([]OutboxEntry, error) {
+    rows, err := db.Query(`
+        UPDATE outbox_events
+        SET next_attempt_at = CURRENT_TIMESTAMP + $1::bigint * INTERVAL '1 millisecond'
+        WHERE id IN (
+            SELECT id
+            FROM outbox_events
+            WHERE dispatched_at IS NULL AND next_attempt_at <= CURRENT_TIMESTAMP
+            ORDER BY id
+            LIMIT $2
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING id, payload, attempts
+    `, lease.Milliseconds(), limit)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    entries := []OutboxEntry{}
+    for rows.Next() {
+        var entry OutboxEntry
+        var payload []byte
+        if err := rows.Scan(&entry.ID, &payload, &entry.Attempts); err != nil {
+            return nil, err
+        }
+        if err := json.Unmarshal(payload, &entry.Event); err != nil {
+            return nil, err
+        }
+        entries = append(entries, entry)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    sort.Slice(entries, func(i, j int) bool {
+        return entries[i].ID < entries[j].ID
+    })
+
+    return entries, nil
+}
+
+func (db *DB) MarkOutboxDispatched(id int64) error {
+    _, err := db.Exec(`
+        UPDATE outbox_events
+        SET dispatched_at = CURRENT_TIMESTAMP, attempts = attempts + 1, last_error = NULL
+        WHERE id = $1
+    `, id)
+    return err
+}
+
+func (db *DB) MarkOutboxFailed(id int64, errMsg string, retryIn time.Duration) error {
+    _, err := db.Exec(`
+        UPDATE outbox_events
+        SET attempts = attempts + 1,
+            last_error = $2,
+            next_attempt_at = CURRENT_TIMESTAMP + $3::bigint * INTERVAL '1 millisecond'
+        WHERE id = $1
+    `, id, errMsg, retryIn.Milliseconds())
+    return err
+}
+
+func (db *DB) PurgeOutbox(olderThan time.Duration) (int, error) {
+    result, err := db.Exec(`
+        DELETE FROM outbox_events
+        WHERE dispatched_at IS NOT NULL
+        AND dispatched_at < CURRENT_TIMESTAMP - $1::bigint * INTERVAL '1 millisecond'
+    `, olderThan.Milliseconds())
+    if err != nil {
+        return 0, err
+    }
+
+    affected, err := result.RowsAffected()
+    return int(affected), err
+}
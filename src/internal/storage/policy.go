@@ -0,0 +1,57 @@
+package database
+
+import (
+    "errors"
+    "pr-reviewer/src/internal/domain/models"
+)
+
+var ErrNotApproved = errors.New("NOT_APPROVED")
+
+// MergeBlockedError описывает, чего не хватает PR для мержа по политике команды
+type MergeBlockedError struct {
+    RequiredApprovals  int      `json:"required_approvals"`
+    Approvals          int      `json:"approvals"`
+    MissingApprovals   []string `json:"missing_approvals"`
+    ChangesRequestedBy []string `json:"changes_requested_by,omitempty"`
+}
+
+func (e *MergeBlockedError) Error() string {
+    return ErrNotApproved.Error()
+}
+
+func (e *MergeBlockedError) Is(target error) bool {
+    return target == ErrNotApproved
+}
+
+// CheckMergePolicy возвращает *MergeBlockedError, если ревьюверы не набрали
+// requiredApprovals одобрений или кто-то из них запросил изменения.
+// Нулевое значение requiredApprovals отключает проверку, теневые ревьюверы не учитываются.
+func CheckMergePolicy(requiredApprovals int, reviewers []models.ReviewerState) error {
+    if requiredApprovals <= 0 {
+        return nil
+    }
+
+    blocked := &MergeBlockedError{
+        RequiredApprovals: requiredApprovals,
+        MissingApprovals:  []string{},
+    }
+    for _, reviewer := range reviewers {
+        if reviewer.Shadow {
+            continue
+        }
+        switch reviewer.State {
+        case models.ReviewApproved:
+            blocked.Approvals++
+        case models.ReviewChangesRequested:
+            blocked.ChangesRequestedBy = append(blocked.ChangesRequestedBy, reviewer.UserID)
+            blocked.MissingApprovals = append(blocked.MissingApprovals, reviewer.UserID)
+        default:
+            blocked.MissingApprovals = append(blocked.MissingApprovals, reviewer.UserID)
+        }
+    }
+
+    if blocked.Approvals >= requiredApprovals && len(blocked.ChangesRequestedBy) == 0 {
+        return nil
+    }
+    return blocked
+}
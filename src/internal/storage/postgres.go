@@ -3,27 +3,34 @@ package database
 import (
     "database/sql"
     "errors"
-    "fmt"
-    "os"
+    "pr-reviewer/src/internal/domain/assignment"
+    "pr-reviewer/src/internal/domain/events"
     "pr-reviewer/src/internal/domain/models"
     "time"
 
-    _ "github.com/lib/pq"
+    "github.com/lib/pq"
 )
 
 var (
     ErrTeamExists     = errors.New("TEAM_EXISTS")
     ErrPRExists       = errors.New("PR_EXISTS")
     ErrPRMerged       = errors.New("PR_MERGED")
+    ErrPRClosed       = errors.New("PR_CLOSED")
     ErrNotAssigned    = errors.New("NOT_ASSIGNED")
     ErrNoCandidate    = errors.New("NO_CANDIDATE")
     ErrNotFound       = errors.New("NOT_FOUND")
+    ErrNotDeadLetter  = errors.New("NOT_DEAD_LETTER")
 )
 
 type DB struct {
     *sql.DB
 }
 
+type queryer interface {
+    Query(query string, args ...interface{}) (*sql.Rows, error)
+    QueryRow(query string, args ...interface{}) *sql.Row
+}
+
 func New(connectionString string) (*DB, error) {
     db, err := sql.Open("postgres", connectionString)
     if err != nil {
@@ -31,92 +38,13 @@ func New(connectionString string) (*DB, error) {
     }
 
     if err := db.Ping(); err != nil {
+        db.Close()
         return nil, err
     }
 
-    if shouldResetDB() {
-        if err := resetDatabase(db); err != nil {
-            return nil, fmt.Errorf("failed to reset database: %v", err)
-        }
-        fmt.Println("Database reset completed")
-    } else {
-        if err := initTables(db); err != nil {
-            return nil, err
-        }
-    }
-
     return &DB{db}, nil
 }
 
-func shouldResetDB() bool {
-    return os.Getenv("RESET_DB_ON_STARTUP") == "true"
-}
-
-func resetDatabase(db *sql.DB) error {
-    dropQueries := []string{
-        "DROP TABLE IF EXISTS pr_reviewers CASCADE",
-        "DROP TABLE IF EXISTS pull_requests CASCADE", 
-        "DROP TABLE IF EXISTS users CASCADE",
-        "DROP TABLE IF EXISTS teams CASCADE",
-    }
-
-    for _, query := range dropQueries {
-        if _, err := db.Exec(query); err != nil {
-            return fmt.Errorf("failed to drop table: %v", err)
-        }
-    }
-
-    return initTables(db)
-}
-
-func initTables(db *sql.DB) error {
-    queries := []string{
-        `CREATE TABLE IF NOT EXISTS teams (
-            team_name VARCHAR(255) PRIMARY KEY,
-            created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-        )`,
-
-        `CREATE TABLE IF NOT EXISTS users (
-            user_id VARCHAR(255) PRIMARY KEY,
-            username VARCHAR(255) NOT NULL,
-            team_name VARCHAR(255) REFERENCES teams(team_name) ON DELETE CASCADE,
-            is_active BOOLEAN DEFAULT TRUE,
-            created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-        )`,
-
-        `CREATE TABLE IF NOT EXISTS pull_requests (
-            pull_request_id VARCHAR(255) PRIMARY KEY,
-            pull_request_name VARCHAR(255) NOT NULL,
-            author_id VARCHAR(255) REFERENCES users(user_id),
-            status VARCHAR(50) DEFAULT 'OPEN',
-            created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-            merged_at TIMESTAMP
-        )`,
-
-        `CREATE TABLE IF NOT EXISTS pr_reviewers (
-            pull_request_id VARCHAR(255) REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
-            reviewer_id VARCHAR(255) REFERENCES users(user_id),
-            assigned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-            PRIMARY KEY (pull_request_id, reviewer_id)
-        )`,
-
-        `CREATE INDEX IF NOT EXISTS idx_users_team_active ON users(team_name, is_active)`,
-        `CREATE INDEX IF NOT EXISTS idx_users_active ON users(is_active)`,
-        `CREATE INDEX IF NOT EXISTS idx_pr_status ON pull_requests(status)`,
-        `CREATE INDEX IF NOT EXISTS idx_pr_author ON pull_requests(author_id)`,
-        `CREATE INDEX IF NOT EXISTS idx_reviewers_pr_id ON pr_reviewers(pull_request_id)`,
-        `CREATE INDEX IF NOT EXISTS idx_reviewers_user_id ON pr_reviewers(reviewer_id)`,
-    }
-
-    for _, query := range queries {
-        if _, err := db.Exec(query); err != nil {
-            return fmt.Errorf("failed to create table/index: %v", err)
-        }
-    }
-
-    return nil
-}
-
 func (db *DB) CreateTeam(team models.Team) error {
     tx, err := db.Begin()
     if err != nil {
@@ -133,19 +61,30 @@ func (db *DB) CreateTeam(team models.Team) error {
         return ErrTeamExists
     }
 
-    _, err = tx.Exec("INSERT INTO teams (team_name) VALUES ($1)", team.TeamName)
+    strategy := team.AssignmentStrategy
+    if strategy == "" {
+        strategy = assignment.Default
+    }
+
+    userIDs := make([]string, len(team.Members))
+    for i, member := range team.Members {
+        userIDs[i] = member.UserID
+    }
+    current, err := currentTeams(tx, userIDs)
+    if err != nil {
+        return err
+    }
+    if conflicts := MemberConflicts(team.TeamName, current); len(conflicts) > 0 {
+        return &MemberConflictError{Conflicts: conflicts}
+    }
+
+    _, err = tx.Exec("INSERT INTO teams (team_name, assignment_strategy) VALUES ($1, $2)", team.TeamName, strategy)
     if err != nil {
         return err
     }
 
     for _, member := range team.Members {
-        _, err = tx.Exec(`
-            INSERT INTO users (user_id, username, team_name, is_active) 
-            VALUES ($1, $2, $3, $4)
-            ON CONFLICT (user_id) 
-            DO UPDATE SET username = $2, team_name = $3, is_active = $4
-        `, member.UserID, member.Username, team.TeamName, member.IsActive)
-        if err != nil {
+        if err := upsertMember(tx, team.TeamName, member); err != nil {
             return err
         }
     }
@@ -157,8 +96,16 @@ func (db *DB) GetTeam(teamName string) (*models.Team, error) {
     var team models.Team
     team.TeamName = teamName
 
+    err := db.QueryRow("SELECT assignment_strategy FROM teams WHERE team_name = $1", teamName).Scan(&team.AssignmentStrategy)
+    if err == sql.ErrNoRows {
+        return nil, ErrNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+
     rows, err := db.Query(`
-        SELECT user_id, username, is_active 
+        SELECT user_id, username, is_active, level
         FROM users 
         WHERE team_name = $1
     `, teamName)
@@ -169,7 +116,7 @@ func (db *DB) GetTeam(teamName string) (*models.Team, error) {
 
     for rows.Next() {
         var member models.TeamMember
-        if err := rows.Scan(&member.UserID, &member.Username, &member.IsActive); err != nil {
+        if err := rows.Scan(&member.UserID, &member.Username, &member.IsActive, &member.Level); err != nil {
             return nil, err
         }
         team.Members = append(team.Members, member)
@@ -182,15 +129,85 @@ func (db *DB) GetTeam(teamName string) (*models.Team, error) {
     return &team, nil
 }
 
-func (db *DB) SetUserActive(userID string, isActive bool) (*models.User, error) {
-    var user models.User
+func (db *DB) GetTeamSettings(teamName string) (*models.TeamSettings, error) {
+    settings, err := teamSettings(db, teamName)
+    if err == sql.ErrNoRows {
+        return nil, ErrNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    return settings, nil
+}
+
+func (db *DB) UpdateTeamSettings(settings models.TeamSettings) (*models.TeamSettings, error) {
+    tx, err := db.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
 
-    err := db.QueryRow(`
+    result, err := scanTeamSettings(tx.QueryRow(`
+        UPDATE teams
+        SET assignment_strategy = $2, reviewers_count = $3, min_reviewers_count = $4, required_approvals = $5,
+            max_open_reviews = $6, review_sla_seconds = $7, sla_auto_reassign = $8, min_reviewer_level = $9,
+            shadow_reviewers = $10
+        WHERE team_name = $1
+        RETURNING `+teamSettingsColumns,
+        settings.TeamName, settings.AssignmentStrategy, settings.ReviewersCount, settings.MinReviewersCount,
+        settings.RequiredApprovals, settings.MaxOpenReviews, settings.ReviewSLASeconds, settings.SLAAutoReassign,
+        settings.MinReviewerLevel, settings.ShadowReviewers))
+    if err == sql.ErrNoRows {
+        return nil, ErrNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    if err := setFallbackTeams(tx, settings.TeamName, settings.FallbackTeams); err != nil {
+        return nil, err
+    }
+    if result.FallbackTeams, err = fallbackTeams(tx, settings.TeamName); err != nil {
+        return nil, err
+    }
+
+    return result, tx.Commit()
+}
+
+func (db *DB) SetUserActive(userID string, isActive bool) (*models.User, error) {
+    return scanUser(db.QueryRow(`
         UPDATE users SET is_active = $1 
         WHERE user_id = $2 
-        RETURNING user_id, username, team_name, is_active
-    `, isActive, userID).Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive)
+        RETURNING `+userColumns, isActive, userID))
+}
+
+func (db *DB) SetUserMaxOpenReviews(userID string, maxOpenReviews *int) (*models.User, error) {
+    limit := sql.NullInt64{Valid: maxOpenReviews != nil}
+    if maxOpenReviews != nil {
+        limit.Int64 = int64(*maxOpenReviews)
+    }
+
+    return scanUser(db.QueryRow(`
+        UPDATE users SET max_open_reviews = $1
+        WHERE user_id = $2
+        RETURNING `+userColumns, limit, userID))
+}
+
+func (db *DB) SetUserLevel(userID, level string) (*models.User, error) {
+    return scanUser(db.QueryRow(`
+        UPDATE users SET level = $1
+        WHERE user_id = $2
+        RETURNING `+userColumns, level, userID))
+}
+
+const userColumns = "user_id, username, COALESCE(team_name, ''), is_active, level, max_open_reviews"
+
+func scanUser(row *sql.Row) (*models.User, error) {
+    var user models.User
+    var limit sql.NullInt64
 
+    err := row.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive, &user.Level, &limit)
     if err == sql.ErrNoRows {
         return nil, ErrNotFound
     }
@@ -198,10 +215,15 @@ func (db *DB) SetUserActive(userID string, isActive bool) (*models.User, error)
         return nil, err
     }
 
+    if limit.Valid {
+        maxOpenReviews := int(limit.Int64)
+        user.MaxOpenReviews = &maxOpenReviews
+    }
+
     return &user, nil
 }
 
-func (db *DB) CreatePullRequest(pr models.CreatePRRequest) (*models.PullRequest, error) {
+func (db *DB) CreatePullRequest(pr models.CreatePRRequest, actor string) (*models.PullRequest, error) {
     tx, err := db.Begin()
     if err != nil {
         return nil, err
@@ -217,53 +239,91 @@ func (db *DB) CreatePullRequest(pr models.CreatePRRequest) (*models.PullRequest,
         return nil, ErrPRExists
     }
 
-    var teamName string
     var authorExists bool
-    err = tx.QueryRow("SELECT team_name, EXISTS(SELECT 1 FROM users WHERE user_id = $1) FROM users WHERE user_id = $1", pr.AuthorID).Scan(&teamName, &authorExists)
-    if err == sql.ErrNoRows || !authorExists {
+    err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE user_id = $1)", pr.AuthorID).Scan(&authorExists)
+    if err != nil {
+        return nil, err
+    }
+    if !authorExists {
         return nil, ErrNotFound
     }
+
+    teamName, repo, err := reviewTeam(tx, pr.AuthorID, pr.Repository)
+    // автор без команды может открыть PR только в заведённом репозитории: иначе ревьюверов брать неоткуда
+    if err == nil && teamName == "" {
+        err = ErrNotFound
+    }
     if err != nil {
         return nil, err
     }
 
     _, err = tx.Exec(`
-        INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status) 
-        VALUES ($1, $2, $3, 'OPEN')
-    `, pr.PullRequestID, pr.PullRequestName, pr.AuthorID)
+        INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, repository, labels) 
+        VALUES ($1, $2, $3, 'OPEN', NULLIF($4, ''), COALESCE($5::text[], '{}'))
+    `, pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Repository, pq.Array(pr.Labels))
     if err != nil {
         return nil, err
     }
 
-    rows, err := tx.Query(`
-        SELECT user_id FROM users 
-        WHERE team_name = $1 
-        AND user_id != $2 
-        AND is_active = true 
-        LIMIT 2
-    `, teamName, pr.AuthorID)
+    settings, err := reviewSettings(tx, teamName, repo)
     if err != nil {
         return nil, err
     }
-    defer rows.Close()
 
-    var reviewers []string
-    for rows.Next() {
-        var reviewerID string
-        if err := rows.Scan(&reviewerID); err != nil {
-            return nil, err
-        }
-        reviewers = append(reviewers, reviewerID)
+    candidates, excluded, err := reviewCandidates(tx, teamName, pr.AuthorID, pr.PullRequestID)
+    if err != nil {
+        return nil, err
     }
 
-    for _, reviewerID := range reviewers {
-        _, err = tx.Exec(`
-            INSERT INTO pr_reviewers (pull_request_id, reviewer_id) 
-            VALUES ($1, $2)
-        `, pr.PullRequestID, reviewerID)
-        if err != nil {
-            return nil, err
-        }
+    // владельцы изменённых файлов из ревьюящей команды назначаются в первую очередь
+    owners, err := codeOwnerIDs(tx, teamName, pr.CodeOwners)
+    if err != nil {
+        return nil, err
+    }
+    strategy := teamStrategy(settings)
+    isOwner := func(c assignment.Candidate) bool {
+        return owners[c.UserID]
+    }
+    reviewers := assignment.PickPreferring(strategy, candidates, settings.ReviewersCount, isOwner)
+
+    // хотя бы один ревьювер должен разбираться в метках PR, если такой есть в команде
+    skilled, err := skilledMembers(tx, teamName, pr.Labels)
+    if err != nil {
+        return nil, err
+    }
+    isSkilled := func(c assignment.Candidate) bool {
+        return skilled[c.UserID]
+    }
+    reviewers = assignment.EnsureCovered(strategy, candidates, reviewers, isSkilled, isOwner)
+
+    fallbackPools, err := fallbackCandidates(tx, settings.FallbackTeams, pr.AuthorID, pr.PullRequestID)
+    if err != nil {
+        return nil, err
+    }
+    fallback := PickFallback(fallbackPools, settings.ReviewersCount-len(reviewers), nil, nil)
+
+    // правило уровня обязательное: без подходящего ревьювера в команде или запасных PR не создаётся
+    team := TeamCandidates{Strategy: strategy, Candidates: candidates}
+    reviewers, fallback, err = EnsureLevel(team, fallbackPools, reviewers, fallback, settings.ReviewersCount, settings.MinReviewerLevel,
+        func(c assignment.Candidate) bool {
+            return isOwner(c) || isSkilled(c)
+        }, excluded)
+    if err != nil {
+        return nil, err
+    }
+    if found := len(reviewers) + len(fallback); found < settings.MinReviewersCount {
+        return nil, &NoCandidateError{Required: settings.MinReviewersCount, Found: found, Excluded: excluded}
+    }
+    shadows := PickShadowReviewers(strategy, candidates, reviewers, settings.ShadowReviewers)
+
+    if err := assignReviewers(tx, pr.PullRequestID, reviewers, regularReviewer, models.ReasonPRCreated, actor); err != nil {
+        return nil, err
+    }
+    if err := assignReviewers(tx, pr.PullRequestID, fallback, fallbackReviewer, models.ReasonPRCreated, actor); err != nil {
+        return nil, err
+    }
+    if err := assignReviewers(tx, pr.PullRequestID, shadows, shadowReviewer, models.ReasonPRCreated, actor); err != nil {
+        return nil, err
     }
 
     var result models.PullRequest
@@ -277,7 +337,24 @@ func (db *DB) CreatePullRequest(pr models.CreatePRRequest) (*models.PullRequest,
         return nil, err
     }
     result.CreatedAt = createdAt
-    result.AssignedReviewers = reviewers
+    result.Repository = pr.Repository
+    result.Labels = pr.Labels
+    for _, reviewerID := range reviewers {
+        result.AssignedReviewers = append(result.AssignedReviewers, reviewerID)
+        result.Reviewers = append(result.Reviewers, models.ReviewerState{UserID: reviewerID, State: models.ReviewPending})
+    }
+    for _, reviewerID := range fallback {
+        result.AssignedReviewers = append(result.AssignedReviewers, reviewerID)
+        result.Reviewers = append(result.Reviewers, models.ReviewerState{UserID: reviewerID, State: models.ReviewPending, Fallback: true})
+    }
+    for _, reviewerID := range shadows {
+        result.AssignedReviewers = append(result.AssignedReviewers, reviewerID)
+        result.Reviewers = append(result.Reviewers, models.ReviewerState{UserID: reviewerID, State: models.ReviewPending, Shadow: true})
+    }
+
+    if err := insertEvents(tx, events.PullRequestCreated(&result)...); err != nil {
+        return nil, err
+    }
 
     if err := tx.Commit(); err != nil {
         return nil, err
@@ -286,7 +363,125 @@ func (db *DB) CreatePullRequest(pr models.CreatePRRequest) (*models.PullRequest,
     return &result, nil
 }
 
-func (db *DB) MergePullRequest(prID string) (*models.PullRequest, error) {
+// MergePullRequest мержит OPEN PR по политике команды; при force политика не проверяется,
+// а в merged_by записывается actor, чтобы обход политики было видно
+func (db *DB) MergePullRequest(prID string, force bool, actor string) (*models.PullRequest, error) {
+    tx, err := db.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    var currentStatus, authorID, repository string
+    err = tx.QueryRow(`
+        SELECT status, author_id, COALESCE(repository, '')
+        FROM pull_requests
+        WHERE pull_request_id = $1
+        FOR UPDATE
+    `, prID).Scan(&currentStatus, &authorID, &repository)
+    if err == sql.ErrNoRows {
+        return nil, ErrNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    if currentStatus == "MERGED" {
+        return db.getPullRequest(tx, prID)
+    }
+    if currentStatus == "CLOSED" {
+        return nil, ErrPRClosed
+    }
+
+    if !force {
+        teamName, repo, err := reviewTeam(tx, authorID, repository)
+        if err != nil {
+            return nil, err
+        }
+
+        // у PR без ревьюящей команды нет политики мержа
+        settings := &models.TeamSettings{}
+        if teamName != "" {
+            if settings, err = reviewSettings(tx, teamName, repo); err != nil {
+                return nil, err
+            }
+        }
+
+        pr, err := db.getPullRequest(tx, prID)
+        if err != nil {
+            return nil, err
+        }
+
+        if err := CheckMergePolicy(settings.RequiredApprovals, pr.Reviewers); err != nil {
+            return nil, err
+        }
+    }
+
+    _, err = tx.Exec(`
+        UPDATE pull_requests 
+        SET status = 'MERGED', merged_at = CURRENT_TIMESTAMP, force_merged = $2, merged_by = CASE WHEN $2 THEN $3 END
+        WHERE pull_request_id = $1
+    `, prID, force, actor)
+    if err != nil {
+        return nil, err
+    }
+
+    pr, err := db.getPullRequest(tx, prID)
+    if err != nil {
+        return nil, err
+    }
+
+    if err := insertEvents(tx, events.PullRequestMergedEvent(pr, actor)); err != nil {
+        return nil, err
+    }
+
+    return pr, tx.Commit()
+}
+
+// MergeExternally переводит OPEN или CLOSED PR в MERGED одной транзакцией без политики мержа:
+// мерж уже случился вне сервиса, поэтому PR не переоткрывается и не помечается как force_merged
+func (db *DB) MergeExternally(prID, actor string) (*models.PullRequest, error) {
+    tx, err := db.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    var currentStatus string
+    err = tx.QueryRow("SELECT status FROM pull_requests WHERE pull_request_id = $1 FOR UPDATE", prID).Scan(&currentStatus)
+    if err == sql.ErrNoRows {
+        return nil, ErrNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    if currentStatus == "MERGED" {
+        return db.getPullRequest(tx, prID)
+    }
+
+    _, err = tx.Exec(`
+        UPDATE pull_requests 
+        SET status = 'MERGED', merged_at = CURRENT_TIMESTAMP, closed_at = NULL, merged_by = $2
+        WHERE pull_request_id = $1
+    `, prID, actor)
+    if err != nil {
+        return nil, err
+    }
+
+    pr, err := db.getPullRequest(tx, prID)
+    if err != nil {
+        return nil, err
+    }
+
+    if err := insertEvents(tx, events.PullRequestMergedEvent(pr, actor)); err != nil {
+        return nil, err
+    }
+
+    return pr, tx.Commit()
+}
+
+func (db *DB) ClosePullRequest(prID string) (*models.PullRequest, error) {
     tx, err := db.Begin()
     if err != nil {
         return nil, err
@@ -294,7 +489,56 @@ func (db *DB) MergePullRequest(prID string) (*models.PullRequest, error) {
     defer tx.Rollback()
 
     var currentStatus string
-    err = tx.QueryRow("SELECT status FROM pull_requests WHERE pull_request_id = $1", prID).Scan(&currentStatus)
+    err = tx.QueryRow("SELECT status FROM pull_requests WHERE pull_request_id = $1 FOR UPDATE", prID).Scan(&currentStatus)
+    if err == sql.ErrNoRows {
+        return nil, ErrNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    if currentStatus == "MERGED" {
+        return nil, ErrPRMerged
+    }
+    if currentStatus == "CLOSED" {
+        return db.getPullRequest(tx, prID)
+    }
+
+    _, err = tx.Exec(`
+        UPDATE pull_requests 
+        SET status = 'CLOSED', closed_at = CURRENT_TIMESTAMP 
+        WHERE pull_request_id = $1
+    `, prID)
+    if err != nil {
+        return nil, err
+    }
+
+    pr, err := db.getPullRequest(tx, prID)
+    if err != nil {
+        return nil, err
+    }
+
+    if err := insertEvents(tx, events.PullRequestEvent(events.PRClosed, pr)); err != nil {
+        return nil, err
+    }
+
+    return pr, tx.Commit()
+}
+
+func (db *DB) ReopenPullRequest(prID, actor string) (*models.PullRequest, error) {
+    tx, err := db.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    var currentStatus, authorID, repository string
+    err = tx.QueryRow(`
+        SELECT status, author_id, COALESCE(repository, '')
+        FROM pull_requests
+        WHERE pull_request_id = $1
+        FOR UPDATE
+    `, prID).Scan(&currentStatus, &authorID, &repository)
     if err == sql.ErrNoRows {
         return nil, ErrNotFound
     }
@@ -303,37 +547,159 @@ func (db *DB) MergePullRequest(prID string) (*models.PullRequest, error) {
     }
 
     if currentStatus == "MERGED" {
+        return nil, ErrPRMerged
+    }
+    if currentStatus == "OPEN" {
         return db.getPullRequest(tx, prID)
     }
 
+    teamName, _, err := reviewTeam(tx, authorID, repository)
+    if err != nil {
+        return nil, err
+    }
+
     _, err = tx.Exec(`
         UPDATE pull_requests 
-        SET status = 'MERGED', merged_at = CURRENT_TIMESTAMP 
+        SET status = 'OPEN', closed_at = NULL 
         WHERE pull_request_id = $1
     `, prID)
     if err != nil {
         return nil, err
     }
 
+    // снятых теневых ревьюверов не добирают, поэтому замены ищутся только основным
+    rows, err := tx.Query(`
+        WITH removed AS (
+            DELETE FROM pr_reviewers prr
+            USING users u
+            WHERE prr.pull_request_id = $1
+            AND u.user_id = prr.reviewer_id
+            AND (u.is_active = false OR (u.team_name IS DISTINCT FROM $2 AND NOT (prr.fallback AND u.team_name IN (
+                SELECT fallback_team FROM team_fallbacks WHERE team_name = $2
+            ))))
+            RETURNING prr.pull_request_id, prr.reviewer_id, prr.assigned_at, prr.shadow
+        ), logged AS (
+            INSERT INTO pr_reviewer_history (pull_request_id, reviewer_id, action, reason, actor, assigned_at)
+            SELECT pull_request_id, reviewer_id, 'REMOVED', $3, $4, assigned_at FROM removed
+        )
+        SELECT reviewer_id FROM removed WHERE NOT shadow ORDER BY assigned_at, reviewer_id
+    `, prID, teamName, models.ReasonPRReopened, actor)
+    if err != nil {
+        return nil, err
+    }
+    var removed []string
+    for rows.Next() {
+        var reviewerID string
+        if err := rows.Scan(&reviewerID); err != nil {
+            rows.Close()
+            return nil, err
+        }
+        removed = append(removed, reviewerID)
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    var picked []string
+    if len(removed) > 0 && teamName != "" {
+        if picked, err = refillReviewers(tx, teamName, authorID, prID, removed, actor); err != nil {
+            return nil, err
+        }
+    }
+
     pr, err := db.getPullRequest(tx, prID)
     if err != nil {
         return nil, err
     }
 
+    reopened := []events.Event{events.PullRequestEvent(events.PRReopened, pr)}
+    for _, reviewerID := range picked {
+        reopened = append(reopened, events.ReviewerAssignedEvent(pr, reviewerID))
+    }
+    if err := insertEvents(tx, reopened...); err != nil {
+        return nil, err
+    }
+
     return pr, tx.Commit()
 }
 
-func (db *DB) ReassignReviewer(prID, oldUserID string) (*models.PullRequest, string, error) {
+// refillReviewers назначает замены снятым при переоткрытии основным ревьюверам по правилам PickReplacement.
+// Если после этого основных ревьюверов меньше min_reviewers_count или среди них нет никого не ниже
+// min_reviewer_level, возвращает *NoCandidateError.
+func refillReviewers(tx *sql.Tx, teamName, authorID, prID string, removed []string, actor string) ([]string, error) {
+    settings, err := teamSettings(tx, teamName)
+    if err != nil {
+        return nil, err
+    }
+
+    var picked []string
+    var excluded []models.CandidateExclusion
+    for _, oldUserID := range removed {
+        var candidates []assignment.Candidate
+        candidates, excluded, err = reviewCandidates(tx, teamName, authorID, prID)
+        if err != nil {
+            return nil, err
+        }
+        _, levelCovered, err := replacedReviewer(tx, prID, oldUserID, settings.MinReviewerLevel)
+        if err != nil {
+            return nil, err
+        }
+        skilled, err := replacementSkills(tx, teamName, prID, oldUserID)
+        if err != nil {
+            return nil, err
+        }
+        fallbacks, err := fallbackCandidates(tx, settings.FallbackTeams, authorID, prID)
+        if err != nil {
+            return nil, err
+        }
+
+        team := TeamCandidates{Strategy: teamStrategy(settings), Candidates: candidates}
+        newUserID, fallback, ok := PickReplacement(team, fallbacks, false, levelCovered, settings.MinReviewerLevel, skilled)
+        if !ok {
+            continue
+        }
+        kind := regularReviewer
+        if fallback {
+            kind = fallbackReviewer
+        }
+        if err := assignReviewers(tx, prID, []string{newUserID}, kind, models.ReasonPRReopened, actor); err != nil {
+            return nil, err
+        }
+        picked = append(picked, newUserID)
+    }
+
+    var found int
+    var levelCovered bool
+    err = tx.QueryRow(`
+        SELECT COUNT(*), $2 = '' OR COALESCE(bool_or(u.level = ANY($3)), false)
+        FROM pr_reviewers prr
+        JOIN users u ON u.user_id = prr.reviewer_id
+        WHERE prr.pull_request_id = $1 AND NOT prr.shadow
+    `, prID, settings.MinReviewerLevel, pq.Array(assignment.LevelsFrom(settings.MinReviewerLevel))).Scan(&found, &levelCovered)
+    if err != nil {
+        return nil, err
+    }
+    if found < settings.MinReviewersCount {
+        return nil, &NoCandidateError{Required: settings.MinReviewersCount, Found: found, Excluded: excluded}
+    }
+    if !levelCovered {
+        return nil, &NoCandidateError{Required: 1, Found: found, MinLevel: settings.MinReviewerLevel, Excluded: excluded}
+    }
+    return picked, nil
+}
+
+func (db *DB) ReassignReviewer(prID, oldUserID, actor string) (*models.PullRequest, string, error) {
     tx, err := db.Begin()
     if err != nil {
         return nil, "", err
     }
     defer tx.Rollback()
 
-    var status, authorID string
+    var status, authorID, repository string
     err = tx.QueryRow(`
-        SELECT status, author_id FROM pull_requests WHERE pull_request_id = $1
-    `, prID).Scan(&status, &authorID)
+        SELECT status, author_id, COALESCE(repository, '') FROM pull_requests WHERE pull_request_id = $1
+    `, prID).Scan(&status, &authorID, &repository)
     if err == sql.ErrNoRows {
         return nil, "", ErrNotFound
     }
@@ -344,6 +710,9 @@ func (db *DB) ReassignReviewer(prID, oldUserID string) (*models.PullRequest, str
     if status == "MERGED" {
         return nil, "", ErrPRMerged
     }
+    if status == "CLOSED" {
+        return nil, "", ErrPRClosed
+    }
 
     var isAssigned bool
     err = tx.QueryRow(`
@@ -356,42 +725,25 @@ func (db *DB) ReassignReviewer(prID, oldUserID string) (*models.PullRequest, str
         return nil, "", ErrNotAssigned
     }
 
-    var teamName string
-    err = tx.QueryRow("SELECT team_name FROM users WHERE user_id = $1", authorID).Scan(&teamName)
+    teamName, _, err := reviewTeam(tx, authorID, repository)
     if err != nil {
         return nil, "", err
     }
-
-    var newUserID string
-    err = tx.QueryRow(`
-        SELECT u.user_id FROM users u
-        WHERE u.team_name = $1 
-        AND u.user_id != $2 
-        AND u.is_active = true 
-        AND u.user_id NOT IN (
-            SELECT reviewer_id FROM pr_reviewers WHERE pull_request_id = $3
-        )
-        LIMIT 1
-    `, teamName, authorID, prID).Scan(&newUserID)
-
-    if err == sql.ErrNoRows {
+    if teamName == "" {
         return nil, "", ErrNoCandidate
     }
+
+    newUserID, err := replaceReviewer(tx, teamName, authorID, prID, oldUserID, models.ReasonManualReassign, actor)
     if err != nil {
         return nil, "", err
     }
 
-    _, err = tx.Exec(`
-        UPDATE pr_reviewers 
-        SET reviewer_id = $1 
-        WHERE pull_request_id = $2 AND reviewer_id = $3
-    `, newUserID, prID, oldUserID)
+    pr, err := db.getPullRequest(tx, prID)
     if err != nil {
         return nil, "", err
     }
 
-    pr, err := db.getPullRequest(tx, prID)
-    if err != nil {
+    if err := insertEvents(tx, events.ReviewerReplacedEvent(pr, oldUserID, newUserID)); err != nil {
         return nil, "", err
     }
 
@@ -402,6 +754,179 @@ func (db *DB) ReassignReviewer(prID, oldUserID string) (*models.PullRequest, str
     return pr, newUserID, nil
 }
 
+// replaceReviewer подбирает замену oldUserID по правилам PickReplacement и записывает её в историю.
+// Если без oldUserID метки PR никто не покрывает, предпочитается участник команды с навыком.
+// Новое назначение - новая строка pr_reviewers, поэтому SLA новому ревьюверу отсчитывается заново.
+func replaceReviewer(tx *sql.Tx, teamName, authorID, prID, oldUserID, reason, actor string) (string, error) {
+    settings, err := teamSettings(tx, teamName)
+    if err != nil {
+        return "", err
+    }
+
+    candidates, excluded, err := reviewCandidates(tx, teamName, authorID, prID)
+    if err != nil {
+        return "", err
+    }
+
+    shadow, levelCovered, err := replacedReviewer(tx, prID, oldUserID, settings.MinReviewerLevel)
+    if err != nil {
+        return "", err
+    }
+    skilled, err := replacementSkills(tx, teamName, prID, oldUserID)
+    if err != nil {
+        return "", err
+    }
+
+    fallbacks, err := fallbackCandidates(tx, settings.FallbackTeams, authorID, prID)
+    if err != nil {
+        return "", err
+    }
+
+    team := TeamCandidates{Strategy: teamStrategy(settings), Candidates: candidates}
+    newUserID, fallback, ok := PickReplacement(team, fallbacks, shadow, levelCovered, settings.MinReviewerLevel, skilled)
+    if !ok {
+        return "", &NoCandidateError{Required: 1, Excluded: excluded}
+    }
+
+    err = unassignReviewers(tx, []string{prID}, []string{oldUserID}, []string{newUserID}, reason, actor)
+    if err != nil {
+        return "", err
+    }
+
+    _, err = tx.Exec(`
+        INSERT INTO pr_reviewers (pull_request_id, reviewer_id, fallback, shadow) VALUES ($1, $2, $3, $4)
+    `, prID, newUserID, fallback, shadow)
+    if err != nil {
+        return "", err
+    }
+
+    return newUserID, nil
+}
+
+const teamSettingsColumns = `team_name, assignment_strategy, reviewers_count, min_reviewers_count, required_approvals,
+    max_open_reviews, review_sla_seconds, sla_auto_reassign, min_reviewer_level, shadow_reviewers`
+
+func teamSettings(q queryer, teamName string) (*models.TeamSettings, error) {
+    settings, err := scanTeamSettings(q.QueryRow("SELECT "+teamSettingsColumns+" FROM teams WHERE team_name = $1", teamName))
+    if err != nil {
+        return nil, err
+    }
+    if settings.FallbackTeams, err = fallbackTeams(q, teamName); err != nil {
+        return nil, err
+    }
+    return settings, nil
+}
+
+func scanTeamSettings(row *sql.Row) (*models.TeamSettings, error) {
+    var settings models.TeamSettings
+    err := row.Scan(&settings.TeamName, &settings.AssignmentStrategy, &settings.ReviewersCount, &settings.MinReviewersCount,
+        &settings.RequiredApprovals, &settings.MaxOpenReviews, &settings.ReviewSLASeconds, &settings.SLAAutoReassign,
+        &settings.MinReviewerLevel, &settings.ShadowReviewers)
+    if err != nil {
+        return nil, err
+    }
+    return &settings, nil
+}
+
+func teamStrategy(settings *models.TeamSettings) assignment.Strategy {
+    strategy, err := assignment.Get(settings.AssignmentStrategy)
+    if err != nil {
+        strategy, _ = assignment.Get(assignment.Default)
+    }
+    return strategy
+}
+
+// reviewCandidates возвращает участников команды, которых можно назначить на prID, и причины, по которым не подошли остальные
+func reviewCandidates(tx *sql.Tx, teamName, authorID, prID string) ([]assignment.Candidate, []models.CandidateExclusion, error) {
+    rows, err := tx.Query(`
+        SELECT u.user_id, u.level, u.is_active, u.user_id = $2,
+            EXISTS(SELECT 1 FROM pr_reviewers r WHERE r.pull_request_id = $3 AND r.reviewer_id = u.user_id),
+            EXISTS(
+                SELECT 1 FROM user_availability a
+                WHERE a.user_id = u.user_id AND a.starts_at <= CURRENT_TIMESTAMP AND a.ends_at > CURRENT_TIMESTAMP
+            ),
+            COALESCE(u.max_open_reviews, t.max_open_reviews),
+            COUNT(p.pull_request_id), MAX(prr.assigned_at)
+        FROM users u
+        JOIN teams t ON t.team_name = u.team_name
+        LEFT JOIN pr_reviewers prr ON prr.reviewer_id = u.user_id
+        LEFT JOIN pull_requests p ON p.pull_request_id = prr.pull_request_id AND p.status = 'OPEN'
+        WHERE u.team_name = $1
+        GROUP BY u.user_id, t.team_name
+        ORDER BY u.user_id
+    `, teamName, authorID, prID)
+    if err != nil {
+        return nil, nil, err
+    }
+    defer rows.Close()
+
+    var members []MemberState
+    for rows.Next() {
+        var m MemberState
+        var lastAssignedAt sql.NullTime
+        err := rows.Scan(&m.UserID, &m.Level, &m.IsActive, &m.IsAuthor, &m.Assigned, &m.Away, &m.MaxOpenReviews,
+            &m.OpenReviews, &lastAssignedAt)
+        if err != nil {
+            return nil, nil, err
+        }
+        if lastAssignedAt.Valid {
+            m.LastAssignedAt = lastAssignedAt.Time
+        }
+        members = append(members, m)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, nil, err
+    }
+
+    candidates, excluded := SplitCandidates(members)
+    return candidates, excluded, nil
+}
+
+func (db *DB) SubmitReview(prID, reviewerID, decision string) (*models.PullRequest, error) {
+    tx, err := db.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    var status string
+    err = tx.QueryRow("SELECT status FROM pull_requests WHERE pull_request_id = $1", prID).Scan(&status)
+    if err == sql.ErrNoRows {
+        return nil, ErrNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    if status == "MERGED" {
+        return nil, ErrPRMerged
+    }
+    if status == "CLOSED" {
+        return nil, ErrPRClosed
+    }
+
+    result, err := tx.Exec(`
+        UPDATE pr_reviewers 
+        SET decision = $1, decided_at = CURRENT_TIMESTAMP 
+        WHERE pull_request_id = $2 AND reviewer_id = $3
+    `, decision, prID, reviewerID)
+    if err != nil {
+        return nil, err
+    }
+    if affected, err := result.RowsAffected(); err != nil {
+        return nil, err
+    } else if affected == 0 {
+        return nil, ErrNotAssigned
+    }
+
+    pr, err := db.getPullRequest(tx, prID)
+    if err != nil {
+        return nil, err
+    }
+
+    return pr, tx.Commit()
+}
+
 func (db *DB) GetUserPullRequests(userID string) (*models.UserPRsResponse, error) {
     var exists bool
     err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE user_id = $1)", userID).Scan(&exists)
@@ -420,6 +945,7 @@ func (db *DB) GetUserPullRequests(userID string) (*models.UserPRsResponse, error
         FROM pull_requests pr
         JOIN pr_reviewers prr ON pr.pull_request_id = prr.pull_request_id
         WHERE prr.reviewer_id = $1
+        AND pr.status != 'CLOSED'
     `, userID)
     if err != nil {
         return nil, err
@@ -438,41 +964,85 @@ func (db *DB) GetUserPullRequests(userID string) (*models.UserPRsResponse, error
 }
 
 func (db *DB) getPullRequest(tx *sql.Tx, prID string) (*models.PullRequest, error) {
-    var pr models.PullRequest
-    var createdAt time.Time
-    var mergedAt sql.NullTime
+    prs, err := getPullRequests(tx, []string{prID})
+    if err != nil {
+        return nil, err
+    }
+    if len(prs) == 0 {
+        return nil, sql.ErrNoRows
+    }
+    return prs[0], nil
+}
 
-    err := tx.QueryRow(`
-        SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at
+// getPullRequests загружает PR вместе с ревьюверами двумя запросами, порядок совпадает с prIDs
+func getPullRequests(q queryer, prIDs []string) ([]*models.PullRequest, error) {
+    rows, err := q.Query(`
+        SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at, closed_at, force_merged,
+            COALESCE(merged_by, ''), COALESCE(repository, ''), labels
         FROM pull_requests 
-        WHERE pull_request_id = $1
-    `, prID).Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &createdAt, &mergedAt)
+        WHERE pull_request_id = ANY($1)
+    `, pq.Array(prIDs))
     if err != nil {
         return nil, err
     }
+    defer rows.Close()
 
-    pr.CreatedAt = createdAt
-    if mergedAt.Valid {
-        pr.MergedAt = mergedAt.Time
+    byID := make(map[string]*models.PullRequest, len(prIDs))
+    for rows.Next() {
+        var pr models.PullRequest
+        var mergedAt, closedAt sql.NullTime
+        err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &mergedAt, &closedAt, &pr.ForceMerged, &pr.MergedBy, &pr.Repository, pq.Array(&pr.Labels))
+        if err != nil {
+            return nil, err
+        }
+        if mergedAt.Valid {
+            pr.MergedAt = mergedAt.Time
+        }
+        if closedAt.Valid {
+            pr.ClosedAt = closedAt.Time
+        }
+        byID[pr.PullRequestID] = &pr
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
     }
 
-    rows, err := tx.Query(`
-        SELECT reviewer_id FROM pr_reviewers WHERE pull_request_id = $1
-    `, prID)
+    reviewerRows, err := q.Query(`
+        SELECT pull_request_id, reviewer_id, COALESCE(decision, $2), decided_at, fallback, shadow
+        FROM pr_reviewers 
+        WHERE pull_request_id = ANY($1)
+        ORDER BY assigned_at, reviewer_id
+    `, pq.Array(prIDs), models.ReviewPending)
     if err != nil {
         return nil, err
     }
-    defer rows.Close()
+    defer reviewerRows.Close()
 
-    for rows.Next() {
-        var reviewerID string
-        if err := rows.Scan(&reviewerID); err != nil {
+    for reviewerRows.Next() {
+        var prID string
+        var reviewer models.ReviewerState
+        var decidedAt sql.NullTime
+        if err := reviewerRows.Scan(&prID, &reviewer.UserID, &reviewer.State, &decidedAt, &reviewer.Fallback, &reviewer.Shadow); err != nil {
             return nil, err
         }
-        pr.AssignedReviewers = append(pr.AssignedReviewers, reviewerID)
+        if decidedAt.Valid {
+            reviewer.ReviewedAt = &decidedAt.Time
+        }
+        pr := byID[prID]
+        pr.AssignedReviewers = append(pr.AssignedReviewers, reviewer.UserID)
+        pr.Reviewers = append(pr.Reviewers, reviewer)
+    }
+    if err := reviewerRows.Err(); err != nil {
+        return nil, err
     }
 
-    return &pr, nil
+    prs := make([]*models.PullRequest, 0, len(byID))
+    for _, prID := range prIDs {
+        if pr, ok := byID[prID]; ok {
+            prs = append(prs, pr)
+        }
+    }
+    return prs, nil
 }
 
 func (db *DB) GetSystemStats() (*models.SystemStats, error) {
@@ -503,6 +1073,11 @@ func (db *DB) GetSystemStats() (*models.SystemStats, error) {
         return nil, err
     }
 
+    err = db.QueryRow("SELECT COUNT(*) FROM pull_requests WHERE status = 'CLOSED'").Scan(&stats.TotalClosedPRs)
+    if err != nil {
+        return nil, err
+    }
+
     err = db.QueryRow("SELECT COUNT(*) FROM pr_reviewers").Scan(&stats.TotalReviews)
     if err != nil {
         return nil, err
@@ -549,7 +1124,7 @@ func (db *DB) GetUserStats() ([]models.UserStats, error) {
         SELECT 
             u.user_id,
             u.username,
-            u.team_name,
+            COALESCE(u.team_name, ''),
             u.is_active,
             COUNT(DISTINCT pr_author.pull_request_id) as prs_count,
             COUNT(DISTINCT pr_reviewers.pull_request_id) as reviews_count
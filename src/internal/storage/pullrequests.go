@@ -0,0 +1,124 @@
+package database
+
+import (
+    "database/sql"
+    "encoding/base64"
+    "errors"
+    "pr-reviewer/src/internal/domain/models"
+    "strings"
+    "time"
+)
+
+var ErrInvalidCursor = errors.New("INVALID_CURSOR")
+
+func (db *DB) GetPullRequest(prID string) (*models.PullRequest, error) {
+    prs, err := getPullRequests(db, []string{prID})
+    if err != nil {
+        return nil, err
+    }
+    if len(prs) == 0 {
+        return nil, ErrNotFound
+    }
+    return prs[0], nil
+}
+
+// ListPullRequests возвращает страницу PR от новых к старым; страницы идут по (created_at, pull_request_id)
+func (db *DB) ListPullRequests(filter models.PullRequestFilter) (*models.PullRequestPage, error) {
+    var afterCreatedAt sql.NullTime
+    var afterID string
+    if filter.After != nil {
+        afterCreatedAt = sql.NullTime{Time: filter.After.CreatedAt, Valid: true}
+        afterID = filter.After.PullRequestID
+    }
+
+    rows, err := db.Query(`
+        SELECT pr.pull_request_id
+        FROM pull_requests pr
+        JOIN users u ON u.user_id = pr.author_id
+        WHERE ($1 = '' OR pr.status = $1)
+        AND ($2 = '' OR pr.author_id = $2)
+        AND ($3 = '' OR EXISTS (
+            SELECT 1 FROM pr_reviewers prr
+            WHERE prr.pull_request_id = pr.pull_request_id AND prr.reviewer_id = $3
+        ))
+        AND ($4 = '' OR u.team_name = $4)
+        AND ($5::timestamptz IS NULL OR pr.created_at >= $5::timestamptz)
+        AND ($6::timestamptz IS NULL OR pr.created_at < $6::timestamptz)
+        AND ($7::timestamptz IS NULL OR pr.merged_at >= $7::timestamptz)
+        AND ($8::timestamptz IS NULL OR pr.merged_at < $8::timestamptz)
+        AND ($9::timestamp IS NULL OR (pr.created_at, pr.pull_request_id) < ($9::timestamp, $10))
+        ORDER BY pr.created_at DESC, pr.pull_request_id DESC
+        LIMIT $11
+    `, filter.Status, filter.AuthorID, filter.ReviewerID, filter.TeamName,
+        nullTime(filter.CreatedFrom), nullTime(filter.CreatedTo), nullTime(filter.MergedFrom), nullTime(filter.MergedTo),
+        afterCreatedAt, afterID, filter.Limit+1)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var prIDs []string
+    for rows.Next() {
+        var prID string
+        if err := rows.Scan(&prID); err != nil {
+            return nil, err
+        }
+        prIDs = append(prIDs, prID)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    prs, err := getPullRequests(db, prIDs)
+    if err != nil {
+        return nil, err
+    }
+
+    return NewPullRequestPage(prs, filter.Limit), nil
+}
+
+// NewPullRequestPage отрезает от prs лишний PR, запрошенный сверх limit, и по нему понимает, есть ли следующая страница
+func NewPullRequestPage(prs []*models.PullRequest, limit int) *models.PullRequestPage {
+    page := &models.PullRequestPage{PullRequests: prs}
+    if len(prs) > limit {
+        page.PullRequests = prs[:limit]
+        last := page.PullRequests[limit-1]
+        page.NextCursor = EncodePullRequestCursor(models.PullRequestCursor{
+            CreatedAt:     last.CreatedAt,
+            PullRequestID: last.PullRequestID,
+        })
+    }
+    if page.PullRequests == nil {
+        page.PullRequests = []*models.PullRequest{}
+    }
+    return page
+}
+
+// EncodePullRequestCursor упаковывает позицию в непрозрачную для клиента строку
+func EncodePullRequestCursor(cursor models.PullRequestCursor) string {
+    raw := cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + cursor.PullRequestID
+    return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodePullRequestCursor(s string) (*models.PullRequestCursor, error) {
+    raw, err := base64.RawURLEncoding.DecodeString(s)
+    if err != nil {
+        return nil, ErrInvalidCursor
+    }
+    createdAt, prID, found := strings.Cut(string(raw), "|")
+    if !found || prID == "" {
+        return nil, ErrInvalidCursor
+    }
+    t, err := time.Parse(time.RFC3339Nano, createdAt)
+    if err != nil {
+        return nil, ErrInvalidCursor
+    }
+    return &models.PullRequestCursor{CreatedAt: t, PullRequestID: prID}, nil
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+    if t == nil {
+        return sql.NullTime{}
+    }
+    return sql.NullTime{Time: *t, Valid: true}
+}
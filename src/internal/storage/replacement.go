@@ -0,0 +1,46 @@
+package database
+
+import (
+    "pr-reviewer/src/internal/domain/assignment"
+)
+
+// PickReplacement выбирает замену снимаемому ревьюверу; одна и та же для ручной, SLA и массовой замены.
+// Теневого ревьювера заменяет другой junior команды. Основного - участник команды по её стратегии, предпочтительно
+// из skilled; если без снимаемого не остаётся основного ревьювера не ниже minLevel (levelCovered ложно), предпочитается
+// участник этого уровня, а если такого нет в команде - он берётся из запасных команд. Если в команде вообще никого нет,
+// замена берётся из запасных команд. fallback сообщает, что замена из запасной команды, ok - что она нашлась.
+func PickReplacement(team TeamCandidates, fallbacks []TeamCandidates, shadow, levelCovered bool, minLevel string,
+    skilled map[string]bool) (userID string, fallback, ok bool) {
+    if shadow {
+        picked := PickShadowReviewers(team.Strategy, team.Candidates, nil, 1)
+        if len(picked) == 0 {
+            return "", false, false
+        }
+        return picked[0], false, true
+    }
+
+    isSkilled := func(c assignment.Candidate) bool {
+        return skilled[c.UserID]
+    }
+    picked := assignment.PickPreferring(team.Strategy, team.Candidates, 1, isSkilled)
+    if !levelCovered {
+        atLevel := func(c assignment.Candidate) bool {
+            return assignment.AtLeast(c.Level, minLevel)
+        }
+        picked = assignment.EnsureCovered(team.Strategy, team.Candidates, picked, atLevel, isSkilled)
+        if !anyMatching(team.Candidates, picked, atLevel) {
+            if senior := PickFallback(fallbacks, 1, nil, atLevel); len(senior) > 0 {
+                return senior[0], true, true
+            }
+        }
+    }
+    if len(picked) > 0 {
+        return picked[0], false, true
+    }
+
+    if picked = PickFallback(fallbacks, 1, nil, nil); len(picked) > 0 {
+        return picked[0], true, true
+    }
+    return "", false, false
+}
+
@@ -0,0 +1,148 @@
+package database
+
+import (
+    "database/sql"
+    "pr-reviewer/src/internal/domain/models"
+)
+
+// SetRepository заводит репозиторий или меняет его команду и настройки; ErrNotFound, если команды нет
+func (db *DB) SetRepository(repo models.Repository) (*models.Repository, error) {
+    return scanRepository(db.QueryRow(`
+        INSERT INTO repositories (repository, team_name, reviewers_count, required_approvals)
+        SELECT $1, $2, $3, $4
+        WHERE EXISTS (SELECT 1 FROM teams WHERE team_name = $2)
+        ON CONFLICT (repository)
+        DO UPDATE SET team_name = $2, reviewers_count = $3, required_approvals = $4, updated_at = CURRENT_TIMESTAMP
+        RETURNING `+repositoryColumns,
+        repo.Repository, repo.TeamName, nullInt(repo.ReviewersCount), nullInt(repo.RequiredApprovals)))
+}
+
+func (db *DB) GetRepository(repository string) (*models.Repository, error) {
+    return getRepository(db, repository)
+}
+
+// ListRepositories возвращает репозитории команды teamName, для пустого teamName - все
+func (db *DB) ListRepositories(teamName string) ([]models.Repository, error) {
+    rows, err := db.Query(`
+        SELECT `+repositoryColumns+`
+        FROM repositories
+        WHERE $1 = '' OR team_name = $1
+        ORDER BY repository
+    `, teamName)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    repos := []models.Repository{}
+    for rows.Next() {
+        repo, err := scanRepository(rows)
+        if err != nil {
+            return nil, err
+        }
+        repos = append(repos, *repo)
+    }
+    return repos, rows.Err()
+}
+
+// DeleteRepository удаляет репозиторий; его PR снова ревьюит команда автора
+func (db *DB) DeleteRepository(repository string) error {
+    result, err := db.Exec("DELETE FROM repositories WHERE repository = $1", repository)
+    if err != nil {
+        return err
+    }
+    deleted, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+    if deleted == 0 {
+        return ErrNotFound
+    }
+    return nil
+}
+
+// RepositorySettings накладывает настройки репозитория на настройки команды-владельца.
+// Если репозиторий уменьшил число ревьюверов, минимум не может быть больше него.
+func RepositorySettings(settings models.TeamSettings, repo *models.Repository) models.TeamSettings {
+    if repo == nil {
+        return settings
+    }
+    if repo.ReviewersCount != nil {
+        settings.ReviewersCount = *repo.ReviewersCount
+        settings.MinReviewersCount = min(settings.MinReviewersCount, settings.ReviewersCount)
+    }
+    if repo.RequiredApprovals != nil {
+        settings.RequiredApprovals = *repo.RequiredApprovals
+    }
+    return settings
+}
+
+// reviewTeam выбирает команду, которая ревьюит PR автора authorID в repository: для заведённого
+// репозитория это команда-владелец, иначе - команда автора. Пустая команда - ревьюверов брать неоткуда.
+func reviewTeam(q queryer, authorID, repository string) (string, *models.Repository, error) {
+    if repository != "" {
+        repo, err := getRepository(q, repository)
+        if err == nil {
+            return repo.TeamName, repo, nil
+        }
+        if err != ErrNotFound {
+            return "", nil, err
+        }
+    }
+
+    var teamName string
+    err := q.QueryRow("SELECT COALESCE(team_name, '') FROM users WHERE user_id = $1", authorID).Scan(&teamName)
+    if err == sql.ErrNoRows {
+        return "", nil, ErrNotFound
+    }
+    if err != nil {
+        return "", nil, err
+    }
+    return teamName, nil, nil
+}
+
+// reviewSettings - настройки команды teamName с учётом настроек репозитория
+func reviewSettings(q queryer, teamName string, repo *models.Repository) (*models.TeamSettings, error) {
+    settings, err := teamSettings(q, teamName)
+    if err != nil {
+        return nil, err
+    }
+    result := RepositorySettings(*settings, repo)
+    return &result, nil
+}
+
+const repositoryColumns = "repository, team_name, reviewers_count, required_approvals, updated_at"
+
+func getRepository(q queryer, repository string) (*models.Repository, error) {
+    return scanRepository(q.QueryRow("SELECT "+repositoryColumns+" FROM repositories WHERE repository = $1", repository))
+}
+
+func scanRepository(row rowScanner) (*models.Repository, error) {
+    var repo models.Repository
+    var reviewersCount, requiredApprovals sql.NullInt64
+    err := row.Scan(&repo.Repository, &repo.TeamName, &reviewersCount, &requiredApprovals, &repo.UpdatedAt)
+    if err == sql.ErrNoRows {
+        return nil, ErrNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+    repo.ReviewersCount = intPtr(reviewersCount)
+    repo.RequiredApprovals = intPtr(requiredApprovals)
+    return &repo, nil
+}
+
+func nullInt(value *int) sql.NullInt64 {
+    if value == nil {
+        return sql.NullInt64{}
+    }
+    return sql.NullInt64{Int64: int64(*value), Valid: true}
+}
+
+func intPtr(value sql.NullInt64) *int {
+    if !value.Valid {
+        return nil
+    }
+    result := int(value.Int64)
+    return &result
+}
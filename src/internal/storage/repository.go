@@ -0,0 +1,147 @@
+package database
+
+import (
+    "pr-reviewer/src/internal/domain/events"
+    "pr-reviewer/src/internal/domain/models"
+    "time"
+)
+
+type TeamRepository interface {
+    CreateTeam(team models.Team) error
+    GetTeam(teamName string) (*models.Team, error)
+    GetTeamSettings(teamName string) (*models.TeamSettings, error)
+    UpdateTeamSettings(settings models.TeamSettings) (*models.TeamSettings, error)
+    AddTeamMembers(teamName string, members []models.TeamMember, allowMove bool, actor string) (*models.TeamMembershipResponse, error)
+    RemoveTeamMembers(teamName string, userIDs []string, actor string) (*models.TeamMembershipResponse, error)
+    RenameTeam(teamName, newTeamName string) error
+    DeleteTeam(teamName string, removeMembers bool, actor string) (*models.TeamMembershipResponse, error)
+    DeactivateTeamUsers(teamName string, userIDs []string, actor string) (*models.DeactivateUsersResponse, error)
+}
+
+type UserRepository interface {
+    SetUserActive(userID string, isActive bool) (*models.User, error)
+    // SetUserMaxOpenReviews задаёт личное ограничение открытых ревью, nil возвращает ограничение команды
+    SetUserMaxOpenReviews(userID string, maxOpenReviews *int) (*models.User, error)
+    SetUserLevel(userID, level string) (*models.User, error)
+    GetUserPullRequests(userID string) (*models.UserPRsResponse, error)
+    SetUserSkills(userID string, skills []string) (*models.UserSkills, error)
+    GetUserSkills(userID string) (*models.UserSkills, error)
+}
+
+type PullRequestRepository interface {
+    // actor - кто инициировал изменение, попадает в историю назначений
+    CreatePullRequest(pr models.CreatePRRequest, actor string) (*models.PullRequest, error)
+    MergePullRequest(prID string, force bool, actor string) (*models.PullRequest, error)
+    // MergeExternally фиксирует мерж, уже сделанный вне сервиса: OPEN или CLOSED PR сразу становится MERGED
+    MergeExternally(prID, actor string) (*models.PullRequest, error)
+    ClosePullRequest(prID string) (*models.PullRequest, error)
+    ReopenPullRequest(prID, actor string) (*models.PullRequest, error)
+    ReassignReviewer(prID, oldUserID, actor string) (*models.PullRequest, string, error)
+    SubmitReview(prID, reviewerID, decision string) (*models.PullRequest, error)
+    GetPullRequestHistory(prID string) (*models.PullRequestHistory, error)
+    GetPullRequest(prID string) (*models.PullRequest, error)
+    ListPullRequests(filter models.PullRequestFilter) (*models.PullRequestPage, error)
+}
+
+type GitHubRepository interface {
+    SetGitHubLogin(userID, login string) (*models.GitHubLogin, error)
+    RemoveGitHubLogin(login string) error
+    GetUserIDByGitHubLogin(login string) (string, error)
+}
+
+// ReviewSLARepository находит ревью, просроченные по SLA команды
+type ReviewSLARepository interface {
+    EscalateOverdueReviews(limit int) ([]models.ReviewEscalation, error)
+}
+
+// AvailabilityRepository хранит периоды отсутствия, в которые пользователь не назначается ревьювером
+type AvailabilityRepository interface {
+    CreateAvailability(period models.AvailabilityPeriod) (*models.AvailabilityPeriod, error)
+    GetAvailability(availabilityID int64) (*models.AvailabilityPeriod, error)
+    ListAvailability(userID string, includePast bool) ([]models.AvailabilityPeriod, error)
+    UpdateAvailability(period models.AvailabilityPeriod) (*models.AvailabilityPeriod, error)
+    DeleteAvailability(availabilityID int64) error
+    // StartDueAvailability переназначает открытые ревью пользователей, у которых начался период с reassign_reviews
+    StartDueAvailability() ([]models.AvailabilityReassignment, error)
+}
+
+type WebhookRepository interface {
+    CreateWebhookSubscription(sub models.WebhookSubscription) (*models.WebhookSubscription, error)
+    GetWebhookSubscription(subscriptionID string) (*models.WebhookSubscription, error)
+    ListWebhookSubscriptions() ([]models.WebhookSubscription, error)
+    UpdateWebhookSubscription(sub models.WebhookSubscription) (*models.WebhookSubscription, error)
+    DeleteWebhookSubscription(subscriptionID string) error
+    // EnqueueWebhookEvent создаёт доставку события для каждой активной подписки на его тип
+    EnqueueWebhookEvent(event events.Event) (int, error)
+    // ClaimWebhookDeliveries забирает готовые к отправке доставки и откладывает их повтор на lease
+    ClaimWebhookDeliveries(lease time.Duration, limit int) ([]models.WebhookDelivery, error)
+    RecordWebhookAttempt(deliveryID int64, attempt DeliveryAttempt) error
+    ListWebhookDeliveries(filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error)
+    ReplayWebhookDelivery(deliveryID int64) (*models.WebhookDelivery, error)
+}
+
+// OutboxRepository отдаёт события, записанные вместе с изменениями PR, фоновой отправке
+type OutboxRepository interface {
+    ClaimOutboxEvents(lease time.Duration, limit int) ([]OutboxEntry, error)
+    MarkOutboxDispatched(id int64) error
+    MarkOutboxFailed(id int64, errMsg string, retryIn time.Duration) error
+    PurgeOutbox(olderThan time.Duration) (int, error)
+}
+
+type StatsRepository interface {
+    GetSystemStats() (*models.SystemStats, error)
+    GetTopReviewers(limit int) ([]models.TopReviewer, error)
+    GetUserStats() ([]models.UserStats, error)
+    GetPRStats() ([]models.PRStats, error)
+}
+
+// TokenRepository хранит токены API; секреты передаются и ищутся только в виде хэша
+type TokenRepository interface {
+    CreateAPIToken(token models.APIToken, tokenHash string) (*models.APIToken, error)
+    GetAPITokenByHash(tokenHash string) (*models.APIToken, error)
+    ListAPITokens() ([]models.APIToken, error)
+    RevokeAPIToken(tokenID string) (*models.APIToken, error)
+}
+
+// AccessRepository хранит руководителей команд и отдаёт команды пользователя для проверки прав
+type AccessRepository interface {
+    AddTeamLead(teamName, userID string) (*models.TeamLeadsResponse, error)
+    RemoveTeamLead(teamName, userID string) (*models.TeamLeadsResponse, error)
+    ListTeamLeads(teamName string) (*models.TeamLeadsResponse, error)
+    GetUserAccess(userID string) (*models.UserAccess, error)
+}
+
+// CodeOwnersRepository хранит файлы CODEOWNERS, загруженные через API, по репозиториям
+type CodeOwnersRepository interface {
+    SetCodeOwners(repository, content string) (*models.CodeOwnersFile, error)
+    GetCodeOwners(repository string) (*models.CodeOwnersFile, error)
+    DeleteCodeOwners(repository string) error
+}
+
+// RepoRepository хранит репозитории и команды, которые ревьюят PR в них
+type RepoRepository interface {
+    SetRepository(repo models.Repository) (*models.Repository, error)
+    GetRepository(repository string) (*models.Repository, error)
+    ListRepositories(teamName string) ([]models.Repository, error)
+    DeleteRepository(repository string) error
+}
+
+// Repository объединяет все хранилища сервиса; реализуется DB и memory.Store
+type Repository interface {
+    TeamRepository
+    UserRepository
+    PullRequestRepository
+    StatsRepository
+    GitHubRepository
+    ReviewSLARepository
+    AvailabilityRepository
+    WebhookRepository
+    OutboxRepository
+    TokenRepository
+    AccessRepository
+    CodeOwnersRepository
+    RepoRepository
+    Close() error
+}
+
+var _ Repository = (*DB)(nil)
@@ -0,0 +1,106 @@
+package database
+
+import (
+    "database/sql"
+    "pr-reviewer/src/internal/domain/assignment"
+    "pr-reviewer/src/internal/domain/models"
+    "slices"
+
+    "github.com/lib/pq"
+)
+
+// EnsureLevel следит, чтобы среди основных ревьюверов own и запасных fallback хотя бы один был не ниже minLevel.
+// Подходящий сначала ищется в команде на место последнего из own, кого не нужно сохранять по keep, затем в запасных
+// командах: на свободное из count место, а без него - вместо последнего запасного или основного ревьювера.
+// Если подходящих нет нигде, возвращает *NoCandidateError, где Found - сколько ревьюверов ниже minLevel нашлось.
+func EnsureLevel(team TeamCandidates, fallbacks []TeamCandidates, own, fallback []string, count int, minLevel string,
+    keep func(assignment.Candidate) bool, excluded []models.CandidateExclusion) ([]string, []string, error) {
+    if minLevel == "" || count <= 0 {
+        return own, fallback, nil
+    }
+
+    atLevel := func(c assignment.Candidate) bool {
+        return assignment.AtLeast(c.Level, minLevel)
+    }
+    all := slices.Clone(team.Candidates)
+    for _, pool := range fallbacks {
+        all = append(all, pool.Candidates...)
+    }
+    if anyMatching(all, own, atLevel) || anyMatching(all, fallback, atLevel) {
+        return own, fallback, nil
+    }
+
+    own = assignment.EnsureCovered(team.Strategy, team.Candidates, own, atLevel, keep)
+    if anyMatching(team.Candidates, own, atLevel) {
+        return own, fallback, nil
+    }
+
+    picked := PickFallback(fallbacks, 1, fallback, atLevel)
+    if len(picked) == 0 {
+        return nil, nil, &NoCandidateError{Required: 1, Found: len(own) + len(fallback), MinLevel: minLevel, Excluded: excluded}
+    }
+    switch {
+    case len(own)+len(fallback) < count:
+        fallback = append(slices.Clip(fallback), picked[0])
+    case len(fallback) > 0:
+        fallback = append(slices.Clone(fallback[:len(fallback)-1]), picked[0])
+    default:
+        own = dropLast(team.Candidates, own, keep)
+        fallback = picked
+    }
+    return own, fallback, nil
+}
+
+// anyMatching проверяет, есть ли среди userIDs кандидат, для которого match истинно
+func anyMatching(candidates []assignment.Candidate, userIDs []string, match func(assignment.Candidate) bool) bool {
+    for _, candidate := range candidates {
+        if match(candidate) && slices.Contains(userIDs, candidate.UserID) {
+            return true
+        }
+    }
+    return false
+}
+
+// dropLast убирает из picked последнего, кого не нужно сохранять по keep, а если сохранить нужно всех - просто последнего
+func dropLast(candidates []assignment.Candidate, picked []string, keep func(assignment.Candidate) bool) []string {
+    dropped := len(picked) - 1
+    for i := len(picked) - 1; i >= 0; i-- {
+        index := slices.IndexFunc(candidates, func(c assignment.Candidate) bool { return c.UserID == picked[i] })
+        if index < 0 || keep == nil || !keep(candidates[index]) {
+            dropped = i
+            break
+        }
+    }
+    return slices.Delete(slices.Clone(picked), dropped, dropped+1)
+}
+
+// PickShadowReviewers выбирает по стратегии до count junior из кандидатов, не попавших в picked
+func PickShadowReviewers(strategy assignment.Strategy, candidates []assignment.Candidate, picked []string, count int) []string {
+    if count <= 0 {
+        return nil
+    }
+
+    var juniors []assignment.Candidate
+    for _, candidate := range candidates {
+        if candidate.Level == assignment.Junior && !slices.Contains(picked, candidate.UserID) {
+            juniors = append(juniors, candidate)
+        }
+    }
+    return strategy.Pick(juniors, count)
+}
+
+// replacedReviewer сообщает, теневой ли oldUserID на prID и остаётся ли без него среди основных ревьюверов
+// кто-то не ниже minLevel; oldUserID может быть уже снят с PR
+func replacedReviewer(tx *sql.Tx, prID, oldUserID, minLevel string) (shadow, levelCovered bool, err error) {
+    err = tx.QueryRow(`
+        SELECT COALESCE((
+            SELECT shadow FROM pr_reviewers WHERE pull_request_id = $1 AND reviewer_id = $2
+        ), false), $3 = '' OR EXISTS (
+            SELECT 1 FROM pr_reviewers other
+            JOIN users u ON u.user_id = other.reviewer_id
+            WHERE other.pull_request_id = $1 AND other.reviewer_id <> $2
+            AND NOT other.shadow AND u.level = ANY($4)
+        )
+    `, prID, oldUserID, minLevel, pq.Array(assignment.LevelsFrom(minLevel))).Scan(&shadow, &levelCovered)
+    return shadow, levelCovered, err
+}
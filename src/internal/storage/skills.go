@@ -0,0 +1,147 @@
+package database
+
+import (
+    "database/sql"
+    "pr-reviewer/src/internal/domain/models"
+    "sort"
+    "strings"
+
+    "github.com/lib/pq"
+)
+
+// SetUserSkills заменяет навыки пользователя целиком; навыки уже приведены NormalizeTags
+func (db *DB) SetUserSkills(userID string, skills []string) (*models.UserSkills, error) {
+    tx, err := db.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    var exists bool
+    if err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE user_id = $1)", userID).Scan(&exists); err != nil {
+        return nil, err
+    }
+    if !exists {
+        return nil, ErrNotFound
+    }
+
+    if _, err := tx.Exec("DELETE FROM user_skills WHERE user_id = $1", userID); err != nil {
+        return nil, err
+    }
+    _, err = tx.Exec(`
+        INSERT INTO user_skills (user_id, skill)
+        SELECT $1, unnest($2::text[])
+    `, userID, pq.Array(skills))
+    if err != nil {
+        return nil, err
+    }
+
+    result, err := userSkills(tx, userID)
+    if err != nil {
+        return nil, err
+    }
+    return result, tx.Commit()
+}
+
+func (db *DB) GetUserSkills(userID string) (*models.UserSkills, error) {
+    var exists bool
+    if err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE user_id = $1)", userID).Scan(&exists); err != nil {
+        return nil, err
+    }
+    if !exists {
+        return nil, ErrNotFound
+    }
+    return userSkills(db, userID)
+}
+
+// NormalizeTags приводит навыки и метки к нижнему регистру без пробелов по краям, убирает пустые и повторы
+func NormalizeTags(tags []string) []string {
+    seen := make(map[string]bool, len(tags))
+    result := []string{}
+    for _, tag := range tags {
+        tag = strings.ToLower(strings.TrimSpace(tag))
+        if tag != "" && !seen[tag] {
+            seen[tag] = true
+            result = append(result, tag)
+        }
+    }
+    sort.Strings(result)
+    return result
+}
+
+func userSkills(q queryer, userID string) (*models.UserSkills, error) {
+    rows, err := q.Query("SELECT skill FROM user_skills WHERE user_id = $1 ORDER BY skill", userID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    result := &models.UserSkills{UserID: userID, Skills: []string{}}
+    for rows.Next() {
+        var skill string
+        if err := rows.Scan(&skill); err != nil {
+            return nil, err
+        }
+        result.Skills = append(result.Skills, skill)
+    }
+    return result, rows.Err()
+}
+
+// skilledMembers отбирает участников команды, навыки которых пересекаются с метками PR
+func skilledMembers(tx *sql.Tx, teamName string, labels []string) (map[string]bool, error) {
+    result := make(map[string]bool)
+    if len(labels) == 0 {
+        return result, nil
+    }
+
+    rows, err := tx.Query(`
+        SELECT DISTINCT s.user_id
+        FROM user_skills s
+        JOIN users u ON u.user_id = s.user_id
+        WHERE u.team_name = $1 AND s.skill = ANY($2)
+    `, teamName, pq.Array(labels))
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    for rows.Next() {
+        var userID string
+        if err := rows.Scan(&userID); err != nil {
+            return nil, err
+        }
+        result[userID] = true
+    }
+    return result, rows.Err()
+}
+
+// replacementSkills отбирает участников команды с навыками из меток PR prID, но только если без oldUserID
+// среди ревьюверов PR никто эти метки не покрывает; иначе замене навыки не нужны
+func replacementSkills(tx *sql.Tx, teamName, prID, oldUserID string) (map[string]bool, error) {
+    rows, err := tx.Query(`
+        SELECT DISTINCT s.user_id
+        FROM pull_requests pr
+        JOIN user_skills s ON s.skill = ANY(pr.labels)
+        JOIN users u ON u.user_id = s.user_id
+        WHERE pr.pull_request_id = $2 AND u.team_name = $1
+        AND NOT EXISTS (
+            SELECT 1 FROM pr_reviewers prr
+            JOIN user_skills rs ON rs.user_id = prr.reviewer_id
+            WHERE prr.pull_request_id = $2 AND prr.reviewer_id <> $3 AND rs.skill = ANY(pr.labels)
+        )
+    `, teamName, prID, oldUserID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    result := make(map[string]bool)
+    for rows.Next() {
+        var userID string
+        if err := rows.Scan(&userID); err != nil {
+            return nil, err
+        }
+        result[userID] = true
+    }
+    return result, rows.Err()
+}
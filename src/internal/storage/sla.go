@@ -0,0 +1,104 @@
+package database
+
+import (
+    "errors"
+    "pr-reviewer/src/internal/domain/events"
+    "pr-reviewer/src/internal/domain/models"
+)
+
+type overdueReview struct {
+    escalation models.ReviewEscalation
+    authorID   string
+    teamName   string
+    reassign   bool
+}
+
+// EscalateOverdueReviews отмечает ревью, по которым ревьювер не принял решение за SLA команды,
+// пишет review.escalated и, если команда этого хочет, переназначает ревью как ReassignReviewer.
+// Каждое ревью эскалируется один раз; новому ревьюверу SLA отсчитывается заново.
+func (db *DB) EscalateOverdueReviews(limit int) ([]models.ReviewEscalation, error) {
+    tx, err := db.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    rows, err := tx.Query(`
+        SELECT prr.pull_request_id, prr.reviewer_id, prr.assigned_at, t.review_sla_seconds,
+            pr.author_id, t.team_name, t.sla_auto_reassign
+        FROM pr_reviewers prr
+        JOIN pull_requests pr ON pr.pull_request_id = prr.pull_request_id
+        JOIN users u ON u.user_id = pr.author_id
+        LEFT JOIN repositories repo ON repo.repository = pr.repository
+        JOIN teams t ON t.team_name = COALESCE(repo.team_name, u.team_name)
+        WHERE pr.status = 'OPEN'
+        AND prr.decision IS NULL
+        AND prr.escalated_at IS NULL
+        AND t.review_sla_seconds > 0
+        AND prr.assigned_at + t.review_sla_seconds * INTERVAL '1 second' <= CURRENT_TIMESTAMP
+        ORDER BY prr.assigned_at, prr.pull_request_id, prr.reviewer_id
+        LIMIT $1
+        FOR UPDATE OF prr SKIP LOCKED
+    `, limit)
+    if err != nil {
+        return nil, err
+    }
+
+    var overdue []overdueReview
+    for rows.Next() {
+        var r overdueReview
+        err := rows.Scan(&r.escalation.PullRequestID, &r.escalation.ReviewerID, &r.escalation.AssignedAt,
+            &r.escalation.SLASeconds, &r.authorID, &r.teamName, &r.reassign)
+        if err != nil {
+            rows.Close()
+            return nil, err
+        }
+        overdue = append(overdue, r)
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    escalations := []models.ReviewEscalation{}
+    for _, r := range overdue {
+        escalation := r.escalation
+        _, err := tx.Exec(`
+            UPDATE pr_reviewers SET escalated_at = CURRENT_TIMESTAMP
+            WHERE pull_request_id = $1 AND reviewer_id = $2
+        `, escalation.PullRequestID, escalation.ReviewerID)
+        if err != nil {
+            return nil, err
+        }
+
+        if r.reassign {
+            newUserID, err := replaceReviewer(tx, r.teamName, r.authorID, escalation.PullRequestID, escalation.ReviewerID,
+                models.ReasonSLABreached, models.ActorSystem)
+            if err != nil && !errors.Is(err, ErrNoCandidate) {
+                return nil, err
+            }
+            escalation.ReplacedBy = newUserID
+        }
+
+        pr, err := db.getPullRequest(tx, escalation.PullRequestID)
+        if err != nil {
+            return nil, err
+        }
+
+        evts := []events.Event{events.ReviewEscalatedEvent(pr, escalation)}
+        if escalation.ReplacedBy != "" {
+            evts = append(evts, events.ReviewerReplacedEvent(pr, escalation.ReviewerID, escalation.ReplacedBy))
+        }
+        if err := insertEvents(tx, evts...); err != nil {
+            return nil, err
+        }
+
+        escalations = append(escalations, escalation)
+    }
+
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+
+    return escalations, nil
+}
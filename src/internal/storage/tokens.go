@@ -0,0 +1,72 @@
+package database
+
+import (
+    "database/sql"
+    "pr-reviewer/src/internal/domain/models"
+)
+
+// CreateAPIToken сохраняет токен с хэшем секрета; для токена пользователя ErrNotFound, если пользователя нет
+func (db *DB) CreateAPIToken(token models.APIToken, tokenHash string) (*models.APIToken, error) {
+    return scanAPIToken(db.QueryRow(`
+        INSERT INTO api_tokens (token_id, name, token_hash, scope, user_id)
+        SELECT $1, $2, $3, $4, NULLIF($5, '')
+        WHERE $5 = '' OR EXISTS (SELECT 1 FROM users WHERE user_id = $5)
+        RETURNING token_id, name, scope, COALESCE(user_id, ''), created_at, revoked_at
+    `, token.TokenID, token.Name, tokenHash, token.Scope, token.UserID))
+}
+
+// GetAPITokenByHash находит действующий токен, отозванные не находятся
+func (db *DB) GetAPITokenByHash(tokenHash string) (*models.APIToken, error) {
+    return scanAPIToken(db.QueryRow(`
+        SELECT token_id, name, scope, COALESCE(user_id, ''), created_at, revoked_at
+        FROM api_tokens
+        WHERE token_hash = $1 AND revoked_at IS NULL
+    `, tokenHash))
+}
+
+func (db *DB) ListAPITokens() ([]models.APIToken, error) {
+    rows, err := db.Query(`
+        SELECT token_id, name, scope, COALESCE(user_id, ''), created_at, revoked_at
+        FROM api_tokens
+        ORDER BY created_at, token_id
+    `)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    tokens := []models.APIToken{}
+    for rows.Next() {
+        token, err := scanAPIToken(rows)
+        if err != nil {
+            return nil, err
+        }
+        tokens = append(tokens, *token)
+    }
+    return tokens, rows.Err()
+}
+
+// RevokeAPIToken отзывает токен; повторный отзыв не меняет время первого
+func (db *DB) RevokeAPIToken(tokenID string) (*models.APIToken, error) {
+    return scanAPIToken(db.QueryRow(`
+        UPDATE api_tokens SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP)
+        WHERE token_id = $1
+        RETURNING token_id, name, scope, COALESCE(user_id, ''), created_at, revoked_at
+    `, tokenID))
+}
+
+func scanAPIToken(row rowScanner) (*models.APIToken, error) {
+    var token models.APIToken
+    var revokedAt sql.NullTime
+    err := row.Scan(&token.TokenID, &token.Name, &token.Scope, &token.UserID, &token.CreatedAt, &revokedAt)
+    if err == sql.ErrNoRows {
+        return nil, ErrNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+    if revokedAt.Valid {
+        token.RevokedAt = &revokedAt.Time
+    }
+    return &token, nil
+}
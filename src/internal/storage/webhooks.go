@@ -0,0 +1,246 @@
+package database
+
+import (
+    "database/sql"
+    "encoding/json"
+    "pr-reviewer/src/internal/domain/events"
+    "pr-reviewer/src/internal/domain/models"
+    "time"
+
+    "github.com/lib/pq"
+)
+
+// DeliveryAttempt - результат одной попытки доставки вебхука
+type DeliveryAttempt struct {
+    // Status - DELIVERED, PENDING (повтор через RetryIn) или DEAD
+    Status     string
+    StatusCode int
+    Error      string
+    RetryIn    time.Duration
+}
+
+type rowScanner interface {
+    Scan(dest ...interface{}) error
+}
+
+const webhookSubscriptionColumns = "subscription_id, url, secret, events, is_active, created_at"
+
+const webhookDeliveryColumns = `delivery_id, subscription_id, event_id, event_type, payload, status, attempts,
+    last_status_code, last_error, next_attempt_at, created_at, delivered_at`
+
+func (db *DB) CreateWebhookSubscription(sub models.WebhookSubscription) (*models.WebhookSubscription, error) {
+    return scanWebhookSubscription(db.QueryRow(`
+        INSERT INTO webhook_subscriptions (subscription_id, url, secret, events, is_active)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING `+webhookSubscriptionColumns,
+        sub.SubscriptionID, sub.URL, sub.Secret, pq.Array(sub.Events), sub.IsActive))
+}
+
+func (db *DB) GetWebhookSubscription(subscriptionID string) (*models.WebhookSubscription, error) {
+    return scanWebhookSubscription(db.QueryRow(
+        "SELECT "+webhookSubscriptionColumns+" FROM webhook_subscriptions WHERE subscription_id = $1", subscriptionID))
+}
+
+func (db *DB) ListWebhookSubscriptions() ([]models.WebhookSubscription, error) {
+    rows, err := db.Query("SELECT " + webhookSubscriptionColumns + " FROM webhook_subscriptions ORDER BY created_at, subscription_id")
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    subs := []models.WebhookSubscription{}
+    for rows.Next() {
+        sub, err := scanWebhookSubscription(rows)
+        if err != nil {
+            return nil, err
+        }
+        subs = append(subs, *sub)
+    }
+
+    return subs, rows.Err()
+}
+
+func (db *DB) UpdateWebhookSubscription(sub models.WebhookSubscription) (*models.WebhookSubscription, error) {
+    return scanWebhookSubscription(db.QueryRow(`
+        UPDATE webhook_subscriptions
+        SET url = $2, secret = $3, events = $4, is_active = $5
+        WHERE subscription_id = $1
+        RETURNING `+webhookSubscriptionColumns,
+        sub.SubscriptionID, sub.URL, sub.Secret, pq.Array(sub.Events), sub.IsActive))
+}
+
+func (db *DB) DeleteWebhookSubscription(subscriptionID string) error {
+    result, err := db.Exec("DELETE FROM webhook_subscriptions WHERE subscription_id = $1", subscriptionID)
+    if err != nil {
+        return err
+    }
+
+    affected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+    if affected == 0 {
+        return ErrNotFound
+    }
+
+    return nil
+}
+
+func (db *DB) EnqueueWebhookEvent(event events.Event) (int, error) {
+    payload, err := json.Marshal(event)
+    if err != nil {
+        return 0, err
+    }
+
+    result, err := db.Exec(`
+        INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, next_attempt_at)
+        SELECT subscription_id, $1, $2, $3, CURRENT_TIMESTAMP
+        FROM webhook_subscriptions
+        WHERE is_active AND (cardinality(events) = 0 OR $2 = ANY(events))
+        ON CONFLICT (subscription_id, event_id) DO NOTHING
+    `, event.ID, event.Type, payload)
+    if err != nil {
+        return 0, err
+    }
+
+    affected, err := result.RowsAffected()
+    return int(affected), err
+}
+
+func (db *DB) ClaimWebhookDeliveries(lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+    rows, err := db.Query(`
+        UPDATE webhook_deliveries
+        SET next_attempt_at = CURRENT_TIMESTAMP + $1::bigint * INTERVAL '1 millisecond'
+        WHERE delivery_id IN (
+            SELECT wd.delivery_id
+            FROM webhook_deliveries wd
+            JOIN webhook_subscriptions ws ON ws.subscription_id = wd.subscription_id
+            WHERE wd.status = 'PENDING' AND ws.is_active AND wd.next_attempt_at <= CURRENT_TIMESTAMP
+            ORDER BY wd.next_attempt_at, wd.delivery_id
+            LIMIT $2
+            FOR UPDATE OF wd SKIP LOCKED
+        )
+        RETURNING `+webhookDeliveryColumns, lease.Milliseconds(), limit)
+    if err != nil {
+        return nil, err
+    }
+
+    return scanWebhookDeliveries(rows)
+}
+
+func (db *DB) RecordWebhookAttempt(deliveryID int64, attempt DeliveryAttempt) error {
+    _, err := db.Exec(`
+        UPDATE webhook_deliveries
+        SET status = $2,
+            attempts = attempts + 1,
+            last_status_code = $3,
+            last_error = $4,
+            next_attempt_at = CASE WHEN $2::text = 'PENDING' THEN CURRENT_TIMESTAMP + $5::bigint * INTERVAL '1 millisecond' END,
+            delivered_at = CASE WHEN $2::text = 'DELIVERED' THEN CURRENT_TIMESTAMP END
+        WHERE delivery_id = $1
+    `, deliveryID, attempt.Status,
+        sql.NullInt64{Int64: int64(attempt.StatusCode), Valid: attempt.StatusCode != 0},
+        sql.NullString{String: attempt.Error, Valid: attempt.Error != ""},
+        attempt.RetryIn.Milliseconds())
+    return err
+}
+
+func (db *DB) ListWebhookDeliveries(filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+    rows, err := db.Query(`
+        SELECT `+webhookDeliveryColumns+`
+        FROM webhook_deliveries
+        WHERE ($1 = '' OR subscription_id = $1) AND ($2 = '' OR status = $2)
+        ORDER BY delivery_id DESC
+        LIMIT $3
+    `, filter.SubscriptionID, filter.Status, filter.Limit)
+    if err != nil {
+        return nil, err
+    }
+
+    return scanWebhookDeliveries(rows)
+}
+
+func (db *DB) ReplayWebhookDelivery(deliveryID int64) (*models.WebhookDelivery, error) {
+    delivery, err := scanWebhookDelivery(db.QueryRow(`
+        UPDATE webhook_deliveries
+        SET status = 'PENDING', attempts = 0, next_attempt_at = CURRENT_TIMESTAMP
+        WHERE delivery_id = $1 AND status = 'DEAD'
+        RETURNING `+webhookDeliveryColumns, deliveryID))
+    if err != ErrNotFound {
+        return delivery, err
+    }
+
+    var exists bool
+    err = db.QueryRow("SELECT EXISTS(SELECT 1 FROM webhook_deliveries WHERE delivery_id = $1)", deliveryID).Scan(&exists)
+    if err != nil {
+        return nil, err
+    }
+    if exists {
+        return nil, ErrNotDeadLetter
+    }
+
+    return nil, ErrNotFound
+}
+
+func scanWebhookSubscription(row rowScanner) (*models.WebhookSubscription, error) {
+    var sub models.WebhookSubscription
+    var eventTypes []string
+    err := row.Scan(&sub.SubscriptionID, &sub.URL, &sub.Secret, pq.Array(&eventTypes), &sub.IsActive, &sub.CreatedAt)
+    if err == sql.ErrNoRows {
+        return nil, ErrNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    sub.Events = eventTypes
+    if sub.Events == nil {
+        sub.Events = []string{}
+    }
+
+    return &sub, nil
+}
+
+func scanWebhookDeliveries(rows *sql.Rows) ([]models.WebhookDelivery, error) {
+    defer rows.Close()
+
+    deliveries := []models.WebhookDelivery{}
+    for rows.Next() {
+        delivery, err := scanWebhookDelivery(rows)
+        if err != nil {
+            return nil, err
+        }
+        deliveries = append(deliveries, *delivery)
+    }
+
+    return deliveries, rows.Err()
+}
+
+func scanWebhookDelivery(row rowScanner) (*models.WebhookDelivery, error) {
+    var d models.WebhookDelivery
+    var payload []byte
+    var statusCode sql.NullInt64
+    var lastError sql.NullString
+    var nextAttemptAt, deliveredAt sql.NullTime
+
+    err := row.Scan(&d.DeliveryID, &d.SubscriptionID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts,
+        &statusCode, &lastError, &nextAttemptAt, &d.CreatedAt, &deliveredAt)
+    if err == sql.ErrNoRows {
+        return nil, ErrNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    d.Payload = payload
+    d.LastStatusCode = int(statusCode.Int64)
+    d.LastError = lastError.String
+    if nextAttemptAt.Valid {
+        d.NextAttemptAt = &nextAttemptAt.Time
+    }
+    if deliveredAt.Valid {
+        d.DeliveredAt = &deliveredAt.Time
+    }
+
+    return &d, nil
+}
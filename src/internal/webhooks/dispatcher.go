@@ -0,0 +1,184 @@
+package webhooks
+
+import (
+    "bytes"
+    "context"
+    "crypto/hmac"
+    "crypto/rand"
+    "crypto/sha256"
+    "encoding/hex"
+    "fmt"
+    "io"
+    "log"
+    "net/http"
+    "strconv"
+    "sync"
+    "time"
+
+    "pr-reviewer/src/internal/backoff"
+    "pr-reviewer/src/internal/domain/models"
+    "pr-reviewer/src/internal/storage"
+)
+
+const (
+    HeaderEvent     = "X-Reviewer-Event"
+    HeaderDelivery  = "X-Reviewer-Delivery"
+    HeaderSignature = "X-Reviewer-Signature-256"
+)
+
+type Config struct {
+    PollInterval  time.Duration
+    RetryBase     time.Duration
+    MaxRetryDelay time.Duration
+    MaxAttempts   int
+    BatchSize     int
+    Timeout       time.Duration
+}
+
+func DefaultConfig() Config {
+    return Config{
+        PollInterval:  time.Second,
+        RetryBase:     10 * time.Second,
+        MaxRetryDelay: time.Hour,
+        MaxAttempts:   8,
+        BatchSize:     20,
+        Timeout:       10 * time.Second,
+    }
+}
+
+// Dispatcher отправляет доставки из очереди подписчикам с повторами
+type Dispatcher struct {
+    repo   database.WebhookRepository
+    config Config
+    client *http.Client
+}
+
+func NewDispatcher(repo database.WebhookRepository, config Config) *Dispatcher {
+    return &Dispatcher{
+        repo:   repo,
+        config: config,
+        client: &http.Client{Timeout: config.Timeout},
+    }
+}
+
+// Run отправляет готовые доставки, пока не будет отменён ctx
+func (d *Dispatcher) Run(ctx context.Context) {
+    ticker := time.NewTicker(d.config.PollInterval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+            d.DeliverDue(ctx)
+        }
+    }
+}
+
+// DeliverDue отправляет все доставки, время которых наступило
+func (d *Dispatcher) DeliverDue(ctx context.Context) {
+    for ctx.Err() == nil {
+        deliveries, err := d.repo.ClaimWebhookDeliveries(2*d.config.Timeout, d.config.BatchSize)
+        if err != nil {
+            log.Printf("webhooks: failed to claim deliveries: %v", err)
+            return
+        }
+
+        subs := make(map[string]*models.WebhookSubscription)
+        var wg sync.WaitGroup
+        for _, delivery := range deliveries {
+            sub, ok := subs[delivery.SubscriptionID]
+            if !ok {
+                sub, err = d.repo.GetWebhookSubscription(delivery.SubscriptionID)
+                if err != nil {
+                    log.Printf("webhooks: failed to load subscription %s: %v", delivery.SubscriptionID, err)
+                    continue
+                }
+                subs[delivery.SubscriptionID] = sub
+            }
+
+            wg.Add(1)
+            go func(delivery models.WebhookDelivery, sub *models.WebhookSubscription) {
+                defer wg.Done()
+                d.deliver(ctx, delivery, sub)
+            }(delivery, sub)
+        }
+        wg.Wait()
+
+        if len(deliveries) < d.config.BatchSize {
+            return
+        }
+    }
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery models.WebhookDelivery, sub *models.WebhookSubscription) {
+    statusCode, err := d.send(ctx, delivery, sub)
+
+    attempt := database.DeliveryAttempt{Status: models.DeliveryDelivered, StatusCode: statusCode}
+    if err != nil {
+        attempt.Error = err.Error()
+        if delivery.Attempts+1 >= d.config.MaxAttempts {
+            attempt.Status = models.DeliveryDead
+            log.Printf("webhooks: delivery %d to %s moved to dead letters: %v", delivery.DeliveryID, sub.URL, err)
+        } else {
+            attempt.Status = models.DeliveryPending
+            attempt.RetryIn = d.RetryDelay(delivery.Attempts + 1)
+        }
+    }
+
+    if err := d.repo.RecordWebhookAttempt(delivery.DeliveryID, attempt); err != nil {
+        log.Printf("webhooks: failed to record attempt for delivery %d: %v", delivery.DeliveryID, err)
+    }
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery models.WebhookDelivery, sub *models.WebhookSubscription) (int, error) {
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+    if err != nil {
+        return 0, err
+    }
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("User-Agent", "pr-reviewer-webhooks")
+    req.Header.Set(HeaderEvent, delivery.EventType)
+    req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.DeliveryID, 10))
+    req.Header.Set(HeaderSignature, "sha256="+Sign(sub.Secret, delivery.Payload))
+
+    resp, err := d.client.Do(req)
+    if err != nil {
+        return 0, err
+    }
+    defer resp.Body.Close()
+    io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+    if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+        return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+    }
+
+    return resp.StatusCode, nil
+}
+
+// RetryDelay возвращает паузу перед повтором после attempts неудачных попыток: RetryBase * 2^(attempts-1)
+func (d *Dispatcher) RetryDelay(attempts int) time.Duration {
+    return backoff.Delay(d.config.RetryBase, d.config.MaxRetryDelay, attempts)
+}
+
+// Sign возвращает hex HMAC-SHA256 тела запроса, который отправляется в заголовке X-Reviewer-Signature-256
+func Sign(secret string, body []byte) string {
+    mac := hmac.New(sha256.New, []byte(secret))
+    mac.Write(body)
+    return hex.EncodeToString(mac.Sum(nil))
+}
+
+func NewSubscriptionID() string {
+    return "sub_" + randomHex(8)
+}
+
+func NewSecret() string {
+    return randomHex(24)
+}
+
+func randomHex(n int) string {
+    b := make([]byte, n)
+    rand.Read(b)
+    return hex.EncodeToString(b)
+}
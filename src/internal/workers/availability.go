@@ -0,0 +1,53 @@
+package workers
+
+import (
+    "context"
+    "log"
+    "time"
+
+    "pr-reviewer/src/internal/storage"
+)
+
+// AvailabilityWorker снимает ревью с пользователей, у которых начался период отсутствия с reassign_reviews
+type AvailabilityWorker struct {
+    repo         database.AvailabilityRepository
+    pollInterval time.Duration
+}
+
+func NewAvailabilityWorker(repo database.AvailabilityRepository, pollInterval time.Duration) *AvailabilityWorker {
+    return &AvailabilityWorker{repo: repo, pollInterval: pollInterval}
+}
+
+// Run проверяет периоды раз в pollInterval, пока не будет отменён ctx
+func (w *AvailabilityWorker) Run(ctx context.Context) {
+    ticker := time.NewTicker(w.pollInterval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+            w.RunOnce()
+        }
+    }
+}
+
+func (w *AvailabilityWorker) RunOnce() {
+    started, err := w.repo.StartDueAvailability()
+    if err != nil {
+        log.Printf("availability: failed to start due periods: %v", err)
+        return
+    }
+
+    for _, item := range started {
+        short := 0
+        for _, pr := range item.PullRequests {
+            if pr.LeftShort {
+                short++
+            }
+        }
+        log.Printf("availability: period %d of %s started, %d pull requests affected, %d left short of reviewers",
+            item.AvailabilityID, item.UserID, len(item.PullRequests), short)
+    }
+}
@@ -0,0 +1,59 @@
+package workers
+
+import (
+    "context"
+    "log"
+    "time"
+
+    "pr-reviewer/src/internal/storage"
+)
+
+const slaBatchSize = 100
+
+// SLAWorker эскалирует ревью, по которым ревьюверы не приняли решение за SLA команды
+type SLAWorker struct {
+    repo         database.ReviewSLARepository
+    pollInterval time.Duration
+}
+
+func NewSLAWorker(repo database.ReviewSLARepository, pollInterval time.Duration) *SLAWorker {
+    return &SLAWorker{repo: repo, pollInterval: pollInterval}
+}
+
+// Run проверяет SLA раз в pollInterval, пока не будет отменён ctx
+func (w *SLAWorker) Run(ctx context.Context) {
+    ticker := time.NewTicker(w.pollInterval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+            w.RunOnce(ctx)
+        }
+    }
+}
+
+// RunOnce обрабатывает все просроченные ревью пачками по slaBatchSize
+func (w *SLAWorker) RunOnce(ctx context.Context) {
+    for ctx.Err() == nil {
+        escalations, err := w.repo.EscalateOverdueReviews(slaBatchSize)
+        if err != nil {
+            log.Printf("sla: failed to escalate overdue reviews: %v", err)
+            return
+        }
+
+        for _, e := range escalations {
+            if e.ReplacedBy != "" {
+                log.Printf("sla: review of %s by %s is overdue, reassigned to %s", e.PullRequestID, e.ReviewerID, e.ReplacedBy)
+            } else {
+                log.Printf("sla: review of %s by %s is overdue", e.PullRequestID, e.ReviewerID)
+            }
+        }
+
+        if len(escalations) < slaBatchSize {
+            return
+        }
+    }
+}
@@ -1,40 +1,258 @@
 package main
 
 import (
+    "context"
+    "fmt"
     "log"
     "os"
+    "strconv"
+    "strings"
     "time"
+    "pr-reviewer/src/internal/api/auth"
+    "pr-reviewer/src/internal/codeowners"
     "pr-reviewer/src/internal/storage"
+    "pr-reviewer/src/internal/storage/memory"
     "pr-reviewer/src/internal/api/handlers"
+    "pr-reviewer/src/internal/outbox"
+    "pr-reviewer/src/internal/webhooks"
+    "pr-reviewer/src/internal/workers"
 
     "github.com/gin-gonic/gin"
 )
 
 type Config struct {
-    DBHost     string
-    DBPort     string
-    DBUser     string
-    DBPassword string
-    DBName     string
-    Port       string
-    ResetDB    bool
+    DBHost           string
+    DBPort           string
+    DBUser           string
+    DBPassword       string
+    DBName           string
+    Port             string
+    Storage          string
+    MigrateOnStartup bool
+    GitHubSecret     string
+    Webhooks         webhooks.Config
+    Outbox           outbox.Config
+    OutboxSinks      string
+    AvailabilityPoll time.Duration
+    SLAPoll          time.Duration
+    AdminToken       string
+    CORSOrigins      []string
+    CodeOwnersFiles  map[string]string
 }
 
 func loadConfig() Config {
     return Config{
-        DBHost:     getEnv("DB_HOST", "postgres"),
-        DBPort:     getEnv("DB_PORT", "5432"),
-        DBUser:     getEnv("DB_USER", "postgres"),
-        DBPassword: getEnv("DB_PASSWORD", "postgres"),
-        DBName:     getEnv("DB_NAME", "pr_reviewer"),
-        Port:       getEnv("PORT", "8080"),
-        ResetDB:    getEnv("RESET_DB_ON_STARTUP", "true") == "true",
+        DBHost:           getEnv("DB_HOST", "postgres"),
+        DBPort:           getEnv("DB_PORT", "5432"),
+        DBUser:           getEnv("DB_USER", "postgres"),
+        DBPassword:       getEnv("DB_PASSWORD", "postgres"),
+        DBName:           getEnv("DB_NAME", "pr_reviewer"),
+        Port:             getEnv("PORT", "8080"),
+        Storage:          getEnv("STORAGE", "postgres"),
+        MigrateOnStartup: getEnv("MIGRATE_ON_STARTUP", "true") == "true",
+        GitHubSecret:     getEnv("GITHUB_WEBHOOK_SECRET", ""),
+        Webhooks:         loadWebhooksConfig(),
+        Outbox:           loadOutboxConfig(),
+        OutboxSinks:      getEnv("OUTBOX_SINKS", outbox.SinkWebhooks),
+        AvailabilityPoll: getDurationEnv("AVAILABILITY_POLL_INTERVAL", time.Minute),
+        SLAPoll:          getDurationEnv("SLA_POLL_INTERVAL", time.Minute),
+        AdminToken:       getEnv("ADMIN_TOKEN", ""),
+        CORSOrigins:      getListEnv("CORS_ALLOWED_ORIGINS"),
+        CodeOwnersFiles:  getMapEnv("CODEOWNERS_FILES"),
     }
 }
 
+func loadWebhooksConfig() webhooks.Config {
+    config := webhooks.DefaultConfig()
+    config.PollInterval = getDurationEnv("WEBHOOK_POLL_INTERVAL", config.PollInterval)
+    config.RetryBase = getDurationEnv("WEBHOOK_RETRY_BASE", config.RetryBase)
+    config.MaxRetryDelay = getDurationEnv("WEBHOOK_MAX_RETRY_DELAY", config.MaxRetryDelay)
+    config.Timeout = getDurationEnv("WEBHOOK_TIMEOUT", config.Timeout)
+    config.MaxAttempts = getIntEnv("WEBHOOK_MAX_ATTEMPTS", config.MaxAttempts)
+    return config
+}
+
+func loadOutboxConfig() outbox.Config {
+    config := outbox.DefaultConfig()
+    config.PollInterval = getDurationEnv("OUTBOX_POLL_INTERVAL", config.PollInterval)
+    config.Retention = getDurationEnv("OUTBOX_RETENTION", config.Retention)
+    return config
+}
+
 func main() {
     config := loadConfig()
 
+    var repo database.Repository
+    if config.Storage == "memory" {
+        log.Println("Using in-memory storage, data will be lost on restart")
+        repo = memory.New()
+    } else {
+        db := connectDatabase(config)
+
+        if len(os.Args) > 1 && os.Args[1] == "migrate" {
+            err := runMigrateCommand(db, os.Args[2:])
+            db.Close()
+            if err != nil {
+                log.Fatal("Migration failed: ", err)
+            }
+            return
+        }
+
+        if config.MigrateOnStartup {
+            if err := db.MigrateUp(); err != nil {
+                log.Fatal(err)
+            }
+            log.Println("Database migrations applied")
+        }
+
+        repo = db
+    }
+    defer repo.Close()
+
+    sinks, err := outbox.SinksFromNames(config.OutboxSinks, repo)
+    if err != nil {
+        log.Fatal(err)
+    }
+    go outbox.NewDispatcher(repo, config.Outbox, sinks...).Run(context.Background())
+    go webhooks.NewDispatcher(repo, config.Webhooks).Run(context.Background())
+    go workers.NewAvailabilityWorker(repo, config.AvailabilityPoll).Run(context.Background())
+    go workers.NewSLAWorker(repo, config.SLAPoll).Run(context.Background())
+
+    codeOwners := codeowners.NewSource(repo, config.CodeOwnersFiles)
+
+    teamHandler := handlers.NewTeamHandler(repo, repo)
+    userHandler := handlers.NewUserHandler(repo, repo)
+    prHandler := handlers.NewPRHandler(repo, repo, repo, codeOwners)
+	statsHandler := handlers.NewStatsHandler(repo)
+    githubHandler := handlers.NewGitHubHandler(repo, repo, repo, config.GitHubSecret)
+    webhookHandler := handlers.NewWebhookHandler(repo)
+    availabilityHandler := handlers.NewAvailabilityHandler(repo, repo)
+    tokenHandler := handlers.NewTokenHandler(repo, repo)
+    codeOwnersHandler := handlers.NewCodeOwnersHandler(repo, codeOwners)
+    repositoryHandler := handlers.NewRepositoryHandler(repo)
+
+    if config.GitHubSecret == "" {
+        log.Println("GITHUB_WEBHOOK_SECRET is not set, GitHub webhooks will be rejected")
+    }
+    if config.AdminToken == "" {
+        log.Println("ADMIN_TOKEN is not set, only previously issued API tokens will be accepted")
+    }
+
+    router := gin.Default()
+
+    router.Use(corsMiddleware(config.CORSOrigins))
+
+    // подпись GitHub проверяется в самом обработчике, токен API ему не нужен
+    router.POST("/webhooks/github", githubHandler.Webhook)
+
+    authenticated := router.Group("/", auth.Authenticate(repo, repo, config.AdminToken))
+
+    // команды, пользователи, репозитории, переназначение и ревью проверяют роль в обработчиках: права зависят от команды
+    authenticated.POST("/team/add", teamHandler.AddTeam)
+    authenticated.GET("/team/get", teamHandler.GetTeam)
+    authenticated.GET("/team/settings", teamHandler.GetSettings)
+    authenticated.POST("/team/settings", teamHandler.UpdateSettings)
+    authenticated.POST("/team/deactivateUsers", teamHandler.DeactivateUsers)
+    authenticated.POST("/team/addMembers", teamHandler.AddMembers)
+    authenticated.POST("/team/removeMembers", teamHandler.RemoveMembers)
+    authenticated.POST("/team/rename", teamHandler.Rename)
+    authenticated.POST("/team/delete", teamHandler.Delete)
+    authenticated.GET("/team/leads", teamHandler.GetLeads)
+    authenticated.POST("/team/addLead", teamHandler.AddLead)
+    authenticated.POST("/team/removeLead", teamHandler.RemoveLead)
+
+    authenticated.POST("/users/setIsActive", userHandler.SetIsActive)
+    authenticated.GET("/users/getReview", userHandler.GetReview)
+    authenticated.POST("/users/setMaxOpenReviews", userHandler.SetMaxOpenReviews)
+    authenticated.POST("/users/setLevel", userHandler.SetLevel)
+    authenticated.GET("/users/skills", userHandler.GetSkills)
+    authenticated.POST("/users/setSkills", userHandler.SetSkills)
+    authenticated.POST("/users/setGithubLogin", githubHandler.SetLogin)
+    authenticated.POST("/users/removeGithubLogin", githubHandler.RemoveLogin)
+    authenticated.GET("/users/availability", availabilityHandler.ListAvailability)
+    authenticated.GET("/users/availability/get", availabilityHandler.GetAvailability)
+    authenticated.POST("/users/availability/add", availabilityHandler.AddAvailability)
+    authenticated.POST("/users/availability/update", availabilityHandler.UpdateAvailability)
+    authenticated.POST("/users/availability/delete", availabilityHandler.DeleteAvailability)
+
+    authenticated.POST("/pullRequest/reassign", prHandler.Reassign)
+    authenticated.POST("/pullRequest/review", prHandler.SubmitReview)
+
+    authenticated.POST("/repository/set", repositoryHandler.SetRepository)
+    authenticated.POST("/repository/delete", repositoryHandler.DeleteRepository)
+
+    authenticated.GET("/auth/permissions", tokenHandler.Permissions)
+
+    reader := authenticated.Group("/", auth.Require(auth.ActionServiceRead))
+    reader.GET("/pullRequest/get", prHandler.GetPR)
+    reader.GET("/pullRequest/list", prHandler.ListPRs)
+    reader.GET("/pullRequest/history", prHandler.GetHistory)
+
+    reader.GET("/stats/system", statsHandler.GetSystemStats)
+    reader.GET("/stats/users", statsHandler.GetUserStats)
+    reader.GET("/stats/prs", statsHandler.GetPRStats)
+    reader.GET("/stats/top-reviewers", statsHandler.GetTopReviewers)
+
+    reader.GET("/webhooks/subscriptions/list", webhookHandler.ListSubscriptions)
+    reader.GET("/webhooks/subscriptions/get", webhookHandler.GetSubscription)
+    reader.GET("/webhooks/deliveries", webhookHandler.ListDeliveries)
+    reader.GET("/webhooks/deadLetters", webhookHandler.ListDeadLetters)
+
+    reader.GET("/codeowners/get", codeOwnersHandler.GetCodeOwners)
+
+    reader.GET("/repository/get", repositoryHandler.GetRepository)
+    reader.GET("/repository/list", repositoryHandler.ListRepositories)
+
+    admin := authenticated.Group("/", auth.Require(auth.ActionServiceAdmin))
+    admin.POST("/pullRequest/create", prHandler.CreatePR)
+    admin.POST("/pullRequest/merge", prHandler.MergePR)
+    admin.POST("/pullRequest/close", prHandler.ClosePR)
+    admin.POST("/pullRequest/reopen", prHandler.ReopenPR)
+
+    admin.POST("/webhooks/subscriptions/add", webhookHandler.AddSubscription)
+    admin.POST("/webhooks/subscriptions/update", webhookHandler.UpdateSubscription)
+    admin.POST("/webhooks/subscriptions/delete", webhookHandler.DeleteSubscription)
+    admin.POST("/webhooks/deliveries/replay", webhookHandler.ReplayDelivery)
+
+    admin.POST("/codeowners/set", codeOwnersHandler.SetCodeOwners)
+    admin.POST("/codeowners/delete", codeOwnersHandler.DeleteCodeOwners)
+
+    admin.POST("/auth/tokens/add", tokenHandler.IssueToken)
+    admin.GET("/auth/tokens/list", tokenHandler.ListTokens)
+    admin.POST("/auth/tokens/revoke", tokenHandler.RevokeToken)
+
+    log.Printf("Server starting on :%s", config.Port)
+    if err := router.Run(":" + config.Port); err != nil {
+        log.Fatal("Failed to start server:", err)
+    }
+}
+
+// corsMiddleware разрешает браузерные запросы только с перечисленных origin; "*" разрешает любой
+func corsMiddleware(origins []string) gin.HandlerFunc {
+    allowed := make(map[string]bool, len(origins))
+    for _, origin := range origins {
+        allowed[origin] = true
+    }
+
+    return func(c *gin.Context) {
+        origin := c.GetHeader("Origin")
+        if origin != "" && (allowed["*"] || allowed[origin]) {
+            c.Header("Access-Control-Allow-Origin", origin)
+            c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+            c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+        }
+        c.Header("Vary", "Origin")
+
+        if c.Request.Method == "OPTIONS" {
+            c.AbortWithStatus(204)
+            return
+        }
+
+        c.Next()
+    }
+}
+
+func connectDatabase(config Config) *database.DB {
     connStr := "host=" + config.DBHost + " port=" + config.DBPort + " user=" + config.DBUser +
         " password=" + config.DBPassword + " dbname=" + config.DBName + " sslmode=disable"
 
@@ -60,55 +278,91 @@ func main() {
     if err != nil {
         log.Fatal("Failed to connect to database after", maxAttempts, "attempts:", err)
     }
-    defer db.Close()
 
     log.Println("Successfully connected to database")
+    return db
+}
 
-    teamHandler := handlers.NewTeamHandler(db)
-    userHandler := handlers.NewUserHandler(db)
-    prHandler := handlers.NewPRHandler(db)
-	statsHandler := handlers.NewStatsHandler(db)
-
-    router := gin.Default()
-
-    router.Use(func(c *gin.Context) {
-        c.Header("Access-Control-Allow-Origin", "*")
-        c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-        c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+func runMigrateCommand(db *database.DB, args []string) error {
+    if len(args) == 0 {
+        return fmt.Errorf("usage: migrate up | down [steps] | status")
+    }
 
-        if c.Request.Method == "OPTIONS" {
-            c.AbortWithStatus(204)
-            return
+    switch args[0] {
+    case "up":
+        if err := db.MigrateUp(); err != nil {
+            return err
         }
+    case "down":
+        steps := 1
+        if len(args) > 1 {
+            n, err := strconv.Atoi(args[1])
+            if err != nil {
+                return fmt.Errorf("invalid steps %q", args[1])
+            }
+            steps = n
+        }
+        if err := db.MigrateDown(steps); err != nil {
+            return err
+        }
+    case "status":
+    default:
+        return fmt.Errorf("unknown migrate command %q", args[0])
+    }
 
-        c.Next()
-    })
-
-    router.POST("/team/add", teamHandler.AddTeam)
-    router.GET("/team/get", teamHandler.GetTeam)
+    status, err := db.MigrationStatus()
+    if err != nil {
+        return err
+    }
+    log.Printf("Schema version: %d (latest %d, pending %d, dirty %t)", status.Version, status.LatestVersion, status.Pending, status.Dirty)
+    return nil
+}
 
-    router.POST("/users/setIsActive", userHandler.SetIsActive)
-    router.GET("/users/getReview", userHandler.GetReview)
+func getEnv(key, defaultValue string) string {
+    value := os.Getenv(key)
+    if value == "" {
+        return defaultValue
+    }
+    return value
+}
 
-    router.POST("/pullRequest/create", prHandler.CreatePR)
-    router.POST("/pullRequest/merge", prHandler.MergePR)
-    router.POST("/pullRequest/reassign", prHandler.Reassign)
+// getListEnv разбирает список через запятую, пустые элементы пропускаются
+func getListEnv(key string) []string {
+    var values []string
+    for _, value := range strings.Split(os.Getenv(key), ",") {
+        if value = strings.TrimSpace(value); value != "" {
+            values = append(values, value)
+        }
+    }
+    return values
+}
 
-	router.GET("/stats/system", statsHandler.GetSystemStats)
-    router.GET("/stats/users", statsHandler.GetUserStats)
-    router.GET("/stats/prs", statsHandler.GetPRStats)
-    router.GET("/stats/top-reviewers", statsHandler.GetTopReviewers)
+// getMapEnv разбирает пары key=value через запятую
+func getMapEnv(key string) map[string]string {
+    values := make(map[string]string)
+    for _, pair := range getListEnv(key) {
+        name, value, found := strings.Cut(pair, "=")
+        if !found {
+            log.Printf("%s: ignoring %q, expected key=value", key, pair)
+            continue
+        }
+        values[strings.TrimSpace(name)] = strings.TrimSpace(value)
+    }
+    return values
+}
 
-    log.Printf("Server starting on :%s", config.Port)
-    if err := router.Run(":" + config.Port); err != nil {
-        log.Fatal("Failed to start server:", err)
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+    value, err := time.ParseDuration(os.Getenv(key))
+    if err != nil || value <= 0 {
+        return defaultValue
     }
+    return value
 }
 
-func getEnv(key, defaultValue string) string {
-    value := os.Getenv(key)
-    if value == "" {
+func getIntEnv(key string, defaultValue int) int {
+    value, err := strconv.Atoi(os.Getenv(key))
+    if err != nil || value <= 0 {
         return defaultValue
     }
     return value
-}
\ No newline at end of file
+}
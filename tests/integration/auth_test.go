@@ -0,0 +1,117 @@
+package integration
+
+import (
+    "bytes"
+    "encoding/json"
+    "net/http"
+
+    "github.com/stretchr/testify/assert"
+)
+
+// requestWithToken выполняет запрос с заданным заголовком Authorization; пустой token - запрос без него
+func (suite *IntegrationTestSuite) requestWithToken(token, method, path string, data map[string]interface{}) (int, map[string]interface{}) {
+    var body bytes.Buffer
+    if data != nil {
+        json.NewEncoder(&body).Encode(data)
+    }
+    req, _ := http.NewRequest(method, suite.baseURL+path, &body)
+    req.Header.Set("Content-Type", "application/json")
+    if token != "" {
+        req.Header.Set("Authorization", "Bearer "+token)
+    }
+
+    resp, err := http.DefaultClient.Do(req)
+    if err != nil {
+        suite.T().Fatalf("%s %s failed: %v", method, path, err)
+    }
+    defer resp.Body.Close()
+
+    var response map[string]interface{}
+    json.NewDecoder(resp.Body).Decode(&response)
+    return resp.StatusCode, response
+}
+
+func (suite *IntegrationTestSuite) TestAuthentication() {
+    t := suite.T()
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "auth_team",
+        "members": []map[string]interface{}{
+            {"user_id": "auth_u1", "username": "Author", "is_active": true},
+            {"user_id": "auth_u2", "username": "Reviewer", "is_active": true},
+        },
+    })
+    status, response := suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "auth_pr_1",
+        "pull_request_name": "Auth check",
+        "author_id":         "auth_u1",
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    assert.Equal(t, []interface{}{"auth_u2"}, response["pr"].(map[string]interface{})["assigned_reviewers"])
+
+    status, response = suite.requestWithToken("", http.MethodGet, "/stats/system", nil)
+    assert.Equal(t, http.StatusUnauthorized, status)
+    assert.Equal(t, "UNAUTHORIZED", errorCode(response))
+
+    status, response = suite.requestWithToken("prt_unknown", http.MethodGet, "/stats/system", nil)
+    assert.Equal(t, http.StatusUnauthorized, status)
+    assert.Equal(t, "UNAUTHORIZED", errorCode(response))
+
+    status, response = suite.postJSON("/auth/tokens/add", map[string]interface{}{"name": "bad", "scope": "root"})
+    assert.Equal(t, http.StatusBadRequest, status)
+    assert.Equal(t, "INVALID_REQUEST", errorCode(response))
+
+    status, response = suite.postJSON("/auth/tokens/add", map[string]interface{}{"name": "ghost", "scope": "user", "user_id": "auth_missing"})
+    assert.Equal(t, http.StatusNotFound, status)
+
+    status, response = suite.postJSON("/auth/tokens/add", map[string]interface{}{"name": "reviewer laptop", "scope": "user", "user_id": "auth_u2"})
+    assert.Equal(t, http.StatusCreated, status)
+    secret := response["secret"].(string)
+    token := response["token"].(map[string]interface{})
+    tokenID := token["token_id"].(string)
+    assert.Equal(t, "user", token["scope"])
+    assert.Equal(t, "auth_u2", token["user_id"])
+
+    status, response = suite.getJSON("/auth/tokens/list")
+    assert.Equal(t, http.StatusOK, status)
+    for _, item := range asSlice(response["tokens"]) {
+        assert.NotContains(t, item, "secret", "secrets must not be listed")
+        assert.NotContains(t, item, "token_hash")
+    }
+
+    status, response = suite.requestWithToken(secret, http.MethodGet, "/users/getReview?user_id=auth_u2", nil)
+    assert.Equal(t, http.StatusOK, status)
+    assert.Len(t, asSlice(response["pull_requests"]), 1)
+
+    status, response = suite.requestWithToken(secret, http.MethodGet, "/users/getReview?user_id=auth_u1", nil)
+    assert.Equal(t, http.StatusForbidden, status)
+    assert.Equal(t, "FORBIDDEN", errorCode(response))
+
+    status, response = suite.requestWithToken(secret, http.MethodPost, "/team/add", map[string]interface{}{
+        "team_name": "auth_hijack",
+        "members":   []map[string]interface{}{},
+    })
+    assert.Equal(t, http.StatusForbidden, status)
+    assert.Equal(t, "FORBIDDEN", errorCode(response))
+
+    status, _ = suite.requestWithToken(secret, http.MethodPost, "/pullRequest/review", map[string]interface{}{
+        "pull_request_id": "auth_pr_1",
+        "reviewer_id":     "auth_u2",
+        "decision":        "APPROVED",
+    })
+    assert.Equal(t, http.StatusOK, status)
+
+    status, response = suite.getJSON("/pullRequest/history?pull_request_id=auth_pr_1")
+    assert.Equal(t, http.StatusOK, status)
+
+    status, response = suite.postJSON("/auth/tokens/revoke", map[string]interface{}{"token_id": tokenID})
+    assert.Equal(t, http.StatusOK, status)
+    assert.NotNil(t, response["token"].(map[string]interface{})["revoked_at"])
+
+    status, response = suite.requestWithToken(secret, http.MethodGet, "/users/getReview?user_id=auth_u2", nil)
+    assert.Equal(t, http.StatusUnauthorized, status)
+    assert.Equal(t, "UNAUTHORIZED", errorCode(response))
+
+    status, _ = suite.postJSON("/auth/tokens/revoke", map[string]interface{}{"token_id": "tok_missing"})
+    assert.Equal(t, http.StatusNotFound, status)
+}
@@ -0,0 +1,137 @@
+package integration
+
+import (
+    "fmt"
+    "net/http"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func (suite *IntegrationTestSuite) TestAvailabilityExcludesReviewers() {
+    t := suite.T()
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "avail_team",
+        "members": []map[string]interface{}{
+            {"user_id": "avail_u1", "username": "Author", "is_active": true},
+            {"user_id": "avail_u2", "username": "On Vacation", "is_active": true},
+            {"user_id": "avail_u3", "username": "Reviewer 3", "is_active": true},
+            {"user_id": "avail_u4", "username": "Reviewer 4", "is_active": true},
+        },
+    })
+
+    now := time.Now()
+    status, response := suite.postJSON("/users/availability/add", map[string]interface{}{
+        "user_id":   "avail_u2",
+        "starts_at": now.Add(-time.Hour).Format(time.RFC3339),
+        "ends_at":   now.Add(24 * time.Hour).Format(time.RFC3339),
+        "reason":    "vacation",
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    period := response["period"].(map[string]interface{})
+    periodID := period["availability_id"]
+
+    status, response = suite.postJSON("/users/availability/add", map[string]interface{}{
+        "user_id":   "avail_u2",
+        "starts_at": now.Format(time.RFC3339),
+        "ends_at":   now.Add(-time.Hour).Format(time.RFC3339),
+    })
+    assert.Equal(t, http.StatusBadRequest, status)
+    assert.Equal(t, "INVALID_REQUEST", errorCode(response))
+
+    status, _ = suite.postJSON("/users/availability/add", map[string]interface{}{
+        "user_id":   "missing_user",
+        "starts_at": now.Format(time.RFC3339),
+        "ends_at":   now.Add(time.Hour).Format(time.RFC3339),
+    })
+    assert.Equal(t, http.StatusNotFound, status)
+
+    status, response = suite.getJSON("/users/availability?user_id=avail_u2")
+    assert.Equal(t, http.StatusOK, status)
+    assert.Len(t, asSlice(response["periods"]), 1)
+
+    status, response = suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "avail_pr_1",
+        "pull_request_name": "Vacation check",
+        "author_id":         "avail_u1",
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    pr := response["pr"].(map[string]interface{})
+    assert.ElementsMatch(t, []interface{}{"avail_u3", "avail_u4"}, pr["assigned_reviewers"])
+
+    status, response = suite.postJSON("/pullRequest/reassign", map[string]interface{}{
+        "pull_request_id": "avail_pr_1",
+        "old_user_id":     "avail_u3",
+    })
+    assert.Equal(t, http.StatusConflict, status)
+    assert.Equal(t, "NO_CANDIDATE", errorCode(response))
+
+    status, _ = suite.postJSON("/users/availability/delete", map[string]interface{}{"availability_id": periodID})
+    assert.Equal(t, http.StatusNoContent, status)
+
+    status, response = suite.postJSON("/pullRequest/reassign", map[string]interface{}{
+        "pull_request_id": "avail_pr_1",
+        "old_user_id":     "avail_u3",
+    })
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, "avail_u2", response["replaced_by"])
+}
+
+func (suite *IntegrationTestSuite) TestAvailabilityReassignsOpenReviews() {
+    t := suite.T()
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "leave_team",
+        "members": []map[string]interface{}{
+            {"user_id": "leave_u1", "username": "Author", "is_active": true},
+            {"user_id": "leave_u2", "username": "Reviewer 2", "is_active": true},
+            {"user_id": "leave_u3", "username": "Reviewer 3", "is_active": true},
+            {"user_id": "leave_u4", "username": "Reviewer 4", "is_active": true},
+        },
+    })
+
+    var leaving interface{}
+    for i := 0; i < 3; i++ {
+        _, response := suite.postJSON("/pullRequest/create", map[string]interface{}{
+            "pull_request_id":   fmt.Sprintf("leave_pr_%d", i),
+            "pull_request_name": "Leave check",
+            "author_id":         "leave_u1",
+        })
+        leaving = asSlice(response["pr"].(map[string]interface{})["assigned_reviewers"])[0]
+    }
+
+    _, response := suite.getJSON(fmt.Sprintf("/users/getReview?user_id=%v", leaving))
+    assert.NotEmpty(t, asSlice(response["pull_requests"]))
+
+    status, response := suite.postJSON("/users/availability/add", map[string]interface{}{
+        "user_id":          leaving,
+        "starts_at":        time.Now().Add(-time.Minute).Format(time.RFC3339),
+        "ends_at":          time.Now().Add(time.Hour).Format(time.RFC3339),
+        "reassign_reviews": true,
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    periodID := response["period"].(map[string]interface{})["availability_id"]
+
+    assert.Eventually(t, func() bool {
+        _, response := suite.getJSON(fmt.Sprintf("/users/availability/get?availability_id=%v", periodID))
+        period, ok := response["period"].(map[string]interface{})
+        return ok && period["reassigned_at"] != nil
+    }, 5*time.Second, 100*time.Millisecond, "period start must be handled by the availability job")
+
+    _, response = suite.getJSON(fmt.Sprintf("/users/getReview?user_id=%v", leaving))
+    for _, item := range asSlice(response["pull_requests"]) {
+        assert.NotEqual(t, "OPEN", item.(map[string]interface{})["status"], "open reviews must be moved off the absent user")
+    }
+
+    for i := 0; i < 3; i++ {
+        status, response := suite.postJSON("/pullRequest/merge", map[string]interface{}{
+            "pull_request_id": fmt.Sprintf("leave_pr_%d", i),
+            "force":           true,
+        })
+        assert.Equal(t, http.StatusOK, status)
+        reviewers := response["pr"].(map[string]interface{})["assigned_reviewers"]
+        assert.NotContains(t, reviewers, leaving)
+        assert.Len(t, reviewers, 2)
+    }
+}
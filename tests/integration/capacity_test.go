@@ -0,0 +1,90 @@
+package integration
+
+import (
+    "net/http"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func (suite *IntegrationTestSuite) TestReviewCapacity() {
+    t := suite.T()
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "cap_team",
+        "members": []map[string]interface{}{
+            {"user_id": "cap_u1", "username": "Author", "is_active": true},
+            {"user_id": "cap_u2", "username": "Senior", "is_active": true},
+            {"user_id": "cap_u3", "username": "Reviewer 3", "is_active": true},
+            {"user_id": "cap_u4", "username": "Reviewer 4", "is_active": true},
+        },
+    })
+
+    status, response := suite.postJSON("/team/settings", map[string]interface{}{
+        "team_name":        "cap_team",
+        "max_open_reviews": 1,
+    })
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, float64(1), response["settings"].(map[string]interface{})["max_open_reviews"])
+
+    createPR := func(id string) (int, map[string]interface{}) {
+        return suite.postJSON("/pullRequest/create", map[string]interface{}{
+            "pull_request_id":   id,
+            "pull_request_name": "Capacity check",
+            "author_id":         "cap_u1",
+        })
+    }
+
+    status, response = createPR("cap_pr_1")
+    assert.Equal(t, http.StatusCreated, status)
+    assert.Len(t, response["pr"].(map[string]interface{})["assigned_reviewers"], 2)
+
+    status, response = createPR("cap_pr_2")
+    assert.Equal(t, http.StatusCreated, status)
+    assert.Len(t, response["pr"].(map[string]interface{})["assigned_reviewers"], 1, "only one teammate is below capacity")
+
+    suite.postJSON("/team/settings", map[string]interface{}{"team_name": "cap_team", "min_reviewers_count": 1})
+
+    status, response = createPR("cap_pr_3")
+    assert.Equal(t, http.StatusConflict, status)
+    assert.Equal(t, "NO_CANDIDATE", errorCode(response))
+    details := response["error"].(map[string]interface{})["details"].(map[string]interface{})
+    assert.Equal(t, float64(1), details["required"])
+    reasons := map[string]string{}
+    for _, e := range asSlice(details["excluded"]) {
+        exclusion := e.(map[string]interface{})
+        reasons[exclusion["user_id"].(string)] = exclusion["reason"].(string)
+        if exclusion["reason"] == "AT_CAPACITY" {
+            assert.Equal(t, float64(1), exclusion["open_reviews"])
+            assert.Equal(t, float64(1), exclusion["max_open_reviews"])
+        }
+    }
+    assert.Equal(t, map[string]string{
+        "cap_u1": "AUTHOR",
+        "cap_u2": "AT_CAPACITY",
+        "cap_u3": "AT_CAPACITY",
+        "cap_u4": "AT_CAPACITY",
+    }, reasons)
+
+    status, response = suite.postJSON("/users/setMaxOpenReviews", map[string]interface{}{"user_id": "cap_u2", "max_open_reviews": 3})
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, float64(3), response["user"].(map[string]interface{})["max_open_reviews"])
+
+    status, response = createPR("cap_pr_3")
+    assert.Equal(t, http.StatusCreated, status)
+    assert.Equal(t, []interface{}{"cap_u2"}, response["pr"].(map[string]interface{})["assigned_reviewers"])
+
+    status, response = suite.postJSON("/pullRequest/reassign", map[string]interface{}{
+        "pull_request_id": "cap_pr_3",
+        "old_user_id":     "cap_u2",
+    })
+    assert.Equal(t, http.StatusConflict, status)
+    assert.Equal(t, "NO_CANDIDATE", errorCode(response))
+    assert.NotNil(t, response["error"].(map[string]interface{})["details"])
+
+    status, response = suite.postJSON("/users/setMaxOpenReviews", map[string]interface{}{"user_id": "cap_u2", "max_open_reviews": nil})
+    assert.Equal(t, http.StatusOK, status)
+    assert.Nil(t, response["user"].(map[string]interface{})["max_open_reviews"])
+
+    status, _ = suite.postJSON("/users/setMaxOpenReviews", map[string]interface{}{"user_id": "cap_u2", "max_open_reviews": -1})
+    assert.Equal(t, http.StatusBadRequest, status)
+}
@@ -0,0 +1,92 @@
+package integration
+
+import (
+    "net/http"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func (suite *IntegrationTestSuite) TestCodeOwnersRouting() {
+    t := suite.T()
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "co_team",
+        "members": []map[string]interface{}{
+            {"user_id": "co_author", "username": "Author", "is_active": true},
+            {"user_id": "co_dba", "username": "DBA", "is_active": true},
+            {"user_id": "co_web", "username": "Web", "is_active": true},
+            {"user_id": "co_other", "username": "Other", "is_active": true},
+        },
+    })
+    suite.postJSON("/team/settings", map[string]interface{}{"team_name": "co_team", "reviewers_count": 1})
+    suite.postJSON("/users/setGithubLogin", map[string]interface{}{"user_id": "co_dba", "github_login": "Co-DBA-GH"})
+
+    status, response := suite.postJSON("/codeowners/set", map[string]interface{}{
+        "repository": "acme/mono",
+        "content":    "!vendor/ @acme/co_team\n",
+    })
+    assert.Equal(t, http.StatusBadRequest, status)
+    details := response["error"].(map[string]interface{})["details"].(map[string]interface{})
+    assert.Len(t, asSlice(details["lines"]), 1)
+
+    status, _ = suite.postJSON("/codeowners/set", map[string]interface{}{
+        "repository": "acme/mono",
+        "content": "# ownership\n" +
+            "*.sql      @co-dba-gh\n" +
+            "/web/      co_web @someone-else\n" +
+            "/ops/      @outsider\n",
+    })
+    assert.Equal(t, http.StatusOK, status)
+
+    reviewerOf := func(response map[string]interface{}) string {
+        reviewers := asSlice(response["pr"].(map[string]interface{})["assigned_reviewers"])
+        if len(reviewers) == 0 {
+            return ""
+        }
+        return reviewers[0].(string)
+    }
+
+    for _, id := range []string{"co_pr_1", "co_pr_2", "co_pr_3"} {
+        status, response = suite.postJSON("/pullRequest/create", map[string]interface{}{
+            "pull_request_id":   id,
+            "pull_request_name": "Migration",
+            "author_id":         "co_author",
+            "repository":        "acme/mono",
+            "changed_files":     []string{"services/billing/migrations/001_init.sql", "services/billing/main.go"},
+        })
+        assert.Equal(t, http.StatusCreated, status)
+        assert.Equal(t, "co_dba", reviewerOf(response), "the file owner is preferred even when loaded")
+    }
+
+    status, response = suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "acme/mono#7",
+        "pull_request_name": "Frontend",
+        "author_id":         "co_author",
+        "repository":        "acme/mono",
+        "changed_files":     []string{"web/src/app.tsx"},
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    assert.Equal(t, "co_web", reviewerOf(response))
+
+    status, response = suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "co_pr_ops",
+        "pull_request_name": "Ops",
+        "author_id":         "co_author",
+        "repository":        "acme/mono",
+        "changed_files":     []string{"ops/deploy.yaml"},
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    assert.NotEmpty(t, reviewerOf(response), "owners outside the team fall back to the team pool")
+
+    status, response = suite.getJSON("/codeowners/get?repository=acme/mono&changed_files=db/x.sql&changed_files=README.md")
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, "api", response["codeowners"].(map[string]interface{})["source"])
+    owners := response["owners"].(map[string]interface{})
+    assert.Equal(t, []interface{}{"@co-dba-gh"}, owners["db/x.sql"])
+    assert.Nil(t, owners["README.md"])
+
+    status, _ = suite.postJSON("/codeowners/delete", map[string]interface{}{"repository": "acme/mono"})
+    assert.Equal(t, http.StatusNoContent, status)
+    status, _ = suite.getJSON("/codeowners/get?repository=acme/mono")
+    assert.Equal(t, http.StatusNotFound, status)
+}
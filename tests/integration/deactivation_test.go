@@ -0,0 +1,221 @@
+package integration
+
+import (
+    "fmt"
+    "net/http"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func (suite *IntegrationTestSuite) TestBulkDeactivation() {
+    t := suite.T()
+
+    members := []map[string]interface{}{}
+    for i := 1; i <= 8; i++ {
+        members = append(members, map[string]interface{}{
+            "user_id": fmt.Sprintf("bulk_u%d", i), "username": fmt.Sprintf("Bulk User %d", i), "is_active": true,
+        })
+    }
+    suite.postJSON("/team/add", map[string]interface{}{"team_name": "bulk_team", "members": members})
+
+    const prCount = 200
+    for i := 0; i < prCount; i++ {
+        status, _ := suite.postJSON("/pullRequest/create", map[string]interface{}{
+            "pull_request_id":   fmt.Sprintf("bulk_pr_%d", i),
+            "pull_request_name": "Bulk PR",
+            "author_id":         fmt.Sprintf("bulk_u%d", i%8+1),
+        })
+        assert.Equal(t, http.StatusCreated, status)
+    }
+
+    status, response := suite.postJSON("/team/deactivateUsers", map[string]interface{}{
+        "team_name": "bulk_team",
+        "user_ids":  []string{"bulk_u1", "missing_user"},
+    })
+    assert.Equal(t, http.StatusNotFound, status)
+    assert.Equal(t, "NOT_FOUND", errorCode(response))
+
+    _, response = suite.getJSON("/team/get?team_name=bulk_team")
+    for _, m := range asSlice(response["members"]) {
+        assert.Equal(t, true, m.(map[string]interface{})["is_active"], "failed request must not deactivate anyone")
+    }
+
+    started := time.Now()
+    status, response = suite.postJSON("/team/deactivateUsers", map[string]interface{}{
+        "team_name": "bulk_team",
+        "user_ids":  []string{"bulk_u2", "bulk_u3", "bulk_u4"},
+    })
+    t.Logf("deactivateUsers for %d PRs took %v", prCount, time.Since(started))
+    assert.Equal(t, http.StatusOK, status)
+    assert.ElementsMatch(t, []interface{}{"bulk_u2", "bulk_u3", "bulk_u4"}, response["deactivated_users"])
+
+    leaving := map[interface{}]bool{"bulk_u2": true, "bulk_u3": true, "bulk_u4": true}
+    reports := asSlice(response["pull_requests"])
+    assert.NotEmpty(t, reports)
+    assert.Equal(t, float64(len(reports)), response["reassigned_prs"])
+    assert.Equal(t, float64(0), response["short_prs"])
+    for _, r := range reports {
+        report := r.(map[string]interface{})
+        assert.Equal(t, false, report["left_short"])
+        assigned := asSlice(report["assigned_reviewers"])
+        assert.Len(t, assigned, 2)
+        for _, reviewer := range assigned {
+            assert.False(t, leaving[reviewer], "deactivated reviewer %v still assigned", reviewer)
+        }
+        for _, rep := range asSlice(report["replacements"]) {
+            assert.True(t, leaving[rep.(map[string]interface{})["old_user_id"]])
+        }
+    }
+
+    for userID := range leaving {
+        _, response = suite.getJSON(fmt.Sprintf("/users/getReview?user_id=%s", userID))
+        assert.Empty(t, response["pull_requests"])
+    }
+}
+
+func (suite *IntegrationTestSuite) TestBulkDeactivationLeavesShortPRs() {
+    t := suite.T()
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "short_team",
+        "members": []map[string]interface{}{
+            {"user_id": "short_u1", "username": "Short Author", "is_active": true},
+            {"user_id": "short_u2", "username": "Short User 2", "is_active": true},
+            {"user_id": "short_u3", "username": "Short User 3", "is_active": true},
+        },
+    })
+    suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "short_pr_1",
+        "pull_request_name": "Short PR",
+        "author_id":         "short_u1",
+    })
+
+    status, response := suite.postJSON("/team/deactivateUsers", map[string]interface{}{
+        "team_name": "short_team",
+        "user_ids":  []string{"short_u2"},
+    })
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, float64(1), response["short_prs"])
+
+    reports := asSlice(response["pull_requests"])
+    if assert.Len(t, reports, 1) {
+        report := reports[0].(map[string]interface{})
+        assert.Equal(t, "short_pr_1", report["pull_request_id"])
+        assert.Equal(t, true, report["left_short"])
+        assert.Equal(t, []interface{}{"short_u2"}, report["unassigned"])
+        assert.Equal(t, []interface{}{"short_u3"}, report["assigned_reviewers"])
+    }
+}
+
+func (suite *IntegrationTestSuite) TestBulkDeactivationKeepsReviewerRules() {
+    t := suite.T()
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "bdr_team",
+        "members": []map[string]interface{}{
+            {"user_id": "bdr_a", "username": "Author", "is_active": true},
+            {"user_id": "bdr_s1", "username": "Senior 1", "is_active": true, "level": "senior"},
+            {"user_id": "bdr_s2", "username": "Senior 2", "is_active": true, "level": "senior"},
+            {"user_id": "bdr_m", "username": "Middle", "is_active": true},
+            {"user_id": "bdr_j1", "username": "Junior 1", "is_active": true, "level": "junior"},
+            {"user_id": "bdr_j2", "username": "Junior 2", "is_active": true, "level": "junior"},
+        },
+    })
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "bdr_db",
+        "members": []map[string]interface{}{
+            {"user_id": "bdr_da", "username": "DB author", "is_active": true},
+            {"user_id": "bdr_d1", "username": "DBA 1", "is_active": true},
+            {"user_id": "bdr_d2", "username": "DBA 2", "is_active": true},
+            {"user_id": "bdr_m1", "username": "Middle 1", "is_active": true},
+            {"user_id": "bdr_m2", "username": "Middle 2", "is_active": true},
+        },
+    })
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "bdr_small",
+        "members": []map[string]interface{}{
+            {"user_id": "bdr_sa", "username": "Small author", "is_active": true},
+            {"user_id": "bdr_x", "username": "Small senior", "is_active": true, "level": "senior"},
+        },
+    })
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "bdr_platform",
+        "members": []map[string]interface{}{
+            {"user_id": "bdr_pm", "username": "Platform middle", "is_active": true},
+            {"user_id": "bdr_ps", "username": "Platform senior", "is_active": true, "level": "senior"},
+        },
+    })
+    for _, userID := range []string{"bdr_d1", "bdr_d2"} {
+        suite.postJSON("/users/setSkills", map[string]interface{}{"user_id": userID, "skills": []string{"db"}})
+    }
+    for _, settings := range []map[string]interface{}{
+        {"team_name": "bdr_team", "reviewers_count": 1, "min_reviewer_level": "senior", "shadow_reviewers": 1},
+        {"team_name": "bdr_db", "reviewers_count": 1},
+        {"team_name": "bdr_small", "reviewers_count": 1, "min_reviewer_level": "senior", "fallback_teams": []string{"bdr_platform"}},
+    } {
+        status, _ := suite.postJSON("/team/settings", settings)
+        assert.Equal(t, http.StatusOK, status)
+    }
+
+    // роль ревьювера: shadow, fallback или regular
+    roles := func(prID string) map[string]string {
+        _, response := suite.getJSON("/pullRequest/get?pull_request_id=" + prID)
+        result := make(map[string]string)
+        for _, item := range asSlice(response["pr"].(map[string]interface{})["reviewers"]) {
+            reviewer := item.(map[string]interface{})
+            role := "regular"
+            if reviewer["shadow"] == true {
+                role = "shadow"
+            } else if reviewer["fallback"] == true {
+                role = "fallback"
+            }
+            result[reviewer["user_id"].(string)] = role
+        }
+        return result
+    }
+    // split возвращает того из пары, у кого роль role, и второго
+    split := func(roles map[string]string, role string, pair ...string) (string, string) {
+        for i, userID := range pair {
+            if roles[userID] == role {
+                return userID, pair[1-i]
+            }
+        }
+        return "", ""
+    }
+
+    for _, pr := range []map[string]interface{}{
+        {"pull_request_id": "bdr_pr_1", "pull_request_name": "Seniority", "author_id": "bdr_a"},
+        {"pull_request_id": "bdr_pr_2", "pull_request_name": "Migration", "author_id": "bdr_da", "labels": []string{"db"}},
+        {"pull_request_id": "bdr_pr_3", "pull_request_name": "Small team", "author_id": "bdr_sa"},
+    } {
+        status, _ := suite.postJSON("/pullRequest/create", pr)
+        assert.Equal(t, http.StatusCreated, status)
+    }
+
+    before := roles("bdr_pr_1")
+    assert.Len(t, before, 2)
+    senior, nextSenior := split(before, "regular", "bdr_s1", "bdr_s2")
+    shadow, nextShadow := split(before, "shadow", "bdr_j1", "bdr_j2")
+    assert.NotEmpty(t, senior)
+    assert.NotEmpty(t, shadow)
+    status, _ := suite.postJSON("/team/deactivateUsers", map[string]interface{}{
+        "team_name": "bdr_team",
+        "user_ids":  []string{senior, shadow},
+    })
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, map[string]string{nextSenior: "regular", nextShadow: "shadow"}, roles("bdr_pr_1"),
+        "the senior is replaced by a senior and the shadow by a junior shadow")
+
+    skilled, nextSkilled := split(roles("bdr_pr_2"), "regular", "bdr_d1", "bdr_d2")
+    assert.NotEmpty(t, skilled)
+    status, _ = suite.postJSON("/team/deactivateUsers", map[string]interface{}{"team_name": "bdr_db", "user_ids": []string{skilled}})
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, map[string]string{nextSkilled: "regular"}, roles("bdr_pr_2"), "the replacement covers the PR labels")
+
+    assert.Equal(t, map[string]string{"bdr_x": "regular"}, roles("bdr_pr_3"))
+    status, response := suite.postJSON("/team/deactivateUsers", map[string]interface{}{"team_name": "bdr_small", "user_ids": []string{"bdr_x"}})
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, float64(0), response["short_prs"])
+    assert.Equal(t, map[string]string{"bdr_ps": "fallback"}, roles("bdr_pr_3"), "a senior comes from the fallback team")
+}
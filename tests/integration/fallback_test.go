@@ -0,0 +1,110 @@
+package integration
+
+import (
+    "net/http"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func (suite *IntegrationTestSuite) TestFallbackTeams() {
+    t := suite.T()
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "fb_small",
+        "members": []map[string]interface{}{
+            {"user_id": "fb_a", "username": "Author", "is_active": true},
+            {"user_id": "fb_b", "username": "Teammate", "is_active": true},
+        },
+    })
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "fb_platform",
+        "members": []map[string]interface{}{
+            {"user_id": "fb_p1", "username": "Platform 1", "is_active": true},
+            {"user_id": "fb_p2", "username": "Platform 2", "is_active": true},
+        },
+    })
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "fb_infra",
+        "members": []map[string]interface{}{
+            {"user_id": "fb_i1", "username": "Infra 1", "is_active": true},
+        },
+    })
+    status, _ := suite.postJSON("/team/settings", map[string]interface{}{
+        "team_name":           "fb_small",
+        "reviewers_count":     3,
+        "min_reviewers_count": 3,
+    })
+    assert.Equal(t, http.StatusOK, status)
+
+    status, response := suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "fb_pr_0",
+        "pull_request_name": "Without fallback",
+        "author_id":         "fb_a",
+    })
+    assert.Equal(t, http.StatusConflict, status)
+    assert.Equal(t, "NO_CANDIDATE", errorCode(response))
+
+    for _, invalid := range [][]string{{"fb_small"}, {"fb_platform", "fb_platform"}} {
+        status, _ = suite.postJSON("/team/settings", map[string]interface{}{"team_name": "fb_small", "fallback_teams": invalid})
+        assert.Equal(t, http.StatusBadRequest, status)
+    }
+    status, _ = suite.postJSON("/team/settings", map[string]interface{}{"team_name": "fb_small", "fallback_teams": []string{"fb_nobody"}})
+    assert.Equal(t, http.StatusNotFound, status)
+
+    status, response = suite.postJSON("/team/settings", map[string]interface{}{
+        "team_name":      "fb_small",
+        "fallback_teams": []string{"fb_platform", "fb_infra"},
+    })
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, []interface{}{"fb_platform", "fb_infra"}, response["settings"].(map[string]interface{})["fallback_teams"])
+
+    fallbackOf := func(response map[string]interface{}) map[string]bool {
+        result := make(map[string]bool)
+        for _, item := range asSlice(response["pr"].(map[string]interface{})["reviewers"]) {
+            reviewer := item.(map[string]interface{})
+            result[reviewer["user_id"].(string)] = reviewer["fallback"] == true
+        }
+        return result
+    }
+
+    status, response = suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "fb_pr_1",
+        "pull_request_name": "First fallback team fills the slots",
+        "author_id":         "fb_a",
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    assert.Equal(t, map[string]bool{"fb_b": false, "fb_p1": true, "fb_p2": true}, fallbackOf(response))
+
+    suite.postJSON("/users/setIsActive", map[string]interface{}{"user_id": "fb_p1", "is_active": false})
+    status, response = suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "fb_pr_2",
+        "pull_request_name": "Fallback teams are used in order",
+        "author_id":         "fb_a",
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    assert.Equal(t, map[string]bool{"fb_b": false, "fb_p2": true, "fb_i1": true}, fallbackOf(response))
+
+    suite.postJSON("/users/setIsActive", map[string]interface{}{"user_id": "fb_p1", "is_active": true})
+    status, response = suite.postJSON("/pullRequest/reassign", map[string]interface{}{
+        "pull_request_id": "fb_pr_2",
+        "old_user_id":     "fb_b",
+    })
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, "fb_p1", response["replaced_by"], "nobody is left in the team, the replacement comes from a fallback team")
+    assert.Equal(t, map[string]bool{"fb_p1": true, "fb_p2": true, "fb_i1": true}, fallbackOf(response))
+
+    suite.postJSON("/pullRequest/close", map[string]interface{}{"pull_request_id": "fb_pr_1"})
+    status, response = suite.postJSON("/pullRequest/reopen", map[string]interface{}{"pull_request_id": "fb_pr_1"})
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, map[string]bool{"fb_b": false, "fb_p1": true, "fb_p2": true}, fallbackOf(response), "reopen keeps fallback reviewers")
+
+    status, _ = suite.postJSON("/team/rename", map[string]interface{}{"team_name": "fb_infra", "new_team_name": "fb_infra_renamed"})
+    assert.Equal(t, http.StatusOK, status)
+    status, response = suite.getJSON("/team/settings?team_name=fb_small")
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, []interface{}{"fb_platform", "fb_infra_renamed"}, response["settings"].(map[string]interface{})["fallback_teams"])
+
+    status, response = suite.postJSON("/team/settings", map[string]interface{}{"team_name": "fb_small", "fallback_teams": []string{}})
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, []interface{}{}, response["settings"].(map[string]interface{})["fallback_teams"])
+}
@@ -0,0 +1,105 @@
+package integration
+
+import (
+    "bytes"
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "net/http"
+    "os"
+    "path/filepath"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func (suite *IntegrationTestSuite) sendGitHubEvent(event, payloadFile, secret string) (int, map[string]interface{}) {
+    body, err := os.ReadFile(filepath.Join("testdata", "github", payloadFile))
+    if err != nil {
+        suite.T().Fatalf("failed to read payload %s: %v", payloadFile, err)
+    }
+
+    mac := hmac.New(sha256.New, []byte(secret))
+    mac.Write(body)
+
+    req, _ := http.NewRequest("POST", suite.baseURL+"/webhooks/github", bytes.NewBuffer(body))
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("X-GitHub-Event", event)
+    req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+    resp, err := suite.httpClient.Do(req)
+    if err != nil {
+        suite.T().Fatalf("webhook request failed: %v", err)
+    }
+    defer resp.Body.Close()
+
+    var response map[string]interface{}
+    json.NewDecoder(resp.Body).Decode(&response)
+    return resp.StatusCode, response
+}
+
+func (suite *IntegrationTestSuite) TestGitHubWebhook() {
+    t := suite.T()
+    secret := getEnv("GITHUB_WEBHOOK_SECRET", "test-secret")
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "github_team",
+        "members": []map[string]interface{}{
+            {"user_id": "gh_u1", "username": "GitHub Author", "is_active": true},
+            {"user_id": "gh_u2", "username": "GitHub User 2", "is_active": true},
+            {"user_id": "gh_u3", "username": "GitHub User 3", "is_active": true},
+        },
+    })
+    status, _ := suite.postJSON("/users/setGithubLogin", map[string]interface{}{
+        "user_id":      "gh_u1",
+        "github_login": "octo-author",
+    })
+    assert.Equal(t, http.StatusOK, status)
+
+    status, response := suite.sendGitHubEvent("pull_request", "pull_request_opened.json", "wrong-secret")
+    assert.Equal(t, http.StatusUnauthorized, status)
+    assert.Equal(t, "INVALID_SIGNATURE", errorCode(response))
+
+    status, response = suite.sendGitHubEvent("pull_request", "pull_request_opened.json", secret)
+    assert.Equal(t, http.StatusOK, status)
+    pr := response["pr"].(map[string]interface{})
+    assert.Equal(t, "acme/widgets#42", pr["pull_request_id"])
+    assert.Equal(t, "gh_u1", pr["author_id"])
+    assert.Len(t, pr["assigned_reviewers"], 2)
+
+    suite.postJSON("/team/settings", map[string]interface{}{"team_name": "github_team", "required_approvals": 1})
+    status, response = suite.sendGitHubEvent("pull_request", "pull_request_closed_merged.json", secret)
+    assert.Equal(t, http.StatusOK, status)
+    pr = response["pr"].(map[string]interface{})
+    assert.Equal(t, "MERGED", pr["status"], "the merge policy does not apply to merges made on GitHub")
+    assert.Equal(t, "github", pr["merged_by"])
+    assert.Nil(t, pr["force_merged"])
+
+    status, response = suite.sendGitHubEvent("pull_request", "pull_request_opened_draft.json", secret)
+    assert.Equal(t, http.StatusAccepted, status)
+    assert.Equal(t, "ignored", response["status"])
+
+    status, response = suite.sendGitHubEvent("pull_request", "pull_request_ready_for_review.json", secret)
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, "acme/widgets#43", response["pr"].(map[string]interface{})["pull_request_id"])
+
+    status, response = suite.sendGitHubEvent("pull_request", "pull_request_closed.json", secret)
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, "CLOSED", response["pr"].(map[string]interface{})["status"])
+
+    suite.postJSON("/users/setIsActive", map[string]interface{}{"user_id": "gh_u2", "is_active": false})
+    status, response = suite.sendGitHubEvent("pull_request", "pull_request_merged_after_close.json", secret)
+    assert.Equal(t, http.StatusOK, status)
+    pr = response["pr"].(map[string]interface{})
+    assert.Equal(t, "MERGED", pr["status"], "a closed PR merged on GitHub becomes merged at once")
+    assert.Equal(t, "github", pr["merged_by"])
+    assert.Nil(t, pr["force_merged"])
+    assert.Contains(t, asSlice(pr["assigned_reviewers"]), "gh_u2", "the PR is not reopened, so reviewers are not re-picked")
+
+    status, response = suite.getJSON("/pullRequest/history?pull_request_id=acme/widgets%2343")
+    assert.Equal(t, http.StatusOK, status)
+    for _, item := range asSlice(response["history"]) {
+        assert.NotEqual(t, "PR_REOPENED", item.(map[string]interface{})["reason"])
+    }
+    suite.postJSON("/users/setIsActive", map[string]interface{}{"user_id": "gh_u2", "is_active": true})
+}
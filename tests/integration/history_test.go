@@ -0,0 +1,82 @@
+package integration
+
+import (
+    "net/http"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func (suite *IntegrationTestSuite) TestReviewerHistory() {
+    t := suite.T()
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "hist_team",
+        "members": []map[string]interface{}{
+            {"user_id": "hist_u1", "username": "Author", "is_active": true},
+            {"user_id": "hist_u2", "username": "Reviewer 2", "is_active": true},
+            {"user_id": "hist_u3", "username": "Reviewer 3", "is_active": true},
+            {"user_id": "hist_u4", "username": "Reviewer 4", "is_active": true},
+        },
+    })
+
+    status, response := suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "hist_pr_1",
+        "pull_request_name": "History check",
+        "author_id":         "hist_u1",
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    reviewers := asSlice(response["pr"].(map[string]interface{})["assigned_reviewers"])
+    assert.Len(t, reviewers, 2)
+
+    first := reviewers[0].(string)
+    status, response = suite.postJSON("/pullRequest/reassign", map[string]interface{}{
+        "pull_request_id": "hist_pr_1",
+        "old_user_id":     first,
+    })
+    assert.Equal(t, http.StatusOK, status)
+    second := response["replaced_by"].(string)
+
+    status, _ = suite.postJSON("/team/deactivateUsers", map[string]interface{}{
+        "team_name": "hist_team",
+        "user_ids":  []string{second},
+    })
+    assert.Equal(t, http.StatusOK, status)
+
+    status, response = suite.getJSON("/pullRequest/history?pull_request_id=hist_pr_1")
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, "hist_pr_1", response["pull_request_id"])
+    assert.Equal(t, float64(2), response["reassignments"])
+
+    history := asSlice(response["history"])
+    if assert.Len(t, history, 4) {
+        for i, reviewerID := range reviewers {
+            entry := history[i].(map[string]interface{})
+            assert.Equal(t, "ASSIGNED", entry["action"])
+            assert.Equal(t, reviewerID, entry["reviewer_id"])
+            assert.Equal(t, "PR_CREATED", entry["reason"])
+            assert.Equal(t, "admin", entry["actor"])
+        }
+
+        manual := history[2].(map[string]interface{})
+        assert.Equal(t, "REPLACED", manual["action"])
+        assert.Equal(t, first, manual["reviewer_id"])
+        assert.Equal(t, second, manual["replaced_by"])
+        assert.Equal(t, "MANUAL_REASSIGN", manual["reason"])
+        assert.NotEmpty(t, manual["assigned_at"])
+
+        deactivated := history[3].(map[string]interface{})
+        assert.Equal(t, second, deactivated["reviewer_id"])
+        assert.Equal(t, "USER_DEACTIVATED", deactivated["reason"])
+        assert.Equal(t, "admin", deactivated["actor"])
+        if deactivated["action"] == "REPLACED" {
+            assert.NotEqual(t, second, deactivated["replaced_by"])
+        }
+    }
+
+    status, response = suite.getJSON("/pullRequest/history?pull_request_id=hist_missing")
+    assert.Equal(t, http.StatusNotFound, status)
+    assert.Equal(t, "NOT_FOUND", errorCode(response))
+
+    status, _ = suite.getJSON("/pullRequest/history")
+    assert.Equal(t, http.StatusBadRequest, status)
+}
@@ -17,6 +17,7 @@ type IntegrationTestSuite struct {
     suite.Suite
     baseURL    string
     httpClient *http.Client
+    adminToken string
     testTeam   string
 }
 
@@ -27,7 +28,11 @@ func TestIntegrationSuite(t *testing.T) {
 func (suite *IntegrationTestSuite) SetupSuite() {
     port := getEnv("PORT", "8080")
     suite.baseURL = fmt.Sprintf("http://localhost:%s", port)
-    suite.httpClient = &http.Client{Timeout: 10 * time.Second}
+    suite.adminToken = getEnv("ADMIN_TOKEN", "test-admin-token")
+    suite.httpClient = &http.Client{
+        Timeout:   10 * time.Second,
+        Transport: bearerTransport{token: suite.adminToken},
+    }
     suite.testTeam = "integration_team"
     
     suite.waitForService()
@@ -43,9 +48,22 @@ func getEnv(key, defaultValue string) string {
     return value
 }
 
+// bearerTransport подписывает запросы тестов токеном, если он не задан в самом запросе
+type bearerTransport struct {
+    token string
+}
+
+func (t bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+    if req.Header.Get("Authorization") == "" {
+        req = req.Clone(req.Context())
+        req.Header.Set("Authorization", "Bearer "+t.token)
+    }
+    return http.DefaultTransport.RoundTrip(req)
+}
+
 func (suite *IntegrationTestSuite) waitForService() {
     for i := 0; i < 30; i++ {
-        resp, err := http.Get(suite.baseURL + "/stats/system")
+        resp, err := suite.httpClient.Get(suite.baseURL + "/stats/system")
         if err == nil && resp.StatusCode == 200 {
             fmt.Println("✅ Service is ready for testing")
             return
@@ -375,4 +393,70 @@ func (suite *IntegrationTestSuite) TestStatistics() {
         assert.NoError(t, err)
         resp.Body.Close()
     }
-}
\ No newline at end of file
+}
+
+func (suite *IntegrationTestSuite) TestTeamSettings() {
+    t := suite.T()
+
+    teamData := map[string]interface{}{
+        "team_name": "settings_team",
+        "members": []map[string]interface{}{
+            {"user_id": "set_u1", "username": "Settings User 1", "is_active": true},
+            {"user_id": "set_u2", "username": "Settings User 2", "is_active": true},
+            {"user_id": "set_u3", "username": "Settings User 3", "is_active": true},
+        },
+    }
+    jsonData, _ := json.Marshal(teamData)
+    resp, err := suite.httpClient.Post(suite.baseURL+"/team/add", "application/json", bytes.NewBuffer(jsonData))
+    assert.NoError(t, err)
+    resp.Body.Close()
+
+    resp, err = suite.httpClient.Get(suite.baseURL + "/team/settings?team_name=settings_team")
+    assert.NoError(t, err)
+    assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+    var settingsResponse map[string]interface{}
+    json.NewDecoder(resp.Body).Decode(&settingsResponse)
+    resp.Body.Close()
+
+    settings := settingsResponse["settings"].(map[string]interface{})
+    assert.Equal(t, float64(2), settings["reviewers_count"])
+    assert.Equal(t, "least_loaded", settings["assignment_strategy"])
+
+    updateData := map[string]interface{}{
+        "team_name":       "settings_team",
+        "reviewers_count": 1,
+    }
+    jsonData, _ = json.Marshal(updateData)
+    resp, err = suite.httpClient.Post(suite.baseURL+"/team/settings", "application/json", bytes.NewBuffer(jsonData))
+    assert.NoError(t, err)
+    assert.Equal(t, http.StatusOK, resp.StatusCode)
+    resp.Body.Close()
+
+    prData := map[string]interface{}{
+        "pull_request_id":   "set_pr_1",
+        "pull_request_name": "Settings Test PR",
+        "author_id":         "set_u1",
+    }
+    jsonData, _ = json.Marshal(prData)
+    resp, err = suite.httpClient.Post(suite.baseURL+"/pullRequest/create", "application/json", bytes.NewBuffer(jsonData))
+    assert.NoError(t, err)
+    assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+    var prResponse map[string]interface{}
+    json.NewDecoder(resp.Body).Decode(&prResponse)
+    resp.Body.Close()
+
+    pr := prResponse["pr"].(map[string]interface{})
+    assert.Len(t, pr["assigned_reviewers"], 1)
+
+    invalidData := map[string]interface{}{
+        "team_name":           "settings_team",
+        "min_reviewers_count": 5,
+    }
+    jsonData, _ = json.Marshal(invalidData)
+    resp, err = suite.httpClient.Post(suite.baseURL+"/team/settings", "application/json", bytes.NewBuffer(jsonData))
+    assert.NoError(t, err)
+    assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+    resp.Body.Close()
+}
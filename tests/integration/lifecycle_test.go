@@ -0,0 +1,147 @@
+package integration
+
+import (
+    "net/http"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func (suite *IntegrationTestSuite) TestCloseReopenWorkflow() {
+    t := suite.T()
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "lifecycle_team",
+        "members": []map[string]interface{}{
+            {"user_id": "life_u1", "username": "Lifecycle Author", "is_active": true},
+            {"user_id": "life_u2", "username": "Lifecycle User 2", "is_active": true},
+            {"user_id": "life_u3", "username": "Lifecycle User 3", "is_active": true},
+            {"user_id": "life_u4", "username": "Lifecycle User 4", "is_active": true},
+        },
+    })
+
+    status, response := suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "life_pr_1",
+        "pull_request_name": "Lifecycle PR",
+        "author_id":         "life_u1",
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    reviewers := response["pr"].(map[string]interface{})["assigned_reviewers"].([]interface{})
+    assert.Len(t, reviewers, 2)
+    leaving := reviewers[0].(string)
+
+    status, response = suite.postJSON("/pullRequest/close", map[string]interface{}{"pull_request_id": "life_pr_1"})
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, "CLOSED", response["pr"].(map[string]interface{})["status"])
+
+    status, response = suite.getJSON("/users/getReview?user_id=" + leaving)
+    assert.Equal(t, http.StatusOK, status)
+    for _, pr := range asSlice(response["pull_requests"]) {
+        assert.NotEqual(t, "life_pr_1", pr.(map[string]interface{})["pull_request_id"])
+    }
+
+    status, response = suite.postJSON("/pullRequest/merge", map[string]interface{}{"pull_request_id": "life_pr_1"})
+    assert.Equal(t, http.StatusConflict, status)
+    assert.Equal(t, "PR_CLOSED", errorCode(response))
+
+    status, _ = suite.postJSON("/users/setIsActive", map[string]interface{}{"user_id": leaving, "is_active": false})
+    assert.Equal(t, http.StatusOK, status)
+
+    status, response = suite.postJSON("/pullRequest/reopen", map[string]interface{}{"pull_request_id": "life_pr_1"})
+    assert.Equal(t, http.StatusOK, status)
+    pr := response["pr"].(map[string]interface{})
+    assert.Equal(t, "OPEN", pr["status"])
+    reopened := pr["assigned_reviewers"].([]interface{})
+    assert.Len(t, reopened, 2)
+    assert.NotContains(t, reopened, leaving)
+
+    status, _ = suite.postJSON("/pullRequest/merge", map[string]interface{}{"pull_request_id": "life_pr_1"})
+    assert.Equal(t, http.StatusOK, status)
+
+    status, response = suite.postJSON("/pullRequest/close", map[string]interface{}{"pull_request_id": "life_pr_1"})
+    assert.Equal(t, http.StatusConflict, status)
+    assert.Equal(t, "PR_MERGED", errorCode(response))
+
+    // переоткрытие добирает ревьюверов по тем же правилам, что и замена
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "life_senior_team",
+        "members": []map[string]interface{}{
+            {"user_id": "life_sa", "username": "Senior team author", "is_active": true},
+            {"user_id": "life_s1", "username": "Senior 1", "is_active": true, "level": "senior"},
+            {"user_id": "life_s2", "username": "Senior 2", "is_active": true, "level": "senior"},
+            {"user_id": "life_m1", "username": "Middle 1", "is_active": true},
+            {"user_id": "life_m2", "username": "Middle 2", "is_active": true},
+        },
+    })
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "life_platform",
+        "members": []map[string]interface{}{
+            {"user_id": "life_ps", "username": "Platform senior", "is_active": true, "level": "senior"},
+        },
+    })
+    status, _ = suite.postJSON("/team/settings", map[string]interface{}{
+        "team_name":          "life_senior_team",
+        "reviewers_count":    1,
+        "min_reviewer_level": "senior",
+    })
+    assert.Equal(t, http.StatusOK, status)
+
+    status, response = suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "life_pr_2",
+        "pull_request_name": "Senior lifecycle PR",
+        "author_id":         "life_sa",
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    senior := asSlice(response["pr"].(map[string]interface{})["assigned_reviewers"])[0].(string)
+    otherSenior := map[string]string{"life_s1": "life_s2", "life_s2": "life_s1"}[senior]
+    assert.NotEmpty(t, otherSenior)
+
+    reopenWithout := func(userID string) (int, map[string]interface{}) {
+        suite.postJSON("/pullRequest/close", map[string]interface{}{"pull_request_id": "life_pr_2"})
+        suite.postJSON("/users/setIsActive", map[string]interface{}{"user_id": userID, "is_active": false})
+        return suite.postJSON("/pullRequest/reopen", map[string]interface{}{"pull_request_id": "life_pr_2"})
+    }
+
+    status, response = reopenWithout(senior)
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, []interface{}{otherSenior}, response["pr"].(map[string]interface{})["assigned_reviewers"],
+        "the removed senior is replaced by a senior")
+
+    status, response = reopenWithout(otherSenior)
+    assert.Equal(t, http.StatusConflict, status)
+    assert.Equal(t, "NO_CANDIDATE", errorCode(response))
+    assert.Equal(t, "senior", response["error"].(map[string]interface{})["details"].(map[string]interface{})["min_level"])
+    _, response = suite.getJSON("/pullRequest/get?pull_request_id=life_pr_2")
+    assert.Equal(t, "CLOSED", response["pr"].(map[string]interface{})["status"], "a failed reopen changes nothing")
+
+    status, _ = suite.postJSON("/team/settings", map[string]interface{}{
+        "team_name":      "life_senior_team",
+        "fallback_teams": []string{"life_platform"},
+    })
+    assert.Equal(t, http.StatusOK, status)
+    status, response = suite.postJSON("/pullRequest/reopen", map[string]interface{}{"pull_request_id": "life_pr_2"})
+    assert.Equal(t, http.StatusOK, status)
+    reviewers = asSlice(response["pr"].(map[string]interface{})["reviewers"])
+    assert.Len(t, reviewers, 1)
+    assert.Equal(t, "life_ps", reviewers[0].(map[string]interface{})["user_id"])
+    assert.Equal(t, true, reviewers[0].(map[string]interface{})["fallback"], "the senior comes from the fallback team")
+
+    status, _ = suite.postJSON("/team/settings", map[string]interface{}{
+        "team_name":           "life_senior_team",
+        "min_reviewer_level":  "",
+        "min_reviewers_count": 1,
+        "fallback_teams":      []string{},
+    })
+    assert.Equal(t, http.StatusOK, status)
+    suite.postJSON("/users/setIsActive", map[string]interface{}{"user_id": "life_m1", "is_active": false})
+    status, response = reopenWithout("life_m2")
+    assert.Equal(t, http.StatusConflict, status)
+    assert.Equal(t, "NO_CANDIDATE", errorCode(response))
+    details := response["error"].(map[string]interface{})["details"].(map[string]interface{})
+    assert.Equal(t, float64(1), details["required"])
+    assert.Equal(t, float64(0), details["found"])
+}
+
+func asSlice(value interface{}) []interface{} {
+    items, _ := value.([]interface{})
+    return items
+}
@@ -0,0 +1,91 @@
+package integration
+
+import (
+    "net/http"
+    "net/url"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func (suite *IntegrationTestSuite) TestPullRequestListing() {
+    t := suite.T()
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "list_team",
+        "members": []map[string]interface{}{
+            {"user_id": "list_u1", "username": "Author 1", "is_active": true},
+            {"user_id": "list_u2", "username": "Author 2", "is_active": true},
+            {"user_id": "list_u3", "username": "Reviewer", "is_active": true},
+        },
+    })
+    suite.postJSON("/team/settings", map[string]interface{}{"team_name": "list_team", "reviewers_count": 1})
+
+    for _, pr := range []struct{ id, author string }{
+        {"list_pr_1", "list_u1"},
+        {"list_pr_2", "list_u2"},
+        {"list_pr_3", "list_u1"},
+    } {
+        status, _ := suite.postJSON("/pullRequest/create", map[string]interface{}{
+            "pull_request_id":   pr.id,
+            "pull_request_name": "Listing check",
+            "author_id":         pr.author,
+        })
+        assert.Equal(t, http.StatusCreated, status)
+    }
+    suite.postJSON("/pullRequest/merge", map[string]interface{}{"pull_request_id": "list_pr_2"})
+
+    status, response := suite.getJSON("/pullRequest/get?pull_request_id=list_pr_2")
+    assert.Equal(t, http.StatusOK, status)
+    pr := response["pr"].(map[string]interface{})
+    assert.Equal(t, "list_pr_2", pr["pull_request_id"])
+    assert.Equal(t, "MERGED", pr["status"])
+    reviewerID := asSlice(pr["assigned_reviewers"])[0].(string)
+
+    status, response = suite.getJSON("/pullRequest/get?pull_request_id=list_missing")
+    assert.Equal(t, http.StatusNotFound, status)
+    assert.Equal(t, "NOT_FOUND", errorCode(response))
+
+    ids := func(response map[string]interface{}) []string {
+        result := []string{}
+        for _, p := range asSlice(response["pull_requests"]) {
+            result = append(result, p.(map[string]interface{})["pull_request_id"].(string))
+        }
+        return result
+    }
+
+    status, response = suite.getJSON("/pullRequest/list?team_name=list_team&limit=2")
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, []string{"list_pr_3", "list_pr_2"}, ids(response))
+    cursor, _ := response["next_cursor"].(string)
+    assert.NotEmpty(t, cursor)
+
+    status, response = suite.getJSON("/pullRequest/list?team_name=list_team&limit=2&cursor=" + url.QueryEscape(cursor))
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, []string{"list_pr_1"}, ids(response))
+    assert.Nil(t, response["next_cursor"])
+
+    _, response = suite.getJSON("/pullRequest/list?team_name=list_team&author_id=list_u1&status=OPEN")
+    assert.Equal(t, []string{"list_pr_3", "list_pr_1"}, ids(response))
+
+    _, response = suite.getJSON("/pullRequest/list?status=MERGED&reviewer_id=" + reviewerID)
+    assert.Equal(t, []string{"list_pr_2"}, ids(response))
+
+    since := url.QueryEscape(time.Now().Add(-time.Hour).Format(time.RFC3339))
+    _, response = suite.getJSON("/pullRequest/list?team_name=list_team&merged_from=" + since)
+    assert.Equal(t, []string{"list_pr_2"}, ids(response))
+
+    future := url.QueryEscape(time.Now().Add(time.Hour).Format(time.RFC3339))
+    _, response = suite.getJSON("/pullRequest/list?team_name=list_team&created_from=" + future)
+    assert.Empty(t, ids(response))
+
+    status, response = suite.getJSON("/pullRequest/list?status=DRAFT")
+    assert.Equal(t, http.StatusBadRequest, status)
+    assert.Equal(t, "INVALID_REQUEST", errorCode(response))
+
+    status, _ = suite.getJSON("/pullRequest/list?cursor=not-a-cursor")
+    assert.Equal(t, http.StatusBadRequest, status)
+
+    status, _ = suite.getJSON("/pullRequest/list?created_to=yesterday")
+    assert.Equal(t, http.StatusBadRequest, status)
+}
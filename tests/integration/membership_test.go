@@ -0,0 +1,148 @@
+package integration
+
+import (
+    "net/http"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func (suite *IntegrationTestSuite) TestTeamMembership() {
+    t := suite.T()
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "mem_alpha",
+        "members": []map[string]interface{}{
+            {"user_id": "mem_u1", "username": "Author", "is_active": true},
+            {"user_id": "mem_u2", "username": "Reviewer 2", "is_active": true},
+            {"user_id": "mem_u3", "username": "Reviewer 3", "is_active": true},
+        },
+    })
+    suite.postJSON("/team/settings", map[string]interface{}{"team_name": "mem_alpha", "reviewers_count": 1})
+
+    status, response := suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "mem_beta",
+        "members": []map[string]interface{}{
+            {"user_id": "mem_u2", "username": "Reviewer 2", "is_active": true},
+            {"user_id": "mem_u4", "username": "Newcomer", "is_active": true},
+        },
+    })
+    assert.Equal(t, http.StatusConflict, status)
+    assert.Equal(t, "USER_IN_OTHER_TEAM", errorCode(response))
+    details := response["error"].(map[string]interface{})["details"].(map[string]interface{})
+    assert.Equal(t, []interface{}{
+        map[string]interface{}{"user_id": "mem_u2", "team_name": "mem_alpha"},
+    }, details["conflicts"])
+
+    status, _ = suite.getJSON("/team/get?team_name=mem_beta")
+    assert.Equal(t, http.StatusNotFound, status, "rejected team must not be created")
+
+    status, _ = suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "mem_beta",
+        "members": []map[string]interface{}{
+            {"user_id": "mem_u4", "username": "Newcomer", "is_active": true},
+        },
+    })
+    assert.Equal(t, http.StatusCreated, status)
+
+    status, response = suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "mem_pr_1",
+        "pull_request_name": "Membership check",
+        "author_id":         "mem_u1",
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    reviewerID := asSlice(response["pr"].(map[string]interface{})["assigned_reviewers"])[0].(string)
+    otherID := "mem_u2"
+    if reviewerID == "mem_u2" {
+        otherID = "mem_u3"
+    }
+
+    addReviewer := map[string]interface{}{
+        "team_name": "mem_beta",
+        "members": []map[string]interface{}{
+            {"user_id": reviewerID, "username": "Mover", "is_active": true},
+            {"user_id": "mem_u5", "username": "Another newcomer", "is_active": true},
+        },
+    }
+    status, response = suite.postJSON("/team/addMembers", addReviewer)
+    assert.Equal(t, http.StatusConflict, status)
+    assert.Equal(t, "USER_IN_OTHER_TEAM", errorCode(response))
+
+    addReviewer["allow_move"] = true
+    status, response = suite.postJSON("/team/addMembers", addReviewer)
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, []interface{}{"mem_u5"}, response["added"])
+    assert.Equal(t, []interface{}{
+        map[string]interface{}{"user_id": reviewerID, "team_name": "mem_alpha"},
+    }, response["moved"])
+    assert.Equal(t, float64(1), response["reassigned_prs"])
+    prs := asSlice(response["pull_requests"])
+    if assert.Len(t, prs, 1) {
+        item := prs[0].(map[string]interface{})
+        assert.Equal(t, "mem_pr_1", item["pull_request_id"])
+        assert.Equal(t, []interface{}{otherID}, item["assigned_reviewers"])
+    }
+
+    status, response = suite.getJSON("/pullRequest/history?pull_request_id=mem_pr_1")
+    assert.Equal(t, http.StatusOK, status)
+    history := asSlice(response["history"])
+    last := history[len(history)-1].(map[string]interface{})
+    assert.Equal(t, "USER_MOVED", last["reason"])
+
+    status, response = suite.postJSON("/team/removeMembers", map[string]interface{}{
+        "team_name": "mem_alpha",
+        "user_ids":  []string{"mem_u1"},
+    })
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, []interface{}{"mem_u1"}, response["removed"])
+    assert.Equal(t, []interface{}{"mem_pr_1"}, response["authored_open_prs"])
+
+    status, response = suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "mem_pr_2",
+        "pull_request_name": "Author without team",
+        "author_id":         "mem_u1",
+    })
+    assert.Equal(t, http.StatusNotFound, status)
+
+    status, response = suite.postJSON("/team/removeMembers", map[string]interface{}{
+        "team_name": "mem_alpha",
+        "user_ids":  []string{"mem_u4"},
+    })
+    assert.Equal(t, http.StatusNotFound, status)
+
+    status, response = suite.postJSON("/team/rename", map[string]interface{}{
+        "team_name":     "mem_beta",
+        "new_team_name": "mem_alpha",
+    })
+    assert.Equal(t, http.StatusConflict, status)
+    assert.Equal(t, "TEAM_EXISTS", errorCode(response))
+
+    status, _ = suite.postJSON("/team/rename", map[string]interface{}{
+        "team_name":     "mem_beta",
+        "new_team_name": "mem_gamma",
+    })
+    assert.Equal(t, http.StatusOK, status)
+
+    status, response = suite.getJSON("/team/get?team_name=mem_gamma")
+    assert.Equal(t, http.StatusOK, status)
+    assert.Len(t, asSlice(response["members"]), 3)
+
+    status, response = suite.postJSON("/team/delete", map[string]interface{}{"team_name": "mem_gamma"})
+    assert.Equal(t, http.StatusConflict, status)
+    assert.Equal(t, "TEAM_NOT_EMPTY", errorCode(response))
+
+    status, response = suite.postJSON("/team/delete", map[string]interface{}{"team_name": "mem_gamma", "remove_members": true})
+    assert.Equal(t, http.StatusOK, status)
+    assert.Len(t, asSlice(response["removed"]), 3)
+
+    status, _ = suite.getJSON("/team/settings?team_name=mem_gamma")
+    assert.Equal(t, http.StatusNotFound, status)
+
+    status, response = suite.postJSON("/team/addMembers", map[string]interface{}{
+        "team_name": "mem_alpha",
+        "members": []map[string]interface{}{
+            {"user_id": "mem_u4", "username": "Newcomer", "is_active": true},
+        },
+    })
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, []interface{}{"mem_u4"}, response["added"], "users without a team join without allow_move")
+}
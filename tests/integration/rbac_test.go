@@ -0,0 +1,124 @@
+package integration
+
+import (
+    "net/http"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func (suite *IntegrationTestSuite) issueToken(name, scope, userID string) string {
+    status, response := suite.postJSON("/auth/tokens/add", map[string]interface{}{"name": name, "scope": scope, "user_id": userID})
+    if status != http.StatusCreated {
+        suite.T().Fatalf("issue %s token: status %d", scope, status)
+    }
+    return response["secret"].(string)
+}
+
+func (suite *IntegrationTestSuite) TestRoleBasedAccess() {
+    t := suite.T()
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "rbac_a",
+        "members": []map[string]interface{}{
+            {"user_id": "rbac_lead", "username": "Lead", "is_active": true},
+            {"user_id": "rbac_m1", "username": "Member 1", "is_active": true},
+            {"user_id": "rbac_m2", "username": "Member 2", "is_active": true},
+            {"user_id": "rbac_m3", "username": "Member 3", "is_active": true},
+        },
+    })
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "rbac_b",
+        "members": []map[string]interface{}{
+            {"user_id": "rbac_b1", "username": "Other 1", "is_active": true},
+            {"user_id": "rbac_b2", "username": "Other 2", "is_active": true},
+        },
+    })
+
+    status, response := suite.postJSON("/team/addLead", map[string]interface{}{"team_name": "rbac_a", "user_id": "rbac_lead"})
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, []interface{}{"rbac_lead"}, response["leads"])
+
+    lead := suite.issueToken("lead", "user", "rbac_lead")
+    member := suite.issueToken("member", "user", "rbac_m1")
+    bot := suite.issueToken("dashboard", "bot", "")
+
+    status, response = suite.requestWithToken(lead, http.MethodGet, "/auth/permissions", nil)
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, "team_lead", response["role"])
+    assert.Equal(t, []interface{}{"rbac_a"}, response["lead_teams"])
+
+    status, _ = suite.requestWithToken(lead, http.MethodPost, "/users/setMaxOpenReviews", map[string]interface{}{"user_id": "rbac_m3", "max_open_reviews": 5})
+    assert.Equal(t, http.StatusOK, status)
+
+    status, response = suite.requestWithToken(lead, http.MethodPost, "/users/setIsActive", map[string]interface{}{"user_id": "rbac_b1", "is_active": false})
+    assert.Equal(t, http.StatusForbidden, status)
+    assert.Equal(t, "FORBIDDEN", errorCode(response))
+
+    status, _ = suite.requestWithToken(lead, http.MethodPost, "/team/deactivateUsers", map[string]interface{}{"team_name": "rbac_b", "user_ids": []string{"rbac_b1"}})
+    assert.Equal(t, http.StatusForbidden, status)
+
+    status, _ = suite.requestWithToken(lead, http.MethodPost, "/team/addMembers", map[string]interface{}{
+        "team_name":  "rbac_a",
+        "members":    []map[string]interface{}{{"user_id": "rbac_b2", "username": "Other 2", "is_active": true}},
+        "allow_move": true,
+    })
+    assert.Equal(t, http.StatusForbidden, status, "a lead must not take members of another team")
+
+    status, _ = suite.requestWithToken(lead, http.MethodPost, "/team/delete", map[string]interface{}{"team_name": "rbac_a"})
+    assert.Equal(t, http.StatusForbidden, status, "deleting teams is left to org admins")
+
+    status, response = suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "rbac_pr_1",
+        "pull_request_name": "RBAC check",
+        "author_id":         "rbac_m1",
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    reviewerID := asSlice(response["pr"].(map[string]interface{})["assigned_reviewers"])[0].(string)
+    reassign := map[string]interface{}{"pull_request_id": "rbac_pr_1", "old_user_id": reviewerID}
+
+    status, _ = suite.requestWithToken(member, http.MethodPost, "/pullRequest/reassign", reassign)
+    assert.Equal(t, http.StatusForbidden, status)
+
+    status, _ = suite.requestWithToken(lead, http.MethodPost, "/pullRequest/reassign", reassign)
+    assert.Equal(t, http.StatusOK, status)
+
+    status, _ = suite.requestWithToken(member, http.MethodPost, "/users/setIsActive", map[string]interface{}{"user_id": "rbac_m1", "is_active": false})
+    assert.Equal(t, http.StatusForbidden, status)
+
+    status, _ = suite.requestWithToken(member, http.MethodGet, "/team/get?team_name=rbac_a", nil)
+    assert.Equal(t, http.StatusOK, status)
+
+    status, _ = suite.requestWithToken(member, http.MethodGet, "/team/get?team_name=rbac_b", nil)
+    assert.Equal(t, http.StatusForbidden, status)
+
+    status, response = suite.requestWithToken(member, http.MethodGet, "/auth/permissions", nil)
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, "member", response["role"])
+
+    status, _ = suite.requestWithToken(bot, http.MethodGet, "/team/get?team_name=rbac_b", nil)
+    assert.Equal(t, http.StatusOK, status)
+    status, _ = suite.requestWithToken(bot, http.MethodGet, "/stats/system", nil)
+    assert.Equal(t, http.StatusOK, status)
+    status, _ = suite.requestWithToken(bot, http.MethodPost, "/users/setIsActive", map[string]interface{}{"user_id": "rbac_m2", "is_active": false})
+    assert.Equal(t, http.StatusForbidden, status)
+    status, _ = suite.requestWithToken(bot, http.MethodPost, "/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "rbac_pr_2",
+        "pull_request_name": "Bot write",
+        "author_id":         "rbac_m1",
+    })
+    assert.Equal(t, http.StatusForbidden, status)
+
+    status, response = suite.getJSON("/auth/permissions?user_id=rbac_lead")
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, "team_lead", response["role"])
+
+    status, response = suite.postJSON("/team/removeLead", map[string]interface{}{"team_name": "rbac_a", "user_id": "rbac_lead"})
+    assert.Equal(t, http.StatusOK, status)
+    assert.Empty(t, response["leads"])
+
+    status, _ = suite.requestWithToken(lead, http.MethodPost, "/users/setMaxOpenReviews", map[string]interface{}{"user_id": "rbac_m3", "max_open_reviews": 5})
+    assert.Equal(t, http.StatusForbidden, status, "removing the lead takes effect immediately")
+
+    status, _ = suite.postJSON("/team/removeLead", map[string]interface{}{"team_name": "rbac_a", "user_id": "rbac_lead"})
+    assert.Equal(t, http.StatusNotFound, status)
+}
@@ -0,0 +1,173 @@
+package integration
+
+import (
+    "net/http"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func (suite *IntegrationTestSuite) TestRepositoryRouting() {
+    t := suite.T()
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "repo_owners",
+        "members": []map[string]interface{}{
+            {"user_id": "repo_o1", "username": "Owner 1", "is_active": true},
+            {"user_id": "repo_o2", "username": "Owner 2", "is_active": true},
+            {"user_id": "repo_o3", "username": "Owner 3", "is_active": true},
+        },
+    })
+    suite.postJSON("/team/settings", map[string]interface{}{"team_name": "repo_owners", "reviewers_count": 2, "required_approvals": 2})
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "repo_guests",
+        "members": []map[string]interface{}{
+            {"user_id": "repo_g1", "username": "Guest 1", "is_active": true},
+            {"user_id": "repo_g2", "username": "Guest 2", "is_active": true},
+        },
+    })
+    owners := []interface{}{"repo_o1", "repo_o2", "repo_o3"}
+
+    status, response := suite.postJSON("/repository/set", map[string]interface{}{
+        "repository": "acme/billing",
+        "team_name":  "repo_nobody",
+    })
+    assert.Equal(t, http.StatusNotFound, status)
+
+    status, _ = suite.postJSON("/repository/set", map[string]interface{}{
+        "repository":      "acme/billing",
+        "team_name":       "repo_owners",
+        "reviewers_count": -1,
+    })
+    assert.Equal(t, http.StatusBadRequest, status)
+
+    status, response = suite.postJSON("/repository/set", map[string]interface{}{
+        "repository":      "acme/billing",
+        "team_name":       "repo_owners",
+        "reviewers_count": 1,
+    })
+    assert.Equal(t, http.StatusOK, status)
+    repo := response["repository"].(map[string]interface{})
+    assert.Equal(t, float64(1), repo["reviewers_count"])
+    assert.Nil(t, repo["required_approvals"], "unset settings are inherited from the team")
+
+    status, response = suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "repo_pr_1",
+        "pull_request_name": "Own repository",
+        "author_id":         "repo_g1",
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    assert.Equal(t, []interface{}{"repo_g2"}, response["pr"].(map[string]interface{})["assigned_reviewers"])
+
+    status, response = suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "repo_pr_2",
+        "pull_request_name": "Cross-team contribution",
+        "author_id":         "repo_g1",
+        "repository":        "acme/billing",
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    pr := response["pr"].(map[string]interface{})
+    assert.Equal(t, "acme/billing", pr["repository"])
+    reviewers := asSlice(pr["assigned_reviewers"])
+    if assert.Len(t, reviewers, 1, "reviewers_count of the repository overrides the team") {
+        assert.Contains(t, owners, reviewers[0])
+    }
+
+    status, response = suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "acme/billing#5",
+        "pull_request_name": "Repository is not guessed from the id",
+        "author_id":         "repo_g1",
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    pr = response["pr"].(map[string]interface{})
+    assert.Nil(t, pr["repository"])
+    assert.Equal(t, []interface{}{"repo_g2"}, pr["assigned_reviewers"], "without repository the author's team reviews")
+
+    status, response = suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "acme/billing#3",
+        "pull_request_name": "Repository",
+        "author_id":         "repo_g1",
+        "repository":        "acme/billing",
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    assert.Subset(t, owners, asSlice(response["pr"].(map[string]interface{})["assigned_reviewers"]))
+
+    status, response = suite.postJSON("/pullRequest/reassign", map[string]interface{}{
+        "pull_request_id": "repo_pr_2",
+        "old_user_id":     reviewers[0],
+    })
+    assert.Equal(t, http.StatusOK, status)
+    replacedBy := response["replaced_by"]
+    assert.Contains(t, owners, replacedBy, "replacements come from the owning team")
+
+    suite.postJSON("/pullRequest/review", map[string]interface{}{
+        "pull_request_id": "repo_pr_2",
+        "reviewer_id":     replacedBy,
+        "decision":        "APPROVED",
+    })
+    status, response = suite.postJSON("/pullRequest/merge", map[string]interface{}{"pull_request_id": "repo_pr_2"})
+    assert.Equal(t, http.StatusConflict, status, "required_approvals is inherited from the owning team")
+    assert.Equal(t, "NOT_APPROVED", errorCode(response))
+
+    suite.postJSON("/team/addLead", map[string]interface{}{"team_name": "repo_owners", "user_id": "repo_o1"})
+    suite.postJSON("/team/addLead", map[string]interface{}{"team_name": "repo_guests", "user_id": "repo_g1"})
+    ownersLead := suite.issueToken("repo owners lead", "user", "repo_o1")
+    guestsLead := suite.issueToken("repo guests lead", "user", "repo_g1")
+
+    status, _ = suite.requestWithToken(ownersLead, http.MethodPost, "/repository/set", map[string]interface{}{
+        "repository":         "acme/billing",
+        "team_name":          "repo_owners",
+        "reviewers_count":    1,
+        "required_approvals": 1,
+    })
+    assert.Equal(t, http.StatusOK, status, "leads change settings of their repositories")
+
+    status, response = suite.postJSON("/pullRequest/merge", map[string]interface{}{"pull_request_id": "repo_pr_2"})
+    assert.Equal(t, http.StatusOK, status)
+
+    status, _ = suite.requestWithToken(ownersLead, http.MethodPost, "/repository/set", map[string]interface{}{
+        "repository": "acme/billing",
+        "team_name":  "repo_guests",
+    })
+    assert.Equal(t, http.StatusForbidden, status, "only admins give repositories away")
+
+    status, response = suite.getJSON("/pullRequest/get?pull_request_id=acme/billing%233")
+    assert.Equal(t, http.StatusOK, status)
+    reviewerID := asSlice(response["pr"].(map[string]interface{})["assigned_reviewers"])[0]
+    status, _ = suite.requestWithToken(guestsLead, http.MethodPost, "/pullRequest/reassign", map[string]interface{}{
+        "pull_request_id": "acme/billing#3",
+        "old_user_id":     reviewerID,
+    })
+    assert.Equal(t, http.StatusForbidden, status, "the author's lead does not manage reviews of another team's repository")
+
+    status, _ = suite.requestWithToken(ownersLead, http.MethodPost, "/pullRequest/reassign", map[string]interface{}{
+        "pull_request_id": "acme/billing#3",
+        "old_user_id":     reviewerID,
+    })
+    assert.Equal(t, http.StatusOK, status)
+
+    suite.postJSON("/team/removeMembers", map[string]interface{}{"team_name": "repo_guests", "user_ids": []string{"repo_g2"}})
+    status, _ = suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "repo_pr_4",
+        "pull_request_name": "Author without team",
+        "author_id":         "repo_g2",
+    })
+    assert.Equal(t, http.StatusNotFound, status)
+    status, _ = suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "repo_pr_4",
+        "pull_request_name": "Author without team",
+        "author_id":         "repo_g2",
+        "repository":        "acme/billing",
+    })
+    assert.Equal(t, http.StatusCreated, status, "registered repositories accept authors without a team")
+
+    status, response = suite.getJSON("/repository/list?team_name=repo_owners")
+    assert.Equal(t, http.StatusOK, status)
+    assert.Len(t, asSlice(response["repositories"]), 1)
+
+    status, _ = suite.postJSON("/repository/delete", map[string]interface{}{"repository": "acme/billing"})
+    assert.Equal(t, http.StatusNoContent, status)
+    status, _ = suite.getJSON("/repository/get?repository=acme/billing")
+    assert.Equal(t, http.StatusNotFound, status)
+    status, _ = suite.postJSON("/repository/delete", map[string]interface{}{"repository": "acme/billing"})
+    assert.Equal(t, http.StatusNotFound, status)
+}
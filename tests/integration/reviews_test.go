@@ -0,0 +1,206 @@
+package integration
+
+import (
+    "bytes"
+    "encoding/json"
+    "net/http"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func (suite *IntegrationTestSuite) postJSON(path string, data map[string]interface{}) (int, map[string]interface{}) {
+    jsonData, _ := json.Marshal(data)
+    resp, err := suite.httpClient.Post(suite.baseURL+path, "application/json", bytes.NewBuffer(jsonData))
+    if err != nil {
+        suite.T().Fatalf("POST %s failed: %v", path, err)
+    }
+    defer resp.Body.Close()
+
+    var response map[string]interface{}
+    json.NewDecoder(resp.Body).Decode(&response)
+    return resp.StatusCode, response
+}
+
+func (suite *IntegrationTestSuite) getJSON(path string) (int, map[string]interface{}) {
+    resp, err := suite.httpClient.Get(suite.baseURL + path)
+    if err != nil {
+        suite.T().Fatalf("GET %s failed: %v", path, err)
+    }
+    defer resp.Body.Close()
+
+    var response map[string]interface{}
+    json.NewDecoder(resp.Body).Decode(&response)
+    return resp.StatusCode, response
+}
+
+func errorCode(response map[string]interface{}) interface{} {
+    errorBody, ok := response["error"].(map[string]interface{})
+    if !ok {
+        return nil
+    }
+    return errorBody["code"]
+}
+
+func (suite *IntegrationTestSuite) TestReviewDecisions() {
+    t := suite.T()
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "review_team",
+        "members": []map[string]interface{}{
+            {"user_id": "rev_u1", "username": "Review Author", "is_active": true},
+            {"user_id": "rev_u2", "username": "Review User 2", "is_active": true},
+            {"user_id": "rev_u3", "username": "Review User 3", "is_active": true},
+        },
+    })
+
+    status, response := suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "rev_pr_1",
+        "pull_request_name": "Review Decisions PR",
+        "author_id":         "rev_u1",
+    })
+    assert.Equal(t, http.StatusCreated, status)
+
+    pr := response["pr"].(map[string]interface{})
+    reviewers := pr["reviewers"].([]interface{})
+    assert.Len(t, reviewers, 2)
+    for _, reviewer := range reviewers {
+        assert.Equal(t, "PENDING", reviewer.(map[string]interface{})["state"])
+    }
+
+    status, response = suite.postJSON("/pullRequest/review", map[string]interface{}{
+        "pull_request_id": "rev_pr_1",
+        "reviewer_id":     "rev_u2",
+        "decision":        "APPROVED",
+    })
+    assert.Equal(t, http.StatusOK, status)
+
+    pr = response["pr"].(map[string]interface{})
+    for _, reviewer := range pr["reviewers"].([]interface{}) {
+        r := reviewer.(map[string]interface{})
+        if r["user_id"] == "rev_u2" {
+            assert.Equal(t, "APPROVED", r["state"])
+            assert.NotEmpty(t, r["reviewed_at"])
+        }
+    }
+
+    status, response = suite.postJSON("/pullRequest/review", map[string]interface{}{
+        "pull_request_id": "rev_pr_1",
+        "reviewer_id":     "rev_u1",
+        "decision":        "APPROVED",
+    })
+    assert.Equal(t, http.StatusConflict, status)
+    assert.Equal(t, "NOT_ASSIGNED", errorCode(response))
+
+    status, response = suite.postJSON("/pullRequest/review", map[string]interface{}{
+        "pull_request_id": "rev_pr_1",
+        "reviewer_id":     "rev_u2",
+        "decision":        "LGTM",
+    })
+    assert.Equal(t, http.StatusBadRequest, status)
+    assert.Equal(t, "INVALID_REQUEST", errorCode(response))
+}
+
+func (suite *IntegrationTestSuite) TestMergeGating() {
+    t := suite.T()
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "gate_team",
+        "members": []map[string]interface{}{
+            {"user_id": "gate_u1", "username": "Gate Author", "is_active": true},
+            {"user_id": "gate_u2", "username": "Gate User 2", "is_active": true},
+            {"user_id": "gate_u3", "username": "Gate User 3", "is_active": true},
+        },
+    })
+    status, _ := suite.postJSON("/team/settings", map[string]interface{}{
+        "team_name":          "gate_team",
+        "required_approvals": 2,
+    })
+    assert.Equal(t, http.StatusOK, status)
+
+    for _, prID := range []string{"gate_pr_1", "gate_pr_2"} {
+        status, _ = suite.postJSON("/pullRequest/create", map[string]interface{}{
+            "pull_request_id":   prID,
+            "pull_request_name": "Merge Gating PR",
+            "author_id":         "gate_u1",
+        })
+        assert.Equal(t, http.StatusCreated, status)
+    }
+
+    status, response := suite.postJSON("/pullRequest/merge", map[string]interface{}{"pull_request_id": "gate_pr_1"})
+    assert.Equal(t, http.StatusConflict, status)
+    assert.Equal(t, "NOT_APPROVED", errorCode(response))
+    details := response["error"].(map[string]interface{})["details"].(map[string]interface{})
+    assert.Len(t, details["missing_approvals"], 2)
+
+    suite.postJSON("/pullRequest/review", map[string]interface{}{
+        "pull_request_id": "gate_pr_1", "reviewer_id": "gate_u2", "decision": "APPROVED",
+    })
+    suite.postJSON("/pullRequest/review", map[string]interface{}{
+        "pull_request_id": "gate_pr_1", "reviewer_id": "gate_u3", "decision": "CHANGES_REQUESTED",
+    })
+
+    status, response = suite.postJSON("/pullRequest/merge", map[string]interface{}{"pull_request_id": "gate_pr_1"})
+    assert.Equal(t, http.StatusConflict, status)
+    details = response["error"].(map[string]interface{})["details"].(map[string]interface{})
+    assert.Equal(t, []interface{}{"gate_u3"}, details["changes_requested_by"])
+
+    suite.postJSON("/pullRequest/review", map[string]interface{}{
+        "pull_request_id": "gate_pr_1", "reviewer_id": "gate_u3", "decision": "APPROVED",
+    })
+
+    status, response = suite.postJSON("/pullRequest/merge", map[string]interface{}{"pull_request_id": "gate_pr_1"})
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, "MERGED", response["pr"].(map[string]interface{})["status"])
+    assert.Nil(t, response["pr"].(map[string]interface{})["merged_by"], "only forced merges record who merged")
+
+    status, response = suite.postJSON("/pullRequest/merge", map[string]interface{}{"pull_request_id": "gate_pr_2", "force": true})
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, true, response["pr"].(map[string]interface{})["force_merged"])
+    assert.Equal(t, "admin", response["pr"].(map[string]interface{})["merged_by"], "the forced merge records the admin")
+}
+
+func (suite *IntegrationTestSuite) TestReassignResetsReviewDecision() {
+    t := suite.T()
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "reset_team",
+        "members": []map[string]interface{}{
+            {"user_id": "reset_u1", "username": "Reset Author", "is_active": true},
+            {"user_id": "reset_u2", "username": "Reset User 2", "is_active": true},
+            {"user_id": "reset_u3", "username": "Reset User 3", "is_active": true},
+        },
+    })
+    suite.postJSON("/team/settings", map[string]interface{}{
+        "team_name":          "reset_team",
+        "reviewers_count":    1,
+        "required_approvals": 1,
+    })
+
+    _, response := suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "reset_pr_1",
+        "pull_request_name": "Reset PR",
+        "author_id":         "reset_u1",
+    })
+    oldReviewer := asSlice(response["pr"].(map[string]interface{})["assigned_reviewers"])[0].(string)
+    status, _ := suite.postJSON("/pullRequest/review", map[string]interface{}{
+        "pull_request_id": "reset_pr_1", "reviewer_id": oldReviewer, "decision": "APPROVED",
+    })
+    assert.Equal(t, http.StatusOK, status)
+
+    status, response = suite.postJSON("/pullRequest/reassign", map[string]interface{}{
+        "pull_request_id": "reset_pr_1",
+        "old_user_id":     oldReviewer,
+    })
+    assert.Equal(t, http.StatusOK, status)
+    reviewers := asSlice(response["pr"].(map[string]interface{})["reviewers"])
+    if assert.Len(t, reviewers, 1) {
+        reviewer := reviewers[0].(map[string]interface{})
+        assert.NotEqual(t, oldReviewer, reviewer["user_id"])
+        assert.Equal(t, "PENDING", reviewer["state"], "the replacement does not inherit the old decision")
+        assert.Nil(t, reviewer["reviewed_at"])
+    }
+
+    status, response = suite.postJSON("/pullRequest/merge", map[string]interface{}{"pull_request_id": "reset_pr_1"})
+    assert.Equal(t, http.StatusConflict, status)
+    assert.Equal(t, "NOT_APPROVED", errorCode(response))
+}
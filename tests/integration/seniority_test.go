@@ -0,0 +1,224 @@
+package integration
+
+import (
+    "net/http"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func (suite *IntegrationTestSuite) TestSeniorityRule() {
+    t := suite.T()
+
+    status, _ := suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "sen_invalid",
+        "members": []map[string]interface{}{
+            {"user_id": "sen_x", "username": "Unknown level", "is_active": true, "level": "guru"},
+        },
+    })
+    assert.Equal(t, http.StatusBadRequest, status)
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "sen_team",
+        "members": []map[string]interface{}{
+            {"user_id": "sen_a", "username": "Author", "is_active": true},
+            {"user_id": "sen_s", "username": "Senior", "is_active": true, "level": "senior"},
+            {"user_id": "sen_m", "username": "Middle", "is_active": true},
+            {"user_id": "sen_j1", "username": "Junior 1", "is_active": true, "level": "junior"},
+            {"user_id": "sen_j2", "username": "Junior 2", "is_active": true, "level": "junior"},
+        },
+    })
+    status, response := suite.getJSON("/team/get?team_name=sen_team")
+    assert.Equal(t, http.StatusOK, status)
+    levels := make(map[string]interface{})
+    for _, item := range asSlice(response["members"]) {
+        member := item.(map[string]interface{})
+        levels[member["user_id"].(string)] = member["level"]
+    }
+    assert.Equal(t, map[string]interface{}{"sen_a": "middle", "sen_s": "senior", "sen_m": "middle", "sen_j1": "junior", "sen_j2": "junior"}, levels)
+
+    for _, invalid := range []map[string]interface{}{{"min_reviewer_level": "guru"}, {"shadow_reviewers": -1}} {
+        invalid["team_name"] = "sen_team"
+        status, _ = suite.postJSON("/team/settings", invalid)
+        assert.Equal(t, http.StatusBadRequest, status)
+    }
+    status, response = suite.postJSON("/team/settings", map[string]interface{}{
+        "team_name":          "sen_team",
+        "reviewers_count":    1,
+        "required_approvals": 1,
+        "min_reviewer_level": "senior",
+        "shadow_reviewers":   1,
+    })
+    assert.Equal(t, http.StatusOK, status)
+    settings := response["settings"].(map[string]interface{})
+    assert.Equal(t, "senior", settings["min_reviewer_level"])
+    assert.Equal(t, float64(1), settings["shadow_reviewers"])
+
+    roles := func(response map[string]interface{}) map[string]bool {
+        result := make(map[string]bool)
+        for _, item := range asSlice(response["pr"].(map[string]interface{})["reviewers"]) {
+            reviewer := item.(map[string]interface{})
+            result[reviewer["user_id"].(string)] = reviewer["shadow"] == true
+        }
+        return result
+    }
+
+    status, response = suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "sen_pr_1",
+        "pull_request_name": "Onboarding review",
+        "author_id":         "sen_a",
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    assigned := roles(response)
+    assert.Len(t, assigned, 2)
+    assert.Equal(t, false, assigned["sen_s"], "the only senior is the regular reviewer")
+    var shadow string
+    for userID, isShadow := range assigned {
+        if isShadow {
+            shadow = userID
+        }
+    }
+    assert.Contains(t, []string{"sen_j1", "sen_j2"}, shadow, "a junior shadows the review")
+
+    status, response = suite.postJSON("/pullRequest/reassign", map[string]interface{}{
+        "pull_request_id": "sen_pr_1",
+        "old_user_id":     shadow,
+    })
+    assert.Equal(t, http.StatusOK, status)
+    newShadow := response["replaced_by"].(string)
+    assert.Contains(t, []string{"sen_j1", "sen_j2"}, newShadow)
+    assert.NotEqual(t, shadow, newShadow)
+    assert.Equal(t, true, roles(response)[newShadow], "a shadow is replaced by another junior")
+
+    suite.postJSON("/pullRequest/review", map[string]interface{}{"pull_request_id": "sen_pr_1", "reviewer_id": newShadow, "decision": "APPROVED"})
+    status, response = suite.postJSON("/pullRequest/merge", map[string]interface{}{"pull_request_id": "sen_pr_1"})
+    assert.Equal(t, http.StatusConflict, status, "shadow approvals do not count")
+    assert.Equal(t, "NOT_APPROVED", errorCode(response))
+
+    suite.postJSON("/pullRequest/review", map[string]interface{}{"pull_request_id": "sen_pr_1", "reviewer_id": newShadow, "decision": "CHANGES_REQUESTED"})
+    suite.postJSON("/pullRequest/review", map[string]interface{}{"pull_request_id": "sen_pr_1", "reviewer_id": "sen_s", "decision": "APPROVED"})
+    status, _ = suite.postJSON("/pullRequest/merge", map[string]interface{}{"pull_request_id": "sen_pr_1"})
+    assert.Equal(t, http.StatusOK, status, "shadow change requests do not block the merge")
+
+    suite.postJSON("/users/setIsActive", map[string]interface{}{"user_id": "sen_s", "is_active": false})
+    status, response = suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "sen_pr_2",
+        "pull_request_name": "Without seniors",
+        "author_id":         "sen_a",
+    })
+    assert.Equal(t, http.StatusConflict, status)
+    assert.Equal(t, "NO_CANDIDATE", errorCode(response))
+    assert.Equal(t, "senior", response["error"].(map[string]interface{})["details"].(map[string]interface{})["min_level"])
+
+    status, _ = suite.postJSON("/users/setLevel", map[string]interface{}{"user_id": "sen_m", "level": "guru"})
+    assert.Equal(t, http.StatusBadRequest, status)
+    status, _ = suite.postJSON("/users/setLevel", map[string]interface{}{"user_id": "sen_nobody", "level": "senior"})
+    assert.Equal(t, http.StatusNotFound, status)
+    status, response = suite.postJSON("/users/setLevel", map[string]interface{}{"user_id": "sen_m", "level": "senior"})
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, "senior", response["user"].(map[string]interface{})["level"])
+
+    status, response = suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "sen_pr_2",
+        "pull_request_name": "Promoted senior",
+        "author_id":         "sen_a",
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    assert.Equal(t, false, roles(response)["sen_m"])
+
+    suite.postJSON("/users/setIsActive", map[string]interface{}{"user_id": "sen_s", "is_active": true})
+    status, response = suite.postJSON("/pullRequest/reassign", map[string]interface{}{
+        "pull_request_id": "sen_pr_2",
+        "old_user_id":     "sen_m",
+    })
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, "sen_s", response["replaced_by"], "the replacement keeps a senior on the review")
+}
+
+func (suite *IntegrationTestSuite) TestSeniorityRuleWithFallbackTeams() {
+    t := suite.T()
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "senfb_juniors",
+        "members": []map[string]interface{}{
+            {"user_id": "senfb_a", "username": "Author", "is_active": true},
+            {"user_id": "senfb_j", "username": "Junior", "is_active": true, "level": "junior"},
+        },
+    })
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "senfb_trainees",
+        "members": []map[string]interface{}{
+            {"user_id": "senfb_t1", "username": "Trainee 1", "is_active": true, "level": "junior"},
+            {"user_id": "senfb_t2", "username": "Trainee 2", "is_active": true, "level": "junior"},
+        },
+    })
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "senfb_platform",
+        "members": []map[string]interface{}{
+            {"user_id": "senfb_pm", "username": "Platform middle", "is_active": true},
+            {"user_id": "senfb_ps", "username": "Platform senior", "is_active": true, "level": "senior"},
+        },
+    })
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "senfb_solo",
+        "members": []map[string]interface{}{
+            {"user_id": "senfb_sa", "username": "Solo author", "is_active": true},
+        },
+    })
+
+    reviewers := func(response map[string]interface{}) map[string]bool {
+        result := make(map[string]bool)
+        for _, item := range asSlice(response["pr"].(map[string]interface{})["reviewers"]) {
+            reviewer := item.(map[string]interface{})
+            result[reviewer["user_id"].(string)] = reviewer["fallback"] == true
+        }
+        return result
+    }
+
+    status, _ := suite.postJSON("/team/settings", map[string]interface{}{
+        "team_name":          "senfb_juniors",
+        "reviewers_count":    2,
+        "min_reviewer_level": "senior",
+        "fallback_teams":     []string{"senfb_platform"},
+    })
+    assert.Equal(t, http.StatusOK, status)
+    status, response := suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "senfb_pr_1",
+        "pull_request_name": "Senior from the fallback team",
+        "author_id":         "senfb_a",
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    assert.Equal(t, map[string]bool{"senfb_j": false, "senfb_ps": true}, reviewers(response))
+
+    status, _ = suite.postJSON("/team/settings", map[string]interface{}{
+        "team_name":          "senfb_solo",
+        "reviewers_count":    2,
+        "min_reviewer_level": "senior",
+        "fallback_teams":     []string{"senfb_trainees"},
+    })
+    assert.Equal(t, http.StatusOK, status)
+    status, response = suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "senfb_pr_2",
+        "pull_request_name": "Only junior fallbacks",
+        "author_id":         "senfb_sa",
+    })
+    assert.Equal(t, http.StatusConflict, status)
+    assert.Equal(t, "NO_CANDIDATE", errorCode(response))
+    details := response["error"].(map[string]interface{})["details"].(map[string]interface{})
+    assert.Equal(t, "senior", details["min_level"])
+    assert.Equal(t, float64(2), details["found"])
+
+    status, _ = suite.postJSON("/team/settings", map[string]interface{}{
+        "team_name":      "senfb_solo",
+        "fallback_teams": []string{"senfb_trainees", "senfb_platform"},
+    })
+    assert.Equal(t, http.StatusOK, status)
+    status, response = suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "senfb_pr_2",
+        "pull_request_name": "Senior replaces a junior fallback",
+        "author_id":         "senfb_sa",
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    assigned := reviewers(response)
+    assert.Len(t, assigned, 2)
+    assert.Equal(t, true, assigned["senfb_ps"], "the senior comes from the second fallback team")
+}
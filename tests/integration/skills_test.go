@@ -0,0 +1,80 @@
+package integration
+
+import (
+    "net/http"
+    "strings"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func (suite *IntegrationTestSuite) TestSkillsAndLabels() {
+    t := suite.T()
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "sk_team",
+        "members": []map[string]interface{}{
+            {"user_id": "sk_a", "username": "Author", "is_active": true},
+            {"user_id": "sk_b", "username": "Generalist 1", "is_active": true},
+            {"user_id": "sk_c", "username": "Generalist 2", "is_active": true},
+            {"user_id": "sk_d", "username": "DBA", "is_active": true},
+            {"user_id": "sk_e", "username": "Security", "is_active": true},
+        },
+    })
+    suite.postJSON("/team/settings", map[string]interface{}{"team_name": "sk_team", "reviewers_count": 1})
+
+    status, _ := suite.postJSON("/users/setSkills", map[string]interface{}{"user_id": "sk_nobody", "skills": []string{"db"}})
+    assert.Equal(t, http.StatusNotFound, status)
+    status, _ = suite.postJSON("/users/setSkills", map[string]interface{}{"user_id": "sk_d", "skills": []string{strings.Repeat("x", 101)}})
+    assert.Equal(t, http.StatusBadRequest, status)
+
+    status, response := suite.postJSON("/users/setSkills", map[string]interface{}{
+        "user_id": "sk_d",
+        "skills":  []string{" Postgres", "db", "DB", ""},
+    })
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, []interface{}{"db", "postgres"}, response["skills"], "skills are normalized")
+    suite.postJSON("/users/setSkills", map[string]interface{}{"user_id": "sk_e", "skills": []string{"security"}})
+
+    status, response = suite.getJSON("/users/skills?user_id=sk_b")
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, []interface{}{}, response["skills"])
+
+    for i, id := range []string{"sk_pr_1", "sk_pr_2", "sk_pr_3"} {
+        status, response = suite.postJSON("/pullRequest/create", map[string]interface{}{
+            "pull_request_id":   id,
+            "pull_request_name": "Migration",
+            "author_id":         "sk_a",
+            "labels":            []string{"DB"},
+        })
+        if !assert.Equal(t, http.StatusCreated, status, i) {
+            continue
+        }
+        pr := response["pr"].(map[string]interface{})
+        assert.Equal(t, []interface{}{"db"}, pr["labels"])
+        assert.Contains(t, asSlice(pr["assigned_reviewers"]), "sk_d", "a reviewer with a matching skill is always assigned")
+    }
+
+    suite.postJSON("/users/setSkills", map[string]interface{}{"user_id": "sk_b", "skills": []string{"db"}})
+    status, response = suite.postJSON("/pullRequest/reassign", map[string]interface{}{
+        "pull_request_id": "sk_pr_1",
+        "old_user_id":     "sk_d",
+    })
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, "sk_b", response["replaced_by"], "the replacement keeps the labels covered")
+
+    status, response = suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "sk_pr_4",
+        "pull_request_name": "Without labels",
+        "author_id":         "sk_a",
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    assert.Nil(t, response["pr"].(map[string]interface{})["labels"])
+
+    status, _ = suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "sk_pr_5",
+        "pull_request_name": "Long label",
+        "author_id":         "sk_a",
+        "labels":            []string{strings.Repeat("x", 101)},
+    })
+    assert.Equal(t, http.StatusBadRequest, status)
+}
@@ -0,0 +1,81 @@
+package integration
+
+import (
+    "net/http"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func (suite *IntegrationTestSuite) TestReviewSLAEscalation() {
+    t := suite.T()
+    receiver := suite.startWebhookReceiver()
+    defer receiver.server.Close()
+
+    status, response := suite.postJSON("/webhooks/subscriptions/add", map[string]interface{}{
+        "url":    receiver.URL(),
+        "events": []string{"review.escalated", "reviewer.replaced"},
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    subID := response["subscription"].(map[string]interface{})["subscription_id"]
+    defer suite.postJSON("/webhooks/subscriptions/delete", map[string]interface{}{"subscription_id": subID})
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "sla_team",
+        "members": []map[string]interface{}{
+            {"user_id": "sla_u1", "username": "Author", "is_active": true},
+            {"user_id": "sla_u2", "username": "Reviewer 2", "is_active": true},
+            {"user_id": "sla_u3", "username": "Reviewer 3", "is_active": true},
+            {"user_id": "sla_u4", "username": "Reviewer 4", "is_active": true},
+        },
+    })
+
+    status, _ = suite.postJSON("/team/settings", map[string]interface{}{"team_name": "sla_team", "review_sla_seconds": -1})
+    assert.Equal(t, http.StatusBadRequest, status)
+
+    status, response = suite.postJSON("/team/settings", map[string]interface{}{
+        "team_name":          "sla_team",
+        "reviewers_count":    1,
+        "review_sla_seconds": 1,
+        "sla_auto_reassign":  true,
+    })
+    assert.Equal(t, http.StatusOK, status)
+    settings := response["settings"].(map[string]interface{})
+    assert.Equal(t, float64(1), settings["review_sla_seconds"])
+    assert.Equal(t, true, settings["sla_auto_reassign"])
+
+    reviewerOf := map[string]string{}
+    for _, prID := range []string{"sla_pr_stale", "sla_pr_reviewed"} {
+        status, response = suite.postJSON("/pullRequest/create", map[string]interface{}{
+            "pull_request_id":   prID,
+            "pull_request_name": "SLA check",
+            "author_id":         "sla_u1",
+        })
+        assert.Equal(t, http.StatusCreated, status)
+        reviewerOf[prID] = asSlice(response["pr"].(map[string]interface{})["assigned_reviewers"])[0].(string)
+    }
+    // Без этого новые ревьюверы тоже будут эскалироваться каждую секунду
+    defer suite.postJSON("/team/settings", map[string]interface{}{"team_name": "sla_team", "review_sla_seconds": 0})
+
+    status, _ = suite.postJSON("/pullRequest/review", map[string]interface{}{
+        "pull_request_id": "sla_pr_reviewed",
+        "reviewer_id":     reviewerOf["sla_pr_reviewed"],
+        "decision":        "APPROVED",
+    })
+    assert.Equal(t, http.StatusOK, status)
+
+    escalated := receiver.waitFor("review.escalated", "sla_pr_stale", 1)
+    if assert.NotEmpty(t, escalated) {
+        data := escalated[0].Payload.Data
+        assert.Equal(t, reviewerOf["sla_pr_stale"], data.ReviewerID)
+        assert.NotEmpty(t, data.ReplacedBy)
+        assert.NotEqual(t, data.ReviewerID, data.ReplacedBy)
+
+        replaced := receiver.waitFor("reviewer.replaced", "sla_pr_stale", 1)
+        if assert.NotEmpty(t, replaced) {
+            assert.Equal(t, data.ReviewerID, replaced[0].Payload.Data.OldReviewerID)
+            assert.Equal(t, data.ReplacedBy, replaced[0].Payload.Data.NewReviewerID)
+        }
+    }
+
+    assert.Empty(t, receiver.waitFor("review.escalated", "sla_pr_reviewed", 0), "reviews with a decision must not be escalated")
+}
@@ -0,0 +1,277 @@
+package integration
+
+import (
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net"
+    "net/http"
+    "net/http/httptest"
+    "sync"
+    "sync/atomic"
+    "time"
+
+    "github.com/stretchr/testify/assert"
+)
+
+type receivedWebhook struct {
+    Event     string
+    Signature string
+    Body      []byte
+    Payload   struct {
+        ID   string `json:"id"`
+        Type string `json:"type"`
+        Data struct {
+            PullRequest struct {
+                PullRequestID string `json:"pull_request_id"`
+                Status        string `json:"status"`
+            } `json:"pull_request"`
+            ReviewerID    string `json:"reviewer_id"`
+            OldReviewerID string `json:"old_reviewer_id"`
+            NewReviewerID string `json:"new_reviewer_id"`
+            ReplacedBy    string `json:"replaced_by"`
+            Actor         string `json:"actor"`
+        } `json:"data"`
+    }
+}
+
+// webhookReceiver принимает вебхуки сервиса; сервис должен иметь доступ к WEBHOOK_RECEIVER_HOST
+type webhookReceiver struct {
+    server   *httptest.Server
+    failing  atomic.Bool
+    mu       sync.Mutex
+    received []receivedWebhook
+}
+
+func (suite *IntegrationTestSuite) startWebhookReceiver() *webhookReceiver {
+    listener, err := net.Listen("tcp", ":0")
+    if err != nil {
+        suite.T().Fatalf("failed to start webhook receiver: %v", err)
+    }
+
+    r := &webhookReceiver{}
+    r.server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+        if r.failing.Load() {
+            w.WriteHeader(http.StatusInternalServerError)
+            return
+        }
+
+        body, _ := io.ReadAll(req.Body)
+        hook := receivedWebhook{
+            Event:     req.Header.Get("X-Reviewer-Event"),
+            Signature: req.Header.Get("X-Reviewer-Signature-256"),
+            Body:      body,
+        }
+        json.Unmarshal(body, &hook.Payload)
+
+        r.mu.Lock()
+        r.received = append(r.received, hook)
+        r.mu.Unlock()
+        w.WriteHeader(http.StatusNoContent)
+    }))
+    r.server.Listener.Close()
+    r.server.Listener = listener
+    r.server.Start()
+
+    return r
+}
+
+func (r *webhookReceiver) URL() string {
+    port := r.server.Listener.Addr().(*net.TCPAddr).Port
+    return fmt.Sprintf("http://%s:%d", getEnv("WEBHOOK_RECEIVER_HOST", "localhost"), port)
+}
+
+func (r *webhookReceiver) waitFor(eventType, prID string, count int) []receivedWebhook {
+    deadline := time.Now().Add(10 * time.Second)
+    for {
+        var matched []receivedWebhook
+        r.mu.Lock()
+        for _, hook := range r.received {
+            if hook.Event == eventType && hook.Payload.Data.PullRequest.PullRequestID == prID {
+                matched = append(matched, hook)
+            }
+        }
+        r.mu.Unlock()
+
+        if len(matched) >= count || time.Now().After(deadline) {
+            return matched
+        }
+        time.Sleep(50 * time.Millisecond)
+    }
+}
+
+func (suite *IntegrationTestSuite) waitForDeliveries(path string, check func([]interface{}) bool) []interface{} {
+    deadline := time.Now().Add(10 * time.Second)
+    for {
+        _, response := suite.getJSON(path)
+        deliveries := asSlice(response["deliveries"])
+        if check(deliveries) || time.Now().After(deadline) {
+            return deliveries
+        }
+        time.Sleep(100 * time.Millisecond)
+    }
+}
+
+func (suite *IntegrationTestSuite) TestOutboundWebhooks() {
+    t := suite.T()
+    receiver := suite.startWebhookReceiver()
+    defer receiver.server.Close()
+
+    status, response := suite.postJSON("/webhooks/subscriptions/add", map[string]interface{}{
+        "url":    "ftp://example.com",
+        "events": []string{"reviewer.assigned"},
+    })
+    assert.Equal(t, http.StatusBadRequest, status)
+
+    status, response = suite.postJSON("/webhooks/subscriptions/add", map[string]interface{}{
+        "url":    receiver.URL(),
+        "secret": "hook-secret",
+        "events": []string{"reviewer.assigned", "reviewer.replaced", "pr.merged"},
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    sub := response["subscription"].(map[string]interface{})
+    subID := sub["subscription_id"].(string)
+    assert.Equal(t, "hook-secret", sub["secret"])
+    defer suite.postJSON("/webhooks/subscriptions/delete", map[string]interface{}{"subscription_id": subID})
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "hooks_team",
+        "members": []map[string]interface{}{
+            {"user_id": "hook_u1", "username": "Hook Author", "is_active": true},
+            {"user_id": "hook_u2", "username": "Hook User 2", "is_active": true},
+            {"user_id": "hook_u3", "username": "Hook User 3", "is_active": true},
+            {"user_id": "hook_u4", "username": "Hook User 4", "is_active": true},
+        },
+    })
+
+    status, response = suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "hook_pr_1",
+        "pull_request_name": "Webhook PR",
+        "author_id":         "hook_u1",
+    })
+    assert.Equal(t, http.StatusCreated, status)
+    reviewers := asSlice(response["pr"].(map[string]interface{})["assigned_reviewers"])
+
+    assigned := receiver.waitFor("reviewer.assigned", "hook_pr_1", 2)
+    assert.Len(t, assigned, 2)
+    for _, hook := range assigned {
+        mac := hmac.New(sha256.New, []byte("hook-secret"))
+        mac.Write(hook.Body)
+        assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), hook.Signature)
+        assert.Contains(t, reviewers, hook.Payload.Data.ReviewerID)
+    }
+
+    status, response = suite.postJSON("/pullRequest/reassign", map[string]interface{}{
+        "pull_request_id": "hook_pr_1",
+        "old_user_id":     reviewers[0],
+    })
+    assert.Equal(t, http.StatusOK, status)
+
+    replaced := receiver.waitFor("reviewer.replaced", "hook_pr_1", 1)
+    if assert.Len(t, replaced, 1) {
+        assert.Equal(t, reviewers[0], replaced[0].Payload.Data.OldReviewerID)
+        assert.Equal(t, response["replaced_by"], replaced[0].Payload.Data.NewReviewerID)
+    }
+
+    suite.postJSON("/pullRequest/merge", map[string]interface{}{"pull_request_id": "hook_pr_1"})
+    merged := receiver.waitFor("pr.merged", "hook_pr_1", 1)
+    if assert.Len(t, merged, 1) {
+        assert.Equal(t, "MERGED", merged[0].Payload.Data.PullRequest.Status)
+        assert.Equal(t, "admin", merged[0].Payload.Data.Actor)
+    }
+
+    // Повторный merge ничего не меняет и не должен порождать событие
+    suite.postJSON("/pullRequest/merge", map[string]interface{}{"pull_request_id": "hook_pr_1"})
+    suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "hook_pr_2",
+        "pull_request_name": "Webhook PR 2",
+        "author_id":         "hook_u1",
+    })
+    receiver.waitFor("reviewer.assigned", "hook_pr_2", 2)
+    assert.Len(t, receiver.waitFor("pr.merged", "hook_pr_1", 1), 1)
+
+    _, response = suite.getJSON("/webhooks/subscriptions/list")
+    for _, s := range asSlice(response["subscriptions"]) {
+        assert.Nil(t, s.(map[string]interface{})["secret"])
+    }
+
+    deliveries := suite.waitForDeliveries("/webhooks/deliveries?subscription_id="+subID, func(deliveries []interface{}) bool {
+        return len(deliveriesFor(deliveries, "hook_pr_1")) == 4
+    })
+    deliveries = deliveriesFor(deliveries, "hook_pr_1")
+    assert.Len(t, deliveries, 4)
+    for _, d := range deliveries {
+        assert.Equal(t, "DELIVERED", d.(map[string]interface{})["status"])
+    }
+}
+
+func deliveriesFor(deliveries []interface{}, prID string) []interface{} {
+    var matched []interface{}
+    for _, d := range deliveries {
+        payload := d.(map[string]interface{})["payload"].(map[string]interface{})
+        pr := payload["data"].(map[string]interface{})["pull_request"].(map[string]interface{})
+        if pr["pull_request_id"] == prID {
+            matched = append(matched, d)
+        }
+    }
+    return matched
+}
+
+func (suite *IntegrationTestSuite) TestWebhookDeadLetters() {
+    t := suite.T()
+    receiver := suite.startWebhookReceiver()
+    defer receiver.server.Close()
+    receiver.failing.Store(true)
+
+    _, response := suite.postJSON("/webhooks/subscriptions/add", map[string]interface{}{
+        "url":    receiver.URL(),
+        "events": []string{"pr.closed"},
+    })
+    subID := response["subscription"].(map[string]interface{})["subscription_id"].(string)
+    defer suite.postJSON("/webhooks/subscriptions/delete", map[string]interface{}{"subscription_id": subID})
+
+    suite.postJSON("/team/add", map[string]interface{}{
+        "team_name": "dead_letter_team",
+        "members": []map[string]interface{}{
+            {"user_id": "dl_u1", "username": "Dead Letter Author", "is_active": true},
+            {"user_id": "dl_u2", "username": "Dead Letter User 2", "is_active": true},
+        },
+    })
+    suite.postJSON("/pullRequest/create", map[string]interface{}{
+        "pull_request_id":   "dl_pr_1",
+        "pull_request_name": "Dead Letter PR",
+        "author_id":         "dl_u1",
+    })
+    suite.postJSON("/pullRequest/close", map[string]interface{}{"pull_request_id": "dl_pr_1"})
+
+    // Тест рассчитан на короткие повторы: WEBHOOK_RETRY_BASE=100ms, WEBHOOK_MAX_ATTEMPTS=3
+    dead := suite.waitForDeliveries("/webhooks/deadLetters?subscription_id="+subID, func(deliveries []interface{}) bool {
+        return len(deliveriesFor(deliveries, "dl_pr_1")) == 1
+    })
+    dead = deliveriesFor(dead, "dl_pr_1")
+    if !assert.Len(t, dead, 1) {
+        return
+    }
+    delivery := dead[0].(map[string]interface{})
+    assert.Equal(t, "pr.closed", delivery["event_type"])
+    assert.Equal(t, float64(500), delivery["last_status_code"])
+    assert.NotEmpty(t, delivery["last_error"])
+
+    receiver.failing.Store(false)
+    status, response := suite.postJSON("/webhooks/deliveries/replay", map[string]interface{}{
+        "delivery_id": delivery["delivery_id"],
+    })
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, "PENDING", response["delivery"].(map[string]interface{})["status"])
+
+    assert.Len(t, receiver.waitFor("pr.closed", "dl_pr_1", 1), 1)
+
+    status, response = suite.postJSON("/webhooks/deliveries/replay", map[string]interface{}{
+        "delivery_id": delivery["delivery_id"],
+    })
+    assert.Equal(t, http.StatusConflict, status)
+    assert.Equal(t, "NOT_DEAD_LETTER", errorCode(response))
+}